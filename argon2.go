@@ -1,5 +1,6 @@
 // Package argon2 provides a convenience wrapper around Go's argon2 package.
-// Currently supported only Argon2id.
+// Argon2id is used by default, Argon2d is supported for non-interactive
+// key derivation and Argon2i for interoperability.
 // Argon2 was the winner of the Password Hashing Competition
 // that makes it easier to securely derive strong keys from weak
 // inputs (i.e. user passwords).
@@ -10,11 +11,9 @@ package argon2
 import (
 	"crypto/rand"
 	"crypto/subtle"
-	"encoding/base64"
 	"errors"
-	"fmt"
-	"strconv"
-	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/crypto/argon2"
 )
@@ -24,6 +23,18 @@ const (
 	minMemoryValue = 8 * 1024 // the minimum allowed memory amount
 	minKeyLength   = 16       // the minimum derived key length in bytes
 	minSaltLength  = 8        // the minimum allowed salt length in bytes
+	minTagLength   = 4        // the minimum derived key length of the spec, accepted in existing hashes
+
+	maxMemoryValue = 4 * 1024 * 1024 // the maximum allowed memory amount, 4 GiB
+	maxKeyLength   = 1024            // the maximum derived key length in bytes
+	maxSaltLength  = 1024            // the maximum allowed salt length in bytes
+)
+
+// Thresholds of the warnings reported by Params.Warnings.
+const (
+	minMemoryCost        = 30 * 1024  // the recommended minimum of memory times iterations (KiB)
+	maxConstrainedMemory = 256 * 1024 // the memory above which constrained environments run out of it (KiB)
+	recSaltLength        = 16         // the recommended salt length in bytes
 )
 
 // Params describes the input parameters to the argon2 key derivation function.
@@ -34,16 +45,46 @@ const (
 // increased as memory latency and CPU parallelism increases. Remember to get a
 // good random salt.
 type Params struct {
-	Memory      uint32 // The amount of memory used by the algorithm (kibibytes)
-	Iterations  uint32 // The number of iterations (passes) over the memory
-	Parallelism uint8  // The number of threads (lanes) used by the algorithm
-	SaltLength  uint32 // Length of the random salt. 16 bytes is recommended for password hashing
-	KeyLength   uint32 // Length of the generated key (password hash). 16 bytes or more is recommended
+	Memory        uint32        // The amount of memory used by the algorithm (kibibytes)
+	Iterations    uint32        // The number of iterations (passes) over the memory
+	Parallelism   uint8         // The number of threads (lanes) used by the algorithm
+	SaltLength    uint32        // Length of the random salt. 16 bytes is recommended for password hashing
+	KeyLength     uint32        // Length of the generated key (password hash). 16 bytes or more is recommended
+	Format        Format        // Encoding of the generated hash. The zero value is FormatLegacy
+	Variant       Variant       // The argon2 variant used to derive the key. The zero value is Argon2id
+	Version       uint32        // The argon2 version. The zero value is the current version (0x13)
+	URLSafe       bool          // Encode the salt and key with URL-safe Base64 instead of the standard alphabet
+	KeyID         string        // Identifies the secret key (K) the key is derived with, if any. Only encoded in the PHC formats, as "keyid"
+	Data          string        // Associated data (X) the key is bound to, if any, e.g. a user ID. Only encoded in the PHC formats, as "data"
+	PreHash       PreHash       // Function the password is pre-hashed with before deriving the key, if any. Only encoded in the PHC formats, as "ph"
+	Normalization Normalization // Unicode normalization form of the password before deriving the key, if any. Only encoded in the PHC formats, as "norm"
+}
+
+// NewParams returns Argon2id parameters with the given costs and lengths,
+// after validating them with Check, so invalid configurations are caught at
+// construction rather than at the first hash.
+func NewParams(memory, iterations uint32, parallelism uint8, saltLength, keyLength uint32) (*Params, error) {
+	p := &Params{
+		Memory:      memory,
+		Iterations:  iterations,
+		Parallelism: parallelism,
+		SaltLength:  saltLength,
+		KeyLength:   keyLength,
+	}
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
 }
 
 // DefaultParams provides sensible default inputs into
 // the argon2 function for interactive use.
 // The default key length is 256 bits.
+//
+// DefaultParams is shared by every importer of the package, so mutating it
+// is deprecated: use Defaults to get a copy to modify instead. The defaults
+// the package falls back to, e.g. for nil params, are unaffected by it.
 var DefaultParams = &Params{
 	Memory:      64 * 1024,
 	Iterations:  3,
@@ -52,6 +93,51 @@ var DefaultParams = &Params{
 	KeyLength:   32,
 }
 
+// builtinDefaults is the copy of DefaultParams made before any importer
+// could modify it, which SetDefaultParams(nil) restores.
+var builtinDefaults = *DefaultParams
+
+var (
+	defaultsMu    sync.RWMutex
+	defaultParams = builtinDefaults // the defaults the package falls back to, e.g. for nil params
+)
+
+// Defaults returns a copy of the default parameters, as set by
+// SetDefaultParams, which the caller is free to modify.
+func Defaults() *Params {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return defaultParams.Clone()
+}
+
+// SetDefaultParams replaces the default parameters returned by Defaults and
+// used for nil params, e.g. at startup, after validating them with Check.
+// A nil p restores the built-in defaults. DefaultParams itself is left as is.
+// It is safe for concurrent use.
+func SetDefaultParams(p *Params) error {
+	if p == nil {
+		p = &builtinDefaults
+	}
+	if err := p.Check(); err != nil {
+		return err
+	}
+
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultParams = *p
+
+	return nil
+}
+
+// Clone returns a copy of the parameters, or nil if p is nil.
+func (p *Params) Clone() *Params {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
 // ErrInvalidHash is returned when function failed to parse
 // provided argon2 hash and/or given parameters.
 var ErrInvalidHash = errors.New("argon2: the encoded hash is not in the correct format")
@@ -61,38 +147,246 @@ var ErrInvalidHash = errors.New("argon2: the encoded hash is not in the correct
 var ErrInvalidParams = errors.New("argon2: the parameters provided are invalid")
 
 // ErrIncompatibleVersion is returned when version of provided argon2 hash
-// s incompatible with current argon2 algorithm
+// is incompatible with current argon2 algorithm. Hashes of version 0x10
+// are still verified, but new hashes are always generated with version 0x13
 var ErrIncompatibleVersion = errors.New("argon2: incompatible version of argon2")
 
+// ErrUnknownAlgorithm is returned when the algorithm identifier of the
+// provided hash, or the variant of the given parameters, is not supported.
+var ErrUnknownAlgorithm = errors.New("argon2: unknown algorithm identifier")
+
+// ErrUnsupportedParams is returned when the provided hash uses optional
+// parameters, such as a secret key id or associated data, which can't be
+// provided to verify it.
+var ErrUnsupportedParams = errors.New("argon2: the encoded hash uses unsupported parameters")
+
+// ErrUnsupportedFormat is returned when the requested hash encoding
+// format is not supported.
+var ErrUnsupportedFormat = errors.New("argon2: unsupported hash format")
+
+// HashError describes why an encoded hash failed to decode: the field that
+// failed, the sentinel error, e.g. ErrInvalidHash or ErrIncompatibleVersion,
+// and the underlying strconv or base64 error, if any. errors.Is matches both
+// the sentinel and the cause, and errors.As extracts either.
+type HashError struct {
+	Field string // The field that failed to decode, e.g. "memory" or "salt", if known
+	Err   error  // The sentinel error
+	Cause error  // The underlying error, if any
+}
+
+func (e *HashError) Error() string {
+	s := e.Err.Error()
+	if e.Field != "" {
+		s += ": " + e.Field
+	}
+	if e.Cause != nil {
+		s += ": " + e.Cause.Error()
+	}
+	return s
+}
+
+// Unwrap returns the sentinel error and the cause, if any.
+func (e *HashError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Cause}
+}
+
+// paramsError is an error of a single parameter. It wraps ErrInvalidParams,
+// so errors.Is(err, ErrInvalidParams) reports any invalid parameter.
+type paramsError string
+
+func (e paramsError) Error() string { return string(e) }
+
+// Unwrap returns ErrInvalidParams.
+func (e paramsError) Unwrap() error { return ErrInvalidParams }
+
+// Errors of the individual parameters, reported by Check. They all wrap
+// ErrInvalidParams.
+var (
+	// ErrMemoryTooSmall is returned when the memory is below 8 MiB, or
+	// below 8 KiB per lane in decoded hashes.
+	ErrMemoryTooSmall error = paramsError("argon2: the memory parameter is too small")
+	// ErrMemoryTooLarge is returned when the memory exceeds 4 GiB,
+	// which is far beyond any sensible password hashing configuration.
+	ErrMemoryTooLarge error = paramsError("argon2: the memory parameter is too large")
+	// ErrIterationsTooSmall is returned when there are no iterations.
+	ErrIterationsTooSmall error = paramsError("argon2: the iterations parameter is too small")
+	// ErrParallelismTooSmall is returned when there are no lanes.
+	ErrParallelismTooSmall error = paramsError("argon2: the parallelism parameter is too small")
+	// ErrSaltTooShort is returned when the salt length is below 8 bytes.
+	ErrSaltTooShort error = paramsError("argon2: the salt length is too short")
+	// ErrSaltTooLong is returned when the salt length exceeds 1024 bytes.
+	ErrSaltTooLong error = paramsError("argon2: the salt length is too long")
+	// ErrKeyTooShort is returned when the derived key length is below 16 bytes.
+	ErrKeyTooShort error = paramsError("argon2: the key length is too short")
+	// ErrKeyTooLong is returned when the derived key length exceeds 1024 bytes.
+	ErrKeyTooLong error = paramsError("argon2: the key length is too long")
+)
+
 // ErrMismatchedHashAndPassword is returned when a password (hashed) and
 // given hash do not match.
 var ErrMismatchedHashAndPassword = errors.New("argon2: the hashed password does not match the hash of the given password")
 
 // GenerateFromPassword returns the derived key of the password using the
-// parameters provided. The parameters are prepended to the derived key and
-// separated by the "$" character
+// parameters provided, or the Defaults if p is nil. The parameters are
+// prepended to the derived key and separated by the "$" character, following
+// the encoding chosen by p.Format. Passwords longer than the limit set with
+// SetMaxPasswordLength are rejected with a PasswordLengthError.
 func GenerateFromPassword(password []byte, p *Params) ([]byte, error) {
-	// Generate a cryptographically secure random salt
-	salt, err := GenerateRandomBytes(p.SaltLength)
+	return AppendHash(nil, password, p)
+}
+
+// AppendHash appends the derived key of the password, encoded the same way as
+// GenerateFromPassword does, to dst and returns the extended buffer.
+// Reusing dst across calls avoids allocating a new output for every hash.
+func AppendHash(dst, password []byte, p *Params) ([]byte, error) {
+	return appendHash(dst, password, nil, p)
+}
+
+// GenerateFromPasswordWithSecret is like GenerateFromPassword, but also
+// mixes the secret key into the derivation, as the secret input (K) of argon2.
+// The secret isn't part of the encoded hash, and the same secret must be
+// passed to CompareHashAndPasswordWithSecret to verify it.
+func GenerateFromPasswordWithSecret(password, secret []byte, p *Params) ([]byte, error) {
+	return appendHash(nil, password, secret, p)
+}
+
+func appendHash(dst, password, secret []byte, p *Params) ([]byte, error) {
+	if err := checkNewPassword(password); err != nil {
+		return nil, err
+	}
+
+	return appendDerived(dst, password, secret, p)
+}
+
+// appendDerived is like appendHash, but for inputs that aren't the password
+// itself, e.g. a peppered password or a legacy hash, which the minimum
+// length and the policy don't apply to.
+func appendDerived(dst, password, secret []byte, p *Params) ([]byte, error) {
+	p = orDefault(p)
+	salt, key, err := generateRaw(password, secret, p)
 	if err != nil {
 		return nil, err
 	}
 
+	return appendEncoded(dst, p.Format, p, salt, key), nil
+}
+
+// GenerateRaw returns a freshly generated random salt and the key derived
+// from the password and that salt using the parameters provided, without
+// encoding them. It is useful for storing the components in binary form,
+// which can be checked later with VerifyRaw.
+func GenerateRaw(password []byte, p *Params) (salt, key []byte, err error) {
+	if err := checkNewPassword(password); err != nil {
+		return nil, nil, err
+	}
+
+	return generateRaw(password, nil, p)
+}
+
+func generateRaw(password, secret []byte, p *Params) (salt, key []byte, err error) {
+	p = orDefault(p)
 	if err := p.Check(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	if err := checkPassword(password); err != nil {
+		return nil, nil, err
+	}
+
+	// Generate a cryptographically secure random salt
+	salt, err = GenerateRandomBytes(p.SaltLength)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Pass the byte array password, salt and parameters to the key derivation
+	// function of the chosen argon2 variant (Argon2id by default).
+	key = p.Variant.deriveKey(password, salt, secret, p)
+
+	return salt, key, nil
+}
 
-	// Pass the byte array password, salt and parameters to the argon2.IDKey
-	// function. This will generate a hash of the password using the Argon2id variation.
-	key := argon2.IDKey(password, salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+// VerifyRaw compares a raw derived key and salt, as returned by GenerateRaw,
+// with the possible cleartext equivalent using the parameters provided.
+// The length of the derived key is the one of key, p.KeyLength is ignored.
+// The comparison performed by this function is constant-time. It returns nil
+// on success, ErrMismatchedHashAndPassword if the derived keys do not match,
+// and ErrInvalidParams for an empty key.
+func VerifyRaw(password, salt, key []byte, p *Params) error {
+	defer padVerify(time.Now())
+
+	return verifyRaw(password, salt, key, nil, p)
+}
+
+// VerifyRawWithSecret is like VerifyRaw, but for keys derived with a secret
+// key, e.g. by Konscious.Security.Cryptography's Argon2id with KnownSecret set.
+func VerifyRawWithSecret(password, salt, key, secret []byte, p *Params) error {
+	defer padVerify(time.Now())
+
+	return verifyRaw(password, salt, key, secret, p)
+}
+
+func verifyRaw(password, salt, key, secret []byte, p *Params) error {
+	if err := checkPassword(password); err != nil {
+		return err
+	}
+	p = orDefault(p)
+	if err := p.checkLimits(); err != nil {
+		return err
+	}
+	if _, ok := variantNames[p.Variant]; !ok {
+		return ErrUnknownAlgorithm
+	}
+	if err := p.checkNormalization(); err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return ErrInvalidParams
+	}
 
-	// Encode salt and hashed password to Base64
-	b64Salt := base64.RawStdEncoding.EncodeToString(salt)
-	b64Hash := base64.RawStdEncoding.EncodeToString(key)
+	params := *p
+	params.KeyLength = uint32(len(key))
+	otherKey := p.Variant.deriveKey(password, salt, secret, &params)
+	if subtle.ConstantTimeCompare(key, otherKey) == 1 {
+		return nil
+	}
 
-	// Prepend the params and the salt to the derived key,
-	// each separated by a "$" character.
-	return []byte(fmt.Sprintf("argon2id$%d$%d$%d$%d$%s$%s", argon2.Version, p.Memory, p.Iterations, p.Parallelism, b64Salt, b64Hash)), nil
+	return ErrMismatchedHashAndPassword
+}
+
+// checkInputs returns an error if the hash with the decoded parameters p
+// can't be verified with the given secret and associated data: it exceeds
+// the limits set with SetVerifyLimits, it was derived with a secret key, as
+// recorded by its key ID, but no secret is given, or it is bound to other
+// associated data than the expected one. A nil data accepts only the hashes
+// without associated data.
+func (p *Params) checkInputs(secret, data []byte) error {
+	if err := p.checkLimits(); err != nil {
+		return err
+	}
+	if p.KeyID != "" && len(secret) == 0 {
+		return &HashError{Field: "keyid", Err: ErrUnsupportedParams}
+	}
+	if err := p.checkNormalization(); err != nil {
+		return &HashError{Field: "norm", Err: err}
+	}
+	if p.Data != string(data) {
+		if data == nil {
+			return &HashError{Field: "data", Err: ErrUnsupportedParams}
+		}
+		return ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+// orDefault returns p, or the Defaults if p is nil.
+func orDefault(p *Params) *Params {
+	if p == nil {
+		return Defaults()
+	}
+	return p
 }
 
 // GenerateRandomBytes returns securely generated random bytes.
@@ -111,109 +405,248 @@ func GenerateRandomBytes(n uint32) ([]byte, error) {
 // CompareHashAndPassword compares a derived key with the possible cleartext
 // equivalent. The parameters used in the provided derived key are used.
 // The comparison performed by this function is constant-time. It returns nil
-// on success, and an error if the derived keys do not match.
+// on success, and an error if the derived keys do not match. Like
+// GenerateFromPassword, it rejects passwords longer than the length limit.
 func CompareHashAndPassword(hash, password []byte) error {
-	// Decode existing hash, retrieve params and salt.
-	p, salt, hash, err := decodeHash(hash)
-	if err != nil {
-		return err
-	}
+	_, err := CompareHashAndPasswordBuf(nil, hash, password)
+	return err
+}
 
-	// hashing the cleartext password with the same parameters and salt
-	otherHash := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+// CompareHashAndPasswordBuf is like CompareHashAndPassword, but decodes the
+// salt and derived key of the provided hash into buf, growing it if needed.
+// The returned buffer can be passed to the next call, so high-throughput
+// callers can reuse it instead of allocating scratch space for every compare.
+func CompareHashAndPasswordBuf(buf, hash, password []byte) ([]byte, error) {
+	defer padVerify(time.Now())
 
-	// Check that the contents of the hashed passwords are identical. Note
-	// that we are using the subtle.ConstantTimeCompare() function for this
-	// to help prevent timing attacks.
-	if subtle.ConstantTimeCompare(hash, otherHash) == 1 {
-		return nil
-	}
+	return compareHashAndPassword(buf, hash, password, nil)
+}
 
-	return ErrMismatchedHashAndPassword
+// CompareHashAndPasswordWithSecret is like CompareHashAndPassword, but for
+// hashes derived with a secret key, e.g. by GenerateFromPasswordWithSecret
+// or by the Ruby argon2 gem's "secret" option.
+func CompareHashAndPasswordWithSecret(hash, password, secret []byte) error {
+	defer padVerify(time.Now())
+
+	_, err := compareHashAndPassword(nil, hash, password, secret)
+	return err
 }
 
-// decodeHash extracts the parameters, salt and derived key from the
-// provided hash. It returns an error if the hash format is invalid and/or
-// the parameters are invalid.
-func decodeHash(encodedHash []byte) (p *Params, salt, hash []byte, err error) {
-	vals := strings.Split(string(encodedHash), "$")
+// CompareHashAndPasswordWithData is like CompareHashAndPassword, but for
+// hashes bound to associated data, e.g. generated with WithAssociatedData.
+// The data is the expected one, e.g. the ID of the user whose hash is
+// verified, so a hash copied from another account is reported as
+// ErrMismatchedHashAndPassword, as are the hashes without associated data.
+func CompareHashAndPasswordWithData(hash, password, data []byte) error {
+	defer padVerify(time.Now())
 
-	if len(vals) != 7 {
-		return nil, nil, nil, ErrInvalidHash
+	if data == nil {
+		data = []byte{}
 	}
 
-	// Check argon2 version
-	version, err := strconv.Atoi(vals[1])
+	p, salt, key, err := decodeHash(hash)
 	if err != nil {
-		return nil, nil, nil, ErrInvalidHash
+		return err
 	}
-	if version != argon2.Version {
-		return nil, nil, nil, ErrIncompatibleVersion
+	if err := p.checkInputs(nil, data); err != nil {
+		return err
 	}
-
-	// Parsing parameters
-	p = &Params{}
-
-	memory, err := strconv.Atoi(vals[2])
-	if err != nil {
-		return nil, nil, nil, ErrInvalidHash
+	if err := verifyRaw(password, salt, key, nil, p); err != nil {
+		return err
 	}
-	p.Memory = uint32(memory)
+	notifyWeakHash(p)
+	return nil
+}
 
-	iterations, err := strconv.Atoi(vals[3])
-	if err != nil {
-		return nil, nil, nil, ErrInvalidHash
+// Verify is like CompareHashAndPassword, but reports whether the password
+// matches the hash as a bool, reserving the error for invalid hashes and
+// other failures. A mismatch is reported as false and a nil error.
+func Verify(hash, password []byte) (bool, error) {
+	err := CompareHashAndPassword(hash, password)
+	switch err {
+	case nil:
+		return true, nil
+	case ErrMismatchedHashAndPassword:
+		return false, nil
+	default:
+		return false, err
 	}
-	p.Iterations = uint32(iterations)
+}
 
-	parallelism, err := strconv.Atoi(vals[4])
-	if err != nil {
-		return nil, nil, nil, ErrInvalidHash
+func compareHashAndPassword(buf, hash, password, secret []byte) ([]byte, error) {
+	if err := checkPassword(password); err != nil {
+		return buf, err
 	}
-	p.Parallelism = uint8(parallelism)
 
-	salt, err = base64.RawStdEncoding.DecodeString(vals[5])
+	// Decode existing hash, retrieve params and salt.
+	p, salt, hash, err := decodeHashInto(buf, trimEncoderID(hash), ParseStrict)
 	if err != nil {
-		return nil, nil, nil, ErrInvalidHash
+		return buf, err
+	}
+	if err := p.checkInputs(secret, nil); err != nil {
+		return buf, err
 	}
-	p.SaltLength = uint32(len(salt))
+	buf = salt[:0]
 
-	hash, err = base64.RawStdEncoding.DecodeString(vals[6])
-	if err != nil {
-		return nil, nil, nil, ErrInvalidHash
+	// hashing the cleartext password with the same variant, parameters and salt
+	otherHash := p.Variant.deriveKey(password, salt, secret, p)
+
+	// Check that the contents of the hashed passwords are identical. Note
+	// that we are using the subtle.ConstantTimeCompare() function for this
+	// to help prevent timing attacks.
+	if subtle.ConstantTimeCompare(hash, otherHash) == 1 {
+		notifyWeakHash(p)
+		return buf, nil
 	}
-	p.KeyLength = uint32(len(hash))
 
-	return p, salt, hash, nil
+	return buf, ErrMismatchedHashAndPassword
 }
 
 // Check checks that the parameters are valid for input into the
-// argon2 key derivation function.
+// argon2 key derivation function, and meet the floors of the validator
+// installed with SetValidator, if any. All violations are reported, joined
+// with errors.Join if there are several of them, so they can be matched
+// with errors.Is. Valid, but questionable parameters aren't rejected,
+// they are reported by Warnings instead.
 func (p *Params) Check() error {
+	errs := p.check()
+	if v := currentValidator(); v != nil {
+		errs = append(errs, v.validate(p)...)
+	}
+
+	return joinErrors(errs)
+}
+
+// extended reports whether the parameters carry a key ID, associated data,
+// a pre-hash function or a normalization, which only the PHC formats record.
+func (p *Params) extended() bool {
+	return p.KeyID != "" || p.Data != "" || p.PreHash != NoPreHash || p.Normalization != NoNormalization
+}
+
+// check returns the violations of the parameters for input into the
+// argon2 key derivation function.
+func (p *Params) check() []error {
+	var errs []error
+
 	// Validate Memory
 	if p.Memory < minMemoryValue {
-		return ErrInvalidParams
+		errs = append(errs, ErrMemoryTooSmall)
+	}
+	if p.Memory > maxMemoryValue {
+		errs = append(errs, ErrMemoryTooLarge)
 	}
 
 	// Validate Iterations
 	if p.Iterations < 1 {
-		return ErrInvalidParams
+		errs = append(errs, ErrIterationsTooSmall)
 	}
 
-	// Validate Parallelism
+	// Validate Parallelism. The maximum of 2^24-1 lanes is beyond the range
+	// of the field, and the minimum memory covers the 8 blocks per lane the
+	// spec requires for any of them
 	if p.Parallelism < 1 {
-		return ErrInvalidParams
+		errs = append(errs, ErrParallelismTooSmall)
 	}
 
 	// Validate salt length
 	if p.SaltLength < minSaltLength {
-		return ErrInvalidParams
+		errs = append(errs, ErrSaltTooShort)
+	}
+	if p.SaltLength > maxSaltLength {
+		errs = append(errs, ErrSaltTooLong)
 	}
 
 	// Validate key length
 	if p.KeyLength < minKeyLength {
-		return ErrInvalidParams
+		errs = append(errs, ErrKeyTooShort)
+	}
+	if p.KeyLength > maxKeyLength {
+		errs = append(errs, ErrKeyTooLong)
 	}
 
-	return nil
+	// Validate variant
+	if _, ok := variantNames[p.Variant]; !ok {
+		errs = append(errs, ErrUnknownAlgorithm)
+	}
+
+	// Validate version, older versions are only supported for verifying
+	// existing hashes
+	if p.Version != 0 && p.Version != argon2.Version {
+		errs = append(errs, ErrIncompatibleVersion)
+	}
+
+	// Validate output format
+	if !p.Format.valid() {
+		errs = append(errs, ErrUnsupportedFormat)
+	} else if p.extended() && p.Format == FormatLegacy {
+		errs = append(errs, ErrUnsupportedFormat)
+	}
+
+	// Validate pre-hash function
+	if _, ok := preHashNames[p.PreHash]; !ok && p.PreHash != NoPreHash {
+		errs = append(errs, ErrUnsupportedParams)
+	}
+
+	// Validate normalization form
+	if err := p.checkNormalization(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// joinErrors returns nil for no errors, the error itself for a single one,
+// and the distinct errors joined with errors.Join otherwise.
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	distinct := errs[:0:0]
+	for _, err := range errs {
+		seen := false
+		for _, d := range distinct {
+			if d == err {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			distinct = append(distinct, err)
+		}
+	}
+	if len(distinct) == 1 {
+		return distinct[0]
+	}
+
+	return errors.Join(distinct...)
+}
+
+// Warnings returns guidance on valid, but questionable parameters, e.g. to
+// log it at startup. It returns nil if there is nothing to report.
+// In constrained environments, where memory is scarce, the memory cost
+// should be kept modest and compensated with iterations, as LowMemory does.
+func (p *Params) Warnings() []string {
+	var warnings []string
+
+	if uint64(p.Memory)*uint64(p.Iterations) < minMemoryCost {
+		warnings = append(warnings, "argon2: the memory and iterations are too low to resist GPU cracking, increase the iterations to compensate for a low memory")
+	}
+
+	if p.Memory > maxConstrainedMemory {
+		warnings = append(warnings, "argon2: the memory exceeds 256 MiB, which runs out of memory with concurrent hashes in serverless and other constrained environments, consider LowMemory")
+	}
+
+	if p.SaltLength < recSaltLength {
+		warnings = append(warnings, "argon2: the salt is shorter than the recommended 16 bytes")
+	}
+
+	if p.Variant == Argon2d {
+		warnings = append(warnings, "argon2: Argon2d is vulnerable to side-channel attacks, use Argon2id for password hashing")
+	}
+
+	return warnings
 }
@@ -1,9 +1,11 @@
 package argon2
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -35,6 +37,70 @@ func TestCompareHashAndPassword(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid phc hash password couple",
+			args: args{
+				hash:     []byte("$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				password: []byte("password"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid url-safe hash password couple",
+			args: args{
+				hash:     []byte("argon2id$19$65536$3$2$6pAg-fVI2vB9uenAuOTK0A$VPg50e-vxRnvQ8dIFSg1HFNYHYcxEW-Dx47O6vipImU"),
+				password: []byte("qwerty123"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid version 16 hash password couple",
+			args: args{
+				hash:     []byte("argon2id$16$65536$2$1$c29tZXNhbHQ$mA69JKTmZ/FjRvnUp4sXVyh4NhPgzG+xfC7IhLFkNd8"),
+				password: []byte("password"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid phc version 16 hash password couple",
+			args: args{
+				hash:     []byte("$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$mA69JKTmZ/FjRvnUp4sXVyh4NhPgzG+xfC7IhLFkNd8"),
+				password: []byte("password"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid phc hash without version password couple",
+			args: args{
+				hash:     []byte("$argon2id$m=65536,t=2,p=1$c29tZXNhbHQ$mA69JKTmZ/FjRvnUp4sXVyh4NhPgzG+xfC7IhLFkNd8"),
+				password: []byte("password"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid version 16 hash password couple",
+			args: args{
+				hash:     []byte("argon2id$16$65536$2$1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				password: []byte("password"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid argon2d hash password couple",
+			args: args{
+				hash:     []byte("$argon2d$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$lV5dWxY6G2C7o1/DbQSWR0+6T2tZrVNihmbwf7L5Pq8"),
+				password: []byte("password"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid argon2d hash password couple",
+			args: args{
+				hash:     []byte("argon2d$19$65536$2$1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				password: []byte("password"),
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid hash",
 			args: args{
@@ -80,60 +146,403 @@ func TestGenerateFromPassword(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "another valid params values",
+			name: "another valid params values",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 4, SaltLength: 32, KeyLength: 64},
+			},
+			wantErr: false,
+		},
+		{
+			name: "phc format",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16, Format: FormatPHC},
+			},
+			wantErr: false,
+		},
+		{
+			name: "argon2d variant",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16, Variant: Argon2d},
+			},
+			wantErr: false,
+		},
+		{
+			name: "url-safe encoding",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16, URLSafe: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "nil params",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid Version",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16, Version: 0x10},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid Format",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16, Format: Format(42)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid Memory",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 4 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid Iterations",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 64 * 1024, Iterations: 0, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid Parallelism",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 0, SaltLength: 16, KeyLength: 32},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid SaltLength",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 4, KeyLength: 32},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid KeyLength",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 8},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateFromPassword(tt.args.password, tt.args.p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateFromPassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func TestAppendHash(t *testing.T) {
+	type args struct {
+		dst      []byte
+		password []byte
+		p        *Params
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "nil buffer",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16},
+			},
+			wantErr: false,
+		},
+		{
+			name: "buffer with prefix",
+			args: args{
+				dst:      []byte("prefix:"),
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid params",
+			args: args{
+				dst:      make([]byte, 0, 128),
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 4 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := string(tt.args.dst)
+			got, err := AppendHash(tt.args.dst, tt.args.password, tt.args.p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AppendHash() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !strings.HasPrefix(string(got), prefix) {
+				t.Errorf("AppendHash() got = %s, want prefix %s", got, prefix)
+			}
+			if err := CompareHashAndPassword(got[len(prefix):], tt.args.password); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	hash := []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU")
+
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		want     bool
+		wantErr  error
+	}{
+		{name: "match", hash: hash, password: []byte("qwerty123"), want: true},
+		{name: "mismatch", hash: hash, password: []byte("qwerty1234")},
+		{name: "invalid hash", hash: []byte("dwiehduwehc8wh"), password: []byte("qwerty123"), wantErr: ErrInvalidHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Verify(tt.hash, tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareHashAndPasswordBuf(t *testing.T) {
+	type args struct {
+		buf      []byte
+		hash     []byte
+		password []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "nil buffer",
+			args: args{
+				hash:     []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				password: []byte("qwerty123"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "small buffer",
+			args: args{
+				buf:      make([]byte, 4),
+				hash:     []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				password: []byte("qwerty123"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "large buffer",
+			args: args{
+				buf:      make([]byte, 0, 256),
+				hash:     []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				password: []byte("qwerty123"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid hash password couple",
+			args: args{
+				buf:      make([]byte, 0, 256),
+				hash:     []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				password: []byte("qwerty1234"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid hash",
+			args: args{
+				buf:      make([]byte, 0, 256),
+				hash:     []byte("dwiehduwehc8wh"),
+				password: []byte("qwerty123"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareHashAndPasswordBuf(tt.args.buf, tt.args.hash, tt.args.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CompareHashAndPasswordBuf() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if cap(got) < cap(tt.args.buf) {
+				t.Errorf("CompareHashAndPasswordBuf() got cap = %v, want at least %v", cap(got), cap(tt.args.buf))
+			}
+		})
+	}
+}
+
+func TestGenerateRaw(t *testing.T) {
+	type args struct {
+		password []byte
+		p        *Params
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "valid params",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			},
+			wantErr: false,
+		},
+		{
+			name: "argon2d variant",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16, Variant: Argon2d},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid params",
+			args: args{
+				password: []byte("qwerty123"),
+				p:        &Params{Memory: 4 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSalt, gotKey, err := GenerateRaw(tt.args.password, tt.args.p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateRaw() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(gotSalt) != int(tt.args.p.SaltLength) {
+				t.Errorf("GenerateRaw() got salt len = %v, want %v", len(gotSalt), tt.args.p.SaltLength)
+			}
+			if len(gotKey) != int(tt.args.p.KeyLength) {
+				t.Errorf("GenerateRaw() got key len = %v, want %v", len(gotKey), tt.args.p.KeyLength)
+			}
+			if err := VerifyRaw(tt.args.password, gotSalt, gotKey, tt.args.p); err != nil {
+				t.Errorf("VerifyRaw() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRaw(t *testing.T) {
+	type args struct {
+		password []byte
+		salt     []byte
+		key      []byte
+		p        *Params
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "valid password",
 			args: args{
-				password: []byte("qwerty123"),
-				p:        &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 4, SaltLength: 32, KeyLength: 64},
+				password: []byte("password"),
+				salt:     []byte("somesalt"),
+				key:      []byte{9, 49, 97, 21, 213, 207, 36, 237, 90, 21, 163, 26, 59, 163, 38, 229, 207, 50, 237, 194, 71, 2, 152, 124, 2, 182, 86, 111, 97, 145, 60, 247},
+				p:        &Params{Memory: 64 * 1024, Iterations: 2, Parallelism: 1, KeyLength: 32},
 			},
 			wantErr: false,
 		},
 		{
-			name: "invalid Memory",
+			name: "invalid password",
 			args: args{
 				password: []byte("qwerty123"),
-				p:        &Params{Memory: 4 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+				salt:     []byte("somesalt"),
+				key:      []byte{9, 49, 97, 21, 213, 207, 36, 237, 90, 21, 163, 26, 59, 163, 38, 229, 207, 50, 237, 194, 71, 2, 152, 124, 2, 182, 86, 111, 97, 145, 60, 247},
+				p:        &Params{Memory: 64 * 1024, Iterations: 2, Parallelism: 1, KeyLength: 32},
 			},
 			wantErr: true,
 		},
 		{
-			name: "invalid Iterations",
+			name: "invalid key length",
 			args: args{
-				password: []byte("qwerty123"),
-				p:        &Params{Memory: 64 * 1024, Iterations: 0, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+				password: []byte("password"),
+				salt:     []byte("somesalt"),
+				key:      []byte{9, 49, 97, 21, 213, 207, 36, 237, 90, 21, 163, 26, 59, 163, 38, 229},
+				p:        &Params{Memory: 64 * 1024, Iterations: 2, Parallelism: 1, KeyLength: 32},
 			},
 			wantErr: true,
 		},
 		{
-			name: "invalid Parallelism",
+			name: "zero key length",
 			args: args{
-				password: []byte("qwerty123"),
-				p:        &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 0, SaltLength: 16, KeyLength: 32},
+				password: []byte("password"),
+				salt:     []byte("somesalt"),
+				key:      []byte{9, 49, 97, 21, 213, 207, 36, 237, 90, 21, 163, 26, 59, 163, 38, 229, 207, 50, 237, 194, 71, 2, 152, 124, 2, 182, 86, 111, 97, 145, 60, 247},
+				p:        &Params{Memory: 64 * 1024, Iterations: 2, Parallelism: 1},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "invalid SaltLength",
+			name: "empty key",
 			args: args{
-				password: []byte("qwerty123"),
-				p:        &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 4, KeyLength: 32},
+				password: []byte("password"),
+				salt:     []byte("somesalt"),
+				p:        &Params{Memory: 64 * 1024, Iterations: 2, Parallelism: 1, KeyLength: 32},
 			},
 			wantErr: true,
 		},
 		{
-			name: "invalid KeyLength",
+			name: "invalid Iterations",
 			args: args{
-				password: []byte("qwerty123"),
-				p:        &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 8},
+				password: []byte("password"),
+				salt:     []byte("somesalt"),
+				key:      []byte{9, 49, 97, 21, 213, 207, 36, 237, 90, 21, 163, 26, 59, 163, 38, 229},
+				p:        &Params{Memory: 64 * 1024, Iterations: 0, Parallelism: 1, KeyLength: 32},
 			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := GenerateFromPassword(tt.args.password, tt.args.p)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GenerateFromPassword() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if err := VerifyRaw(tt.args.password, tt.args.salt, tt.args.key, tt.args.p); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyRaw() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -215,6 +624,9 @@ func TestParams_Check(t *testing.T) {
 		Parallelism uint8
 		SaltLength  uint32
 		KeyLength   uint32
+		Format      Format
+		Variant     Variant
+		Version     uint32
 	}
 	tests := []struct {
 		name    string
@@ -241,6 +653,36 @@ func TestParams_Check(t *testing.T) {
 			fields:  fields{Memory: 256 * 1024, Iterations: 4, Parallelism: 8, SaltLength: 64, KeyLength: 128},
 			wantErr: false,
 		},
+		{
+			name:    "phc format",
+			fields:  fields{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Format: FormatPHC},
+			wantErr: false,
+		},
+		{
+			name:    "invalid Format",
+			fields:  fields{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Format: Format(42)},
+			wantErr: true,
+		},
+		{
+			name:    "argon2d variant",
+			fields:  fields{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Variant: Argon2d},
+			wantErr: false,
+		},
+		{
+			name:    "current Version",
+			fields:  fields{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Version: 0x13},
+			wantErr: false,
+		},
+		{
+			name:    "legacy Version",
+			fields:  fields{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Version: 0x10},
+			wantErr: true,
+		},
+		{
+			name:    "unknown Variant",
+			fields:  fields{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Variant: Variant(42)},
+			wantErr: true,
+		},
 		{
 			name:    "invalid Memory",
 			fields:  fields{Memory: 4 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
@@ -275,6 +717,9 @@ func TestParams_Check(t *testing.T) {
 				Parallelism: tt.fields.Parallelism,
 				SaltLength:  tt.fields.SaltLength,
 				KeyLength:   tt.fields.KeyLength,
+				Format:      tt.fields.Format,
+				Variant:     tt.fields.Variant,
+				Version:     tt.fields.Version,
 			}
 			if err := p.Check(); (err != nil) != tt.wantErr {
 				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
@@ -283,6 +728,259 @@ func TestParams_Check(t *testing.T) {
 	}
 }
 
+func TestParams_CheckUpperBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *Params
+		wantErr error
+	}{
+		{
+			name:    "maximum values",
+			params:  &Params{Memory: 4 * 1024 * 1024, Iterations: 1, Parallelism: 255, SaltLength: 1024, KeyLength: 1024},
+			wantErr: nil,
+		},
+		{
+			name:    "too large Memory",
+			params:  &Params{Memory: 4*1024*1024 + 1, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			wantErr: ErrMemoryTooLarge,
+		},
+		{
+			name:    "too long SaltLength",
+			params:  &Params{Memory: 64 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 1025, KeyLength: 32},
+			wantErr: ErrSaltTooLong,
+		},
+		{
+			name:    "too long KeyLength",
+			params:  &Params{Memory: 64 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 1 << 31},
+			wantErr: ErrKeyTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.params.Check(); err != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParams_CheckFieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *Params
+		wantErr error
+	}{
+		{
+			name:    "too small Memory",
+			params:  &Params{Memory: 4 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			wantErr: ErrMemoryTooSmall,
+		},
+		{
+			name:    "too small Iterations",
+			params:  &Params{Memory: 64 * 1024, Iterations: 0, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			wantErr: ErrIterationsTooSmall,
+		},
+		{
+			name:    "too small Parallelism",
+			params:  &Params{Memory: 64 * 1024, Iterations: 1, Parallelism: 0, SaltLength: 16, KeyLength: 32},
+			wantErr: ErrParallelismTooSmall,
+		},
+		{
+			name:    "too short SaltLength",
+			params:  &Params{Memory: 64 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 4, KeyLength: 32},
+			wantErr: ErrSaltTooShort,
+		},
+		{
+			name:    "too short KeyLength",
+			params:  &Params{Memory: 64 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 8},
+			wantErr: ErrKeyTooShort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Check()
+			if err != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("Check() error = %v, want it to wrap %v", err, ErrInvalidParams)
+			}
+		})
+	}
+}
+
+func TestParams_CheckAllViolations(t *testing.T) {
+	p := &Params{Memory: 4 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 4, KeyLength: 2048, Variant: Variant(42)}
+	err := p.Check()
+	for _, want := range []error{ErrMemoryTooSmall, ErrSaltTooShort, ErrKeyTooLong, ErrUnknownAlgorithm, ErrInvalidParams} {
+		if !errors.Is(err, want) {
+			t.Errorf("Check() error = %v, want it to report %v", err, want)
+		}
+	}
+	if errors.Is(err, ErrIterationsTooSmall) {
+		t.Errorf("Check() error = %v, want it not to report %v", err, ErrIterationsTooSmall)
+	}
+
+	SetValidator(&Validator{MinMemory: 64 * 1024, MinIterations: 2})
+	defer SetValidator(nil)
+
+	err = p.Check()
+	if !errors.Is(err, ErrIterationsTooSmall) || !errors.Is(err, ErrMemoryTooSmall) {
+		t.Errorf("Check() error = %v, want it to report the validator floors", err)
+	}
+	if got := strings.Count(err.Error(), ErrMemoryTooSmall.Error()); got != 1 {
+		t.Errorf("Check() error = %v, reports %v %d times", err, ErrMemoryTooSmall, got)
+	}
+}
+
+func TestNewParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		memory      uint32
+		iterations  uint32
+		parallelism uint8
+		saltLength  uint32
+		keyLength   uint32
+		wantErr     error
+	}{
+		{name: "valid", memory: 64 * 1024, iterations: 3, parallelism: 2, saltLength: 16, keyLength: 32},
+		{name: "memory too small", memory: 1024, iterations: 3, parallelism: 2, saltLength: 16, keyLength: 32, wantErr: ErrMemoryTooSmall},
+		{name: "no iterations", memory: 64 * 1024, parallelism: 2, saltLength: 16, keyLength: 32, wantErr: ErrIterationsTooSmall},
+		{name: "key too short", memory: 64 * 1024, iterations: 3, parallelism: 2, saltLength: 16, keyLength: 8, wantErr: ErrKeyTooShort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewParams(tt.memory, tt.iterations, tt.parallelism, tt.saltLength, tt.keyLength)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if got != nil {
+					t.Errorf("NewParams() got = %v, want nil", got)
+				}
+				return
+			}
+			want := &Params{Memory: tt.memory, Iterations: tt.iterations, Parallelism: tt.parallelism, SaltLength: tt.saltLength, KeyLength: tt.keyLength}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("NewParams() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	p := Defaults()
+	if *p != *DefaultParams {
+		t.Errorf("Defaults() got = %v, want %v", p, DefaultParams)
+	}
+
+	p.Memory = 8 * 1024
+	if got := Defaults().Memory; got != 64*1024 {
+		t.Errorf("Defaults() got Memory = %d after modifying a copy, want %d", got, 64*1024)
+	}
+
+	saved := *DefaultParams
+	defer func() { *DefaultParams = saved }()
+	DefaultParams.Iterations = 1
+	if got := Defaults().Iterations; got != 3 {
+		t.Errorf("Defaults() got Iterations = %d after modifying DefaultParams, want 3", got)
+	}
+}
+
+func TestSetDefaultParams(t *testing.T) {
+	defer SetDefaultParams(nil)
+
+	p := &Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+	if err := SetDefaultParams(p); err != nil {
+		t.Fatalf("SetDefaultParams() error = %v", err)
+	}
+	p.Memory = 8 * 1024
+	if got := Defaults(); got.Memory != 32*1024 || got.Format != FormatPHC {
+		t.Errorf("Defaults() got = %v, want the parameters set", got)
+	}
+
+	hash, err := GenerateFromPassword([]byte("qwerty123"), nil)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	if !strings.HasPrefix(string(hash), "$argon2id$v=19$m=32768,t=2,p=1$") {
+		t.Errorf("GenerateFromPassword() got = %s, want a hash with the parameters set", hash)
+	}
+
+	if err := SetDefaultParams(&Params{Memory: 1024}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("SetDefaultParams() error = %v, wantErr %v", err, ErrInvalidParams)
+	}
+	if got := Defaults().Memory; got != 32*1024 {
+		t.Errorf("Defaults() got Memory = %d after invalid parameters, want %d", got, 32*1024)
+	}
+
+	if err := SetDefaultParams(nil); err != nil {
+		t.Fatalf("SetDefaultParams() error = %v", err)
+	}
+	if got := Defaults(); *got != *DefaultParams {
+		t.Errorf("Defaults() got = %v, want the built-in defaults", got)
+	}
+}
+
+func TestParams_Clone(t *testing.T) {
+	p := &Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+	c := p.Clone()
+	if c == p || *c != *p {
+		t.Errorf("Clone() got = %v, want a copy of %v", c, p)
+	}
+
+	var nilParams *Params
+	if got := nilParams.Clone(); got != nil {
+		t.Errorf("Clone() got = %v, want nil", got)
+	}
+}
+
+func TestParams_Warnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *Params
+		want   int
+	}{
+		{
+			name:   "default params",
+			params: DefaultParams,
+			want:   0,
+		},
+		{
+			name:   "low memory params",
+			params: LowMemory(),
+			want:   0,
+		},
+		{
+			name:   "owasp 2021 params",
+			params: OWASP2021(),
+			want:   0,
+		},
+		{
+			name:   "low memory without compensation",
+			params: &Params{Memory: 12 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			want:   1,
+		},
+		{
+			name:   "too much memory",
+			params: &Params{Memory: 512 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			want:   1,
+		},
+		{
+			name:   "short salt and argon2d",
+			params: &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 8, KeyLength: 32, Variant: Argon2d},
+			want:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.params.Warnings(); len(got) != tt.want {
+				t.Errorf("Warnings() got = %q, want %d warnings", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_decodeHash(t *testing.T) {
 	type args struct {
 		encodedHash []byte
@@ -308,6 +1006,152 @@ func Test_decodeHash(t *testing.T) {
 			wantSalt: []byte{203, 211, 35, 151, 144, 169, 30, 2, 155, 70, 57, 104, 21, 158, 64, 130},
 			wantHash: []byte{58, 225, 33, 111, 160, 166, 33, 224, 140, 11, 114, 113, 221, 24, 9, 22, 135, 148, 75, 10, 59, 75, 211, 147, 174, 173, 180, 164, 85, 191, 21, 201},
 		},
+		{
+			name: "valid phc hash",
+			args: args{[]byte("$argon2id$v=19$m=65536,t=3,p=2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantP: &Params{
+				Memory:      65536,
+				Iterations:  3,
+				Parallelism: 2,
+				SaltLength:  16,
+				KeyLength:   32,
+				Format:      FormatPHC,
+			},
+			wantSalt: []byte{203, 211, 35, 151, 144, 169, 30, 2, 155, 70, 57, 104, 21, 158, 64, 130},
+			wantHash: []byte{58, 225, 33, 111, 160, 166, 33, 224, 140, 11, 114, 113, 221, 24, 9, 22, 135, 148, 75, 10, 59, 75, 211, 147, 174, 173, 180, 164, 85, 191, 21, 201},
+		},
+		{
+			name: "valid argon2d hash",
+			args: args{[]byte("argon2d$19$65536$3$2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantP: &Params{
+				Memory:      65536,
+				Iterations:  3,
+				Parallelism: 2,
+				SaltLength:  16,
+				KeyLength:   32,
+				Variant:     Argon2d,
+			},
+			wantSalt: []byte{203, 211, 35, 151, 144, 169, 30, 2, 155, 70, 57, 104, 21, 158, 64, 130},
+			wantHash: []byte{58, 225, 33, 111, 160, 166, 33, 224, 140, 11, 114, 113, 221, 24, 9, 22, 135, 148, 75, 10, 59, 75, 211, 147, 174, 173, 180, 164, 85, 191, 21, 201},
+		},
+		{
+			name: "valid phc hash without version",
+			args: args{[]byte("$argon2id$m=65536,t=3,p=2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantP: &Params{
+				Memory:      65536,
+				Iterations:  3,
+				Parallelism: 2,
+				SaltLength:  16,
+				KeyLength:   32,
+				Format:      FormatPHC,
+				Version:     0x10,
+			},
+			wantSalt: []byte{203, 211, 35, 151, 144, 169, 30, 2, 155, 70, 57, 104, 21, 158, 64, 130},
+			wantHash: []byte{58, 225, 33, 111, 160, 166, 33, 224, 140, 11, 114, 113, 221, 24, 9, 22, 135, 148, 75, 10, 59, 75, 211, 147, 174, 173, 180, 164, 85, 191, 21, 201},
+		},
+		{
+			name: "valid url-safe hash",
+			args: args{[]byte("argon2id$19$65536$3$2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW_Fck")},
+			wantP: &Params{
+				Memory:      65536,
+				Iterations:  3,
+				Parallelism: 2,
+				SaltLength:  16,
+				KeyLength:   32,
+				URLSafe:     true,
+			},
+			wantSalt: []byte{203, 211, 35, 151, 144, 169, 30, 2, 155, 70, 57, 104, 21, 158, 64, 130},
+			wantHash: []byte{58, 225, 33, 111, 160, 166, 33, 224, 140, 11, 114, 113, 221, 24, 9, 22, 135, 148, 75, 10, 59, 75, 211, 147, 174, 173, 180, 164, 85, 191, 21, 201},
+		},
+		{
+			name:    "invalid mixed base64 alphabets",
+			args:    args{[]byte("argon2id$19$65536$3$2$y9Mjl5Cp-gKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc version field",
+			args:    args{[]byte("$argon2id$m=65536,t=3,p=2$v=19$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name: "valid phc hash with parameters in any order",
+			args: args{[]byte("$argon2id$v=19$t=3,p=2,m=65536$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantP: &Params{
+				Memory:      65536,
+				Iterations:  3,
+				Parallelism: 2,
+				SaltLength:  16,
+				KeyLength:   32,
+				Format:      FormatPHC,
+			},
+			wantSalt: []byte{203, 211, 35, 151, 144, 169, 30, 2, 155, 70, 57, 104, 21, 158, 64, 130},
+			wantHash: []byte{58, 225, 33, 111, 160, 166, 33, 224, 140, 11, 114, 113, 221, 24, 9, 22, 135, 148, 75, 10, 59, 75, 211, 147, 174, 173, 180, 164, 85, 191, 21, 201},
+		},
+		{
+			name:    "invalid phc duplicated parameter",
+			args:    args{[]byte("$argon2id$v=19$m=65536,t=3,p=2,m=1024$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc unknown parameter",
+			args:    args{[]byte("$argon2id$v=19$m=65536,t=3,p=2,x=1$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc data parameter",
+			args:    args{[]byte("$argon2id$v=19$m=65536,t=3,p=2,data=!$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc keyid parameter",
+			args:    args{[]byte("$argon2id$v=19$m=65536,t=3,p=2,keyid=!$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc parameters count",
+			args:    args{[]byte("$argon2id$v=19$m=65536,t=3$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc argon2 version",
+			args:    args{[]byte("$argon2id$v=18$m=65536,t=3,p=2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc parallelism",
+			args:    args{[]byte("$argon2id$v=19$m=65536,t=3,p=256$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid legacy parallelism",
+			args:    args{[]byte("argon2id$19$65536$3$256$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid legacy zero iterations",
+			args:    args{[]byte("argon2id$19$65536$0$2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc zero iterations",
+			args:    args{[]byte("$argon2id$v=19$m=65536,t=0,p=2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid phc zero parallelism",
+			args:    args{[]byte("$argon2id$v=19$m=65536,t=3,p=0$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown algorithm",
+			args:    args{[]byte("bcrypt$19$65536$3$2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown phc algorithm",
+			args:    args{[]byte("$argon2x$v=19$m=65536,t=3,p=2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck")},
+			wantErr: true,
+		},
 		{
 			name:    "invalid hash length",
 			args:    args{[]byte("argon2id$19$65536$3$2$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTdeawf43v43rxwxrq20pFW/Fck")},
@@ -0,0 +1,50 @@
+// Package argon2test provides a fast, deterministic fake of the argon2
+// PasswordHasher and PasswordVerifier interfaces for the tests of
+// applications, so their suites don't spend minutes of CPU hashing fixture
+// passwords.
+//
+// The fake hashes are a plain SHA-256 of the password without a salt.
+// NEVER use them outside of tests.
+package argon2test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// prefix starts the fake hashes, so they are never mistaken for real ones.
+const prefix = "argon2test-insecure$"
+
+// Hasher is a fake of argon2.Hasher, implementing the argon2.PasswordHasher
+// and argon2.PasswordVerifier interfaces. The same password always results
+// in the same hash. The zero value is ready to use.
+type Hasher struct{}
+
+// Hash returns the fake hash of the password.
+func (Hasher) Hash(password []byte) ([]byte, error) {
+	sum := sha256.Sum256(password)
+	hash := make([]byte, len(prefix)+base64.RawStdEncoding.EncodedLen(len(sum)))
+	copy(hash, prefix)
+	base64.RawStdEncoding.Encode(hash[len(prefix):], sum[:])
+	return hash, nil
+}
+
+// Verify compares the fake hash with the password. It returns nil on
+// success, argon2.ErrMismatchedHashAndPassword if they do not match, and
+// argon2.ErrInvalidHash if the hash isn't a fake one.
+func (h Hasher) Verify(hash, password []byte) error {
+	if !bytes.HasPrefix(hash, []byte(prefix)) {
+		return argon2.ErrInvalidHash
+	}
+
+	want, _ := h.Hash(password)
+	if subtle.ConstantTimeCompare(hash, want) != 1 {
+		return argon2.ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
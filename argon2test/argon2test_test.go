@@ -0,0 +1,50 @@
+package argon2test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+func TestHasher(t *testing.T) {
+	var hasher argon2.PasswordHasher = Hasher{}
+	var verifier argon2.PasswordVerifier = Hasher{}
+
+	hash, err := hasher.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	again, _ := hasher.Hash([]byte("password"))
+	if !bytes.Equal(hash, again) {
+		t.Errorf("Hash() got = %s and %s, want deterministic hashes", hash, again)
+	}
+
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{name: "match", hash: hash, password: []byte("password")},
+		{name: "mismatch", hash: hash, password: []byte("wrong"), wantErr: argon2.ErrMismatchedHashAndPassword},
+		{
+			name:     "real hash",
+			hash:     []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+			password: []byte("qwerty123"),
+			wantErr:  argon2.ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifier.Verify(tt.hash, tt.password); err != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// fake hashes are never accepted by the real verifier
+	if err := argon2.CompareHashAndPassword(hash, []byte("password")); err == nil {
+		t.Errorf("CompareHashAndPassword() accepted a fake hash")
+	}
+}
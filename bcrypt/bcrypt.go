@@ -0,0 +1,106 @@
+// Package bcrypt mirrors the API of golang.org/x/crypto/bcrypt on top of
+// argon2, so codebases can move to argon2 by changing an import:
+//
+//	import "github.com/andskur/argon2-hashing/bcrypt"
+//
+// The bcrypt cost levels are mapped to argon2 parameter presets, from cheap
+// parameters meant for tests at MinCost, through the package defaults at
+// DefaultCost, to libsodium's sensitive profile from cost 14 on.
+// Existing bcrypt hashes aren't verified; use argon2.VerifyAndMigrate to
+// upgrade them.
+package bcrypt
+
+import (
+	"strconv"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// The cost levels, as in golang.org/x/crypto/bcrypt.
+const (
+	MinCost     int = 4  // the minimum allowable cost as passed in to GenerateFromPassword
+	MaxCost     int = 31 // the maximum allowable cost as passed in to GenerateFromPassword
+	DefaultCost int = 10 // the cost that will actually be set if a cost below MinCost is passed into GenerateFromPassword
+)
+
+// ErrMismatchedHashAndPassword is returned from CompareHashAndPassword
+// when a password and hash do not match.
+var ErrMismatchedHashAndPassword = argon2.ErrMismatchedHashAndPassword
+
+// InvalidCostError is returned by GenerateFromPassword for a cost above
+// MaxCost.
+type InvalidCostError int
+
+func (ic InvalidCostError) Error() string {
+	return "argon2: cost " + strconv.Itoa(int(ic)) + " is outside allowed range (" +
+		strconv.Itoa(MinCost) + "," + strconv.Itoa(MaxCost) + ")"
+}
+
+// level maps the costs from its cost on to argon2 parameters.
+type level struct {
+	cost   int
+	params func() *argon2.Params
+}
+
+// levels are the cost levels in increasing order of cost.
+var levels = []level{
+	{MinCost, func() *argon2.Params {
+		return &argon2.Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	}},
+	{6, argon2.OWASP},
+	{DefaultCost, argon2.Defaults},
+	{12, argon2.Moderate},
+	{14, argon2.Sensitive},
+}
+
+// params returns the argon2 parameters of the cost.
+func params(cost int) *argon2.Params {
+	p := levels[0].params
+	for _, l := range levels {
+		if cost >= l.cost {
+			p = l.params
+		}
+	}
+	return p()
+}
+
+// GenerateFromPassword returns the argon2 hash of the password at the given
+// cost. If the cost given is less than MinCost, the cost will be set to
+// DefaultCost, instead.
+func GenerateFromPassword(password []byte, cost int) ([]byte, error) {
+	if cost < MinCost {
+		cost = DefaultCost
+	}
+	if cost > MaxCost {
+		return nil, InvalidCostError(cost)
+	}
+
+	return argon2.GenerateFromPassword(password, params(cost))
+}
+
+// CompareHashAndPassword compares an argon2 hashed password with its
+// possible plaintext equivalent. Returns nil on success, or an error on
+// failure.
+func CompareHashAndPassword(hashedPassword, password []byte) error {
+	return argon2.CompareHashAndPassword(hashedPassword, password)
+}
+
+// Cost returns the cost level the hash corresponds to: the highest level
+// whose parameters cost as much memory times iterations as the hash, or
+// MinCost if none does.
+func Cost(hashedPassword []byte) (int, error) {
+	cost, err := argon2.Cost(hashedPassword)
+	if err != nil {
+		return 0, err
+	}
+
+	level := MinCost
+	for _, l := range levels {
+		p := l.params()
+		if uint64(p.Memory)*uint64(p.Iterations) <= cost {
+			level = l.cost
+		}
+	}
+
+	return level, nil
+}
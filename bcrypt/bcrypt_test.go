@@ -0,0 +1,69 @@
+package bcrypt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+func TestGenerateFromPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		cost     int
+		wantCost int
+		wantErr  error
+	}{
+		{name: "min cost", cost: MinCost, wantCost: MinCost},
+		{name: "between levels", cost: 7, wantCost: 6},
+		{name: "below min cost", cost: 0, wantCost: DefaultCost},
+		{name: "above max cost", cost: MaxCost + 1, wantErr: InvalidCostError(MaxCost + 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := GenerateFromPassword([]byte("password"), tt.cost)
+			if err != tt.wantErr {
+				t.Fatalf("GenerateFromPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if err := CompareHashAndPassword(hash, []byte("password")); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+			if err := CompareHashAndPassword(hash, []byte("wrong")); err != ErrMismatchedHashAndPassword {
+				t.Errorf("CompareHashAndPassword() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+			}
+
+			cost, err := Cost(hash)
+			if err != nil || cost != tt.wantCost {
+				t.Errorf("Cost() got = %d, error = %v, want %d", cost, err, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestCost(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *argon2.Params
+		want    int
+		wantErr error
+	}{
+		{name: "moderate", params: argon2.Moderate(), want: 12},
+		{name: "sensitive", params: argon2.Sensitive(), want: 14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := argon2.EncodeHash(tt.params, make([]byte, tt.params.SaltLength), make([]byte, tt.params.KeyLength))
+			if got, err := Cost(hash); err != nil || got != tt.want {
+				t.Errorf("Cost() got = %d, error = %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Cost([]byte("$2b$10$salt")); !errors.Is(err, argon2.ErrInvalidHash) {
+		t.Errorf("Cost() error = %v, want %v", err, argon2.ErrInvalidHash)
+	}
+}
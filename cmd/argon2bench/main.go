@@ -0,0 +1,92 @@
+// Command argon2bench times argon2 hashes over a grid of memory, iterations
+// and parallelism values and prints the timings as JSON or CSV, e.g.
+//
+//	argon2bench -memory 19456,65536 -iterations 1,2,3 -parallelism 1,2 -format csv
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+func main() {
+	memory := flag.String("memory", "19456,65536", "comma-separated memory values (kibibytes)")
+	iterations := flag.String("iterations", "1,2,3", "comma-separated iterations values")
+	parallelism := flag.String("parallelism", "1,2", "comma-separated parallelism values")
+	format := flag.String("format", "json", "output format, json or csv")
+	flag.Parse()
+
+	var g argon2.Grid
+	for _, v := range parseList(*memory, 32) {
+		g.Memory = append(g.Memory, uint32(v))
+	}
+	for _, v := range parseList(*iterations, 32) {
+		g.Iterations = append(g.Iterations, uint32(v))
+	}
+	for _, v := range parseList(*parallelism, 8) {
+		g.Parallelism = append(g.Parallelism, uint8(v))
+	}
+
+	timings, err := argon2.Benchmark(g)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(timings)
+	case "csv":
+		err = writeCSV(timings)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseList parses a comma-separated list of unsigned integers of the given bit size.
+func parseList(s string, bitSize int) []uint64 {
+	var values []uint64
+	for _, field := range strings.Split(s, ",") {
+		v, err := strconv.ParseUint(strings.TrimSpace(field), 10, bitSize)
+		if err != nil {
+			log.Fatal(err)
+		}
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// writeCSV writes the timings to stdout as CSV, with the durations in milliseconds.
+func writeCSV(timings []argon2.Timing) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write([]string{"memory", "iterations", "parallelism", "duration_ms"}); err != nil {
+		return err
+	}
+	for _, t := range timings {
+		record := []string{
+			strconv.FormatUint(uint64(t.Memory), 10),
+			strconv.FormatUint(uint64(t.Iterations), 10),
+			strconv.FormatUint(uint64(t.Parallelism), 10),
+			strconv.FormatFloat(t.Duration.Seconds()*1000, 'f', 3, 64),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+
+	return w.Error()
+}
@@ -0,0 +1,262 @@
+// Package config loads the argon2 hashing configuration from a YAML file and
+// can watch the file for changes, so operators can raise the cost parameters
+// without redeploying:
+//
+//	w, err := config.Watch("/etc/myapp/argon2.yaml", 10*time.Second, log.Print)
+//	...
+//	hash, err := argon2.GenerateFromPasswordWithSecret(password, w.Config().Pepper, w.Config().Params)
+//
+// The file holds the parameters, with the field names of their JSON form,
+// and an optional Base64 encoded pepper, e.g.
+//
+//	params:
+//	  memory: 65536 # KiB
+//	  iterations: 3
+//	  parallelism: 2
+//	  format: phc
+//	pepper: c2VjcmV0LXBlcHBlcg==
+//
+// Only this flat subset of YAML is supported, so the package doesn't pull
+// in a YAML dependency: mappings of scalars, comments and quoted strings.
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// ErrInvalidConfig is returned when the configuration file is malformed
+// or has unknown keys.
+var ErrInvalidConfig = errors.New("config: invalid configuration")
+
+// Config is the hashing configuration.
+type Config struct {
+	Params *argon2.Params // The parameters of new hashes
+	Pepper []byte         // The secret key mixed into the hashes, if any
+}
+
+// paramKeys are the keys of the params section, the field names of the
+// JSON form of argon2.Params, reporting whether the value is a string even
+// if it looks like a number, e.g. the key ID 2024.
+var paramKeys = map[string]bool{
+	"memory":        false,
+	"iterations":    false,
+	"parallelism":   false,
+	"salt_length":   false,
+	"key_length":    false,
+	"format":        false,
+	"variant":       false,
+	"version":       false,
+	"url_safe":      false,
+	"key_id":        true,
+	"data":          true,
+	"prehash":       false,
+	"normalization": false,
+}
+
+// Load reads and parses the named configuration file.
+func Load(name string) (*Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Parse parses a configuration. Omitted parameters are taken from
+// argon2.Defaults, and the parameters are validated with Check.
+// It returns ErrInvalidConfig, wrapped with the line number, if a line
+// is malformed or has an unknown key, and wrapping the underlying error if
+// a value is malformed.
+func Parse(data []byte) (*Config, error) {
+	var params []string
+	var pepper string
+
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		key, value, err := parseLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("config: line %d: %w", n, err)
+		}
+		if key == "" {
+			continue
+		}
+
+		indented := text[0] == ' ' || text[0] == '\t'
+		str, known := paramKeys[key]
+		switch {
+		case indented && section == "params" && known:
+			params = append(params, strconv.Quote(key)+":"+jsonValue(value, str))
+		case !indented && key == "params" && value == "":
+			section = key
+		case !indented && key == "pepper":
+			section, pepper = "", value
+		default:
+			return nil, fmt.Errorf("config: line %d: %w", n, ErrInvalidConfig)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	c := &Config{Params: new(argon2.Params)}
+	if err := json.Unmarshal([]byte("{"+strings.Join(params, ",")+"}"), c.Params); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+		return nil, err
+	}
+	if pepper != "" {
+		var err error
+		if c.Pepper, err = base64.StdEncoding.DecodeString(pepper); err != nil {
+			return nil, fmt.Errorf("%w: pepper: %w", ErrInvalidConfig, err)
+		}
+	}
+
+	return c, nil
+}
+
+// parseLine parses a "key: value" line, with an optional comment. It
+// returns an empty key for blank and comment lines.
+func parseLine(text string) (key, value string, err error) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || trimmed[0] == '#' {
+		return "", "", nil
+	}
+
+	i := strings.IndexByte(trimmed, ':')
+	if i <= 0 {
+		return "", "", ErrInvalidConfig
+	}
+	key, value = trimmed[:i], strings.TrimSpace(trimmed[i+1:])
+
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		end := strings.IndexByte(value[1:], value[0])
+		if end < 0 {
+			return "", "", ErrInvalidConfig
+		}
+		rest := strings.TrimSpace(value[end+2:])
+		if rest != "" && rest[0] != '#' {
+			return "", "", ErrInvalidConfig
+		}
+		return key, value[:end+2], nil
+	}
+	if j := strings.Index(value, " #"); j >= 0 {
+		value = strings.TrimSpace(value[:j])
+	}
+
+	return key, value, nil
+}
+
+// jsonValue returns the JSON form of a YAML scalar: numbers and booleans
+// as is, unless str is set, and other values as strings.
+func jsonValue(value string, str bool) string {
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		return strconv.Quote(value[1 : len(value)-1])
+	}
+	if str {
+		return strconv.Quote(value)
+	}
+	if _, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return value
+	}
+	if value == "true" || value == "false" {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// Watcher watches a configuration file, reloading it when it changes.
+// The active configuration is swapped atomically, and an invalid file
+// doesn't replace it.
+type Watcher struct {
+	name    string
+	onError func(error)
+	current atomic.Pointer[Config]
+
+	modTime time.Time
+	size    int64
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Watch loads the named configuration file and checks it for changes
+// every interval. The errors of reloading the file, e.g. an invalid
+// configuration, are passed to onError if it isn't nil, and the previous
+// configuration stays active.
+func Watch(name string, interval time.Duration, onError func(error)) (*Watcher, error) {
+	w := &Watcher{name: name, onError: onError, done: make(chan struct{})}
+	if err := w.reload(); err != nil {
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.reload(); err != nil && w.onError != nil {
+					w.onError(err)
+				}
+			case <-w.done:
+				return
+			}
+		}
+	}()
+
+	return w, nil
+}
+
+// Config returns the active configuration.
+func (w *Watcher) Config() *Config {
+	return w.current.Load()
+}
+
+// Close stops watching the file.
+func (w *Watcher) Close() {
+	close(w.done)
+	w.wg.Wait()
+}
+
+// reload loads the file if it changed since it was last loaded.
+func (w *Watcher) reload() error {
+	info, err := os.Stat(w.name)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return nil
+	}
+
+	c, err := Load(w.name)
+	if err != nil {
+		return err
+	}
+
+	w.modTime, w.size = info.ModTime(), info.Size()
+	w.current.Store(c)
+
+	return nil
+}
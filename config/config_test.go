@@ -0,0 +1,174 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+func TestParse(t *testing.T) {
+	// the NFKC normalization requires a function, which the test doesn't need
+	argon2.SetNFKC(func(password []byte) []byte { return password })
+	defer argon2.SetNFKC(nil)
+
+	tests := []struct {
+		name    string
+		data    string
+		want    *Config
+		wantErr error
+	}{
+		{
+			name: "params and pepper",
+			data: `# argon2 configuration
+params:
+  memory: 19456 # KiB
+  iterations: 2
+  parallelism: 1
+  format: "phc"
+  variant: 'argon2i'
+
+pepper: c2VjcmV0LXBlcHBlcg==
+`,
+			want: &Config{
+				Params: &argon2.Params{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: argon2.FormatPHC, Variant: argon2.Argon2i},
+				Pepper: []byte("secret-pepper"),
+			},
+		},
+		{
+			name: "empty",
+			data: "",
+			want: &Config{Params: argon2.DefaultParams},
+		},
+		{
+			name: "url-safe",
+			data: "params:\n  url_safe: true\n",
+			want: &Config{Params: &argon2.Params{Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, URLSafe: true}},
+		},
+		{
+			name: "extensions",
+			data: "params:\n  format: phc\n  key_id: 2024\n  data: dXNlci00Mg==\n  prehash: sha512\n  normalization: nfkc\n",
+			want: &Config{Params: &argon2.Params{
+				Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Format: argon2.FormatPHC,
+				KeyID: "2024", Data: "user-42", PreHash: argon2.PreHashSHA512, Normalization: argon2.NFKC,
+			}},
+		},
+		{
+			name:    "unknown key",
+			data:    "params:\n  memory: 65536\ncost: 3\n",
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "unknown parameter",
+			data:    "params:\n  lanes: 4\n",
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "malformed line",
+			data:    "params:\n  memory 65536\n",
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "malformed number",
+			data:    "params:\n  memory: 64MiB\n",
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "invalid params",
+			data:    "params:\n  memory: 1024\n",
+			wantErr: argon2.ErrMemoryTooSmall,
+		},
+		{
+			name:    "invalid pepper",
+			data:    "pepper: not base64!\n",
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "unterminated quote",
+			data:    "pepper: \"c2VjcmV0\n",
+			wantErr: ErrInvalidConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_WrapsErrors(t *testing.T) {
+	_, err := Parse([]byte("params:\n  memory: 64MiB\n"))
+	var typeErr *json.UnmarshalTypeError
+	if !errors.Is(err, ErrInvalidConfig) || !errors.As(err, &typeErr) {
+		t.Errorf("Parse() error = %v, want %v wrapping a %T", err, ErrInvalidConfig, typeErr)
+	}
+
+	_, err = Parse([]byte("pepper: c2VjcmV0!\n"))
+	var corruptErr base64.CorruptInputError
+	if !errors.Is(err, ErrInvalidConfig) || !errors.As(err, &corruptErr) {
+		t.Errorf("Parse() error = %v, want %v wrapping a %T", err, ErrInvalidConfig, corruptErr)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "argon2.yaml")
+	write := func(data string, modTime time.Time) {
+		if err := os.WriteFile(name, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(name, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	write("params:\n  memory: 19456\n", start)
+
+	errs := make(chan error, 10)
+	w, err := Watch(name, 10*time.Millisecond, func(err error) { errs <- err })
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer w.Close()
+
+	if got := w.Config().Params.Memory; got != 19456 {
+		t.Fatalf("Config().Params.Memory = %d, want 19456", got)
+	}
+
+	write("params:\n  memory: 1024\n", start.Add(time.Minute))
+	select {
+	case err := <-errs:
+		if !errors.Is(err, argon2.ErrMemoryTooSmall) {
+			t.Errorf("onError() error = %v, want %v", err, argon2.ErrMemoryTooSmall)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid configuration wasn't reported")
+	}
+	if got := w.Config().Params.Memory; got != 19456 {
+		t.Errorf("Config().Params.Memory = %d after an invalid change, want 19456", got)
+	}
+
+	write("params:\n  memory: 65536\n", start.Add(2*time.Minute))
+	deadline := time.Now().Add(5 * time.Second)
+	for w.Config().Params.Memory != 65536 {
+		if time.Now().After(deadline) {
+			t.Fatal("configuration wasn't reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := Watch(filepath.Join(t.TempDir(), "missing.yaml"), time.Second, nil); err == nil {
+		t.Error("Watch() error = nil for a missing file")
+	}
+}
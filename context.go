@@ -0,0 +1,27 @@
+package argon2
+
+import (
+	"context"
+)
+
+// GenerateFromPasswordContext is like GenerateFromPassword, but returns the
+// error of the context without hashing if it is already canceled or past its
+// deadline, so request-scoped servers don't spend the cost of a hash on
+// requests that already timed out. The derivation itself can't be
+// interrupted once started.
+func GenerateFromPasswordContext(ctx context.Context, password []byte, p *Params) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return GenerateFromPassword(password, p)
+}
+
+// CompareHashAndPasswordContext is like CompareHashAndPassword, but returns
+// the error of the context without verifying if it is already canceled or
+// past its deadline, like GenerateFromPasswordContext.
+func CompareHashAndPasswordContext(ctx context.Context, hash, password []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return CompareHashAndPassword(hash, password)
+}
@@ -0,0 +1,43 @@
+package argon2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestContext(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hash := MustGenerateFromPassword([]byte("password"), p)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "background", ctx: context.Background()},
+		{name: "canceled", ctx: canceled, wantErr: context.Canceled},
+		{name: "deadline exceeded", ctx: expired, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateFromPasswordContext(tt.ctx, []byte("password"), p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GenerateFromPasswordContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got != nil) != (tt.wantErr == nil) {
+				t.Errorf("GenerateFromPasswordContext() got = %s", got)
+			}
+
+			if err := CompareHashAndPasswordContext(tt.ctx, hash, []byte("password")); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CompareHashAndPasswordContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package argon2
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxAutoParallelism caps the lanes set by AutoParallelism, as more lanes
+// than that hardly speed up a hash while starving concurrent requests.
+const maxAutoParallelism = 8
+
+// AutoParallelism returns a copy of the parameters provided, or of the
+// Defaults if p is nil, with the parallelism set to the number of CPUs the
+// process may use, up to 8. The number of CPUs is runtime.GOMAXPROCS, capped
+// to the CPU quota of the cgroup the process runs in, e.g. of a Kubernetes
+// pod, which GOMAXPROCS only follows from Go 1.25, so containers get
+// appropriate lane counts without manual tuning. Hashes record their
+// parallelism, so hosts with different CPU counts still verify each other's
+// hashes.
+func AutoParallelism(p *Params) *Params {
+	c := orDefault(p).Clone()
+	c.Parallelism = autoParallelism(cpuLimit("/", runtime.GOMAXPROCS(0)))
+	return c
+}
+
+// autoParallelism returns the number of lanes for the number of CPUs.
+func autoParallelism(cpus int) uint8 {
+	switch {
+	case cpus < 1:
+		return 1
+	case cpus > maxAutoParallelism:
+		return maxAutoParallelism
+	default:
+		return uint8(cpus)
+	}
+}
+
+// cpuLimit returns the number of CPUs the process may use, cpus capped to
+// the cgroup v2 or v1 CPU quota under the root directory, rounded up.
+func cpuLimit(root string, cpus int) int {
+	var quota, period string
+	if data, err := os.ReadFile(filepath.Join(root, "sys/fs/cgroup/cpu.max")); err == nil {
+		// cgroup v2 reads "$MAX $PERIOD", where an unlimited $MAX is "max"
+		if fields := strings.Fields(string(data)); len(fields) == 2 {
+			quota, period = fields[0], fields[1]
+		}
+	} else {
+		// cgroup v1 reads a quota of -1 when unlimited
+		q, _ := os.ReadFile(filepath.Join(root, "sys/fs/cgroup/cpu/cpu.cfs_quota_us"))
+		p, _ := os.ReadFile(filepath.Join(root, "sys/fs/cgroup/cpu/cpu.cfs_period_us"))
+		quota, period = strings.TrimSpace(string(q)), strings.TrimSpace(string(p))
+	}
+
+	q, err := strconv.ParseUint(quota, 10, 64)
+	if err != nil || q == 0 {
+		return cpus
+	}
+	p, err := strconv.ParseUint(period, 10, 64)
+	if err != nil || p == 0 {
+		return cpus
+	}
+	if limit := (q + p - 1) / p; limit < uint64(cpus) {
+		return int(limit)
+	}
+
+	return cpus
+}
@@ -0,0 +1,104 @@
+package argon2
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestAutoParallelism(t *testing.T) {
+	p := &Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	got := AutoParallelism(p)
+	if got == p || p.Parallelism != 1 {
+		t.Errorf("AutoParallelism() modified the parameters provided")
+	}
+	if want := autoParallelism(cpuLimit("/", runtime.GOMAXPROCS(0))); got.Parallelism != want {
+		t.Errorf("AutoParallelism() got Parallelism = %d, want %d", got.Parallelism, want)
+	}
+	if err := got.Check(); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+	if got := AutoParallelism(nil); got.Memory != Defaults().Memory {
+		t.Errorf("AutoParallelism() got = %v, want the Defaults", got)
+	}
+}
+
+func Test_autoParallelism(t *testing.T) {
+	tests := []struct {
+		cpus int
+		want uint8
+	}{
+		{cpus: 0, want: 1},
+		{cpus: 1, want: 1},
+		{cpus: 4, want: 4},
+		{cpus: 8, want: 8},
+		{cpus: 64, want: 8},
+	}
+	for _, tt := range tests {
+		if got := autoParallelism(tt.cpus); got != tt.want {
+			t.Errorf("autoParallelism(%d) got = %d, want %d", tt.cpus, got, tt.want)
+		}
+	}
+}
+
+func Test_cpuLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  int
+	}{
+		{
+			name: "no cgroup limit",
+			want: 16,
+		},
+		{
+			name:  "cgroup v2 limit",
+			files: map[string]string{"sys/fs/cgroup/cpu.max": "200000 100000\n"},
+			want:  2,
+		},
+		{
+			name:  "fractional cgroup v2 limit",
+			files: map[string]string{"sys/fs/cgroup/cpu.max": "150000 100000\n"},
+			want:  2,
+		},
+		{
+			name:  "unlimited cgroup v2",
+			files: map[string]string{"sys/fs/cgroup/cpu.max": "max 100000\n"},
+			want:  16,
+		},
+		{
+			name:  "cgroup v2 limit above the CPUs",
+			files: map[string]string{"sys/fs/cgroup/cpu.max": "3200000 100000\n"},
+			want:  16,
+		},
+		{
+			name:  "cgroup v1 limit",
+			files: map[string]string{"sys/fs/cgroup/cpu/cpu.cfs_quota_us": "400000\n", "sys/fs/cgroup/cpu/cpu.cfs_period_us": "100000\n"},
+			want:  4,
+		},
+		{
+			name:  "unlimited cgroup v1",
+			files: map[string]string{"sys/fs/cgroup/cpu/cpu.cfs_quota_us": "-1\n", "sys/fs/cgroup/cpu/cpu.cfs_period_us": "100000\n"},
+			want:  16,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, data := range tt.files {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := cpuLimit(root, 16); got != tt.want {
+				t.Errorf("cpuLimit() got = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"time"
+)
+
+// cryptPrefix is the prefix shared by the crypt(3) ids of the argon2 variants.
+const cryptPrefix = "$argon2"
+
+// GenerateCrypt returns the derived key of the password in the crypt(3)
+// notation, e.g. "$argon2id$v=19$m=65536,t=3,p=2$salt$hash", as stored in
+// the password field of /etc/shadow and understood by PAM modules and crypt
+// libraries supporting argon2. The hash is encoded in the PHC format with
+// the standard base64 alphabet, whatever p.Format and p.URLSafe are set to.
+// The parameters crypt(3) doesn't know, a key ID, associated data, a
+// pre-hash function or a normalization, are rejected with
+// ErrUnsupportedParams.
+func GenerateCrypt(password []byte, p *Params) ([]byte, error) {
+	crypt := *orDefault(p)
+	if crypt.extended() {
+		return nil, ErrUnsupportedParams
+	}
+	crypt.Format, crypt.URLSafe = FormatPHC, false
+
+	return GenerateFromPassword(password, &crypt)
+}
+
+// CompareCrypt compares a hash in the crypt(3) notation, as generated by
+// GenerateCrypt, with the possible cleartext equivalent. Unlike
+// CompareHashAndPassword, it only accepts hashes as crypt(3) would, so other
+// formats and prefixed hashes, such as the ones of locked accounts in
+// /etc/shadow ("!$argon2id$..."), are rejected with ErrInvalidHash.
+func CompareCrypt(hash, password []byte) error {
+	defer padVerify(time.Now())
+
+	if !bytes.HasPrefix(hash, []byte(cryptPrefix)) {
+		return ErrInvalidHash
+	}
+
+	p, salt, key, err := decodeHashInto(nil, hash, ParseStrict)
+	if err != nil {
+		return err
+	}
+	if p.Format != FormatPHC || p.URLSafe {
+		return ErrInvalidHash
+	}
+	if err := p.checkInputs(nil, nil); err != nil {
+		return err
+	}
+	if err := checkPassword(password); err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(key, p.Variant.deriveKey(password, salt, nil, p)) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+	notifyWeakHash(p)
+
+	return nil
+}
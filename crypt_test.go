@@ -0,0 +1,123 @@
+package argon2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGenerateCrypt(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *Params
+		prefix string
+	}{
+		{
+			name:   "default params",
+			params: DefaultParams,
+			prefix: "$argon2id$v=19$m=65536,t=3,p=2$",
+		},
+		{
+			name:   "url safe legacy params",
+			params: &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, URLSafe: true},
+			prefix: "$argon2id$v=19$m=8192,t=1,p=1$",
+		},
+		{
+			name:   "argon2i",
+			params: &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Variant: Argon2i},
+			prefix: "$argon2i$v=19$m=8192,t=1,p=1$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := GenerateCrypt([]byte("shadow-password"), tt.params)
+			if err != nil {
+				t.Fatalf("GenerateCrypt() error = %v", err)
+			}
+			if !bytes.HasPrefix(hash, []byte(tt.prefix)) {
+				t.Errorf("GenerateCrypt() got = %s, want prefix %s", hash, tt.prefix)
+			}
+			// /etc/shadow fields are separated by ":"
+			if bytes.ContainsAny(hash, ":-_") {
+				t.Errorf("GenerateCrypt() got = %s, want standard base64 without \":\"", hash)
+			}
+			if err := CompareCrypt(hash, []byte("shadow-password")); err != nil {
+				t.Errorf("CompareCrypt() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateCrypt_Extensions(t *testing.T) {
+	base := Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+
+	tests := []struct {
+		name   string
+		modify func(p *Params)
+	}{
+		{name: "key id", modify: func(p *Params) { p.KeyID = "k1" }},
+		{name: "associated data", modify: func(p *Params) { p.Data = "user" }},
+		{name: "pre-hash", modify: func(p *Params) { p.PreHash = PreHashSHA512 }},
+		{name: "normalization", modify: func(p *Params) { p.Normalization = NFKC }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := base
+			tt.modify(&p)
+			if hash, err := GenerateCrypt([]byte("shadow-password"), &p); err != ErrUnsupportedParams {
+				t.Errorf("GenerateCrypt() = %s, %v, want %v", hash, err, ErrUnsupportedParams)
+			}
+		})
+	}
+}
+
+func TestCompareCrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			name:     "argon2id",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password"),
+		},
+		{
+			name:     "mismatched password",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password1"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "locked account",
+			hash:     []byte("!$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "legacy format",
+			hash:     []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+			password: []byte("qwerty123"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "encoder id",
+			hash:     []byte("{ARGON2}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "sha512-crypt",
+			hash:     []byte("$6$saltsalt$hash"),
+			password: []byte("dotnet-password"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareCrypt(tt.hash, tt.password); err != tt.wantErr {
+				t.Errorf("CompareCrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
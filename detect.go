@@ -0,0 +1,114 @@
+package argon2
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Scheme identifies the scheme of a stored password hash.
+type Scheme int
+
+const (
+	// SchemeUnknown is a hash of an unrecognized scheme.
+	SchemeUnknown Scheme = iota
+	// SchemeArgon2 is an argon2 hash in the legacy format of the package.
+	SchemeArgon2
+	// SchemeArgon2PHC is an argon2 hash in the PHC string format, including
+	// the Django and Spring Security variants of it.
+	SchemeArgon2PHC
+	// SchemeDual is a record of GenerateDualHash.
+	SchemeDual
+	// SchemeWrapped is a legacy hash wrapped with argon2 by WrapHash.
+	SchemeWrapped
+	// SchemeBcrypt is a bcrypt hash.
+	SchemeBcrypt
+	// SchemeScrypt is a scrypt hash in the format of simple-scrypt.
+	SchemeScrypt
+	// SchemeDjangoPBKDF2 is a PBKDF2 hash of Django.
+	SchemeDjangoPBKDF2
+	// SchemeASPNetIdentity is a PBKDF2 hash of ASP.NET Identity.
+	SchemeASPNetIdentity
+	// SchemeUnixCrypt is an MD5-crypt, SHA256-crypt or SHA512-crypt hash.
+	SchemeUnixCrypt
+	// SchemeRegistered is a hash of a scheme registered with RegisterVerifier.
+	SchemeRegistered
+	// SchemeEncrypted is a hash encrypted by EncryptHash.
+	SchemeEncrypted
+)
+
+var schemeNames = map[Scheme]string{
+	SchemeUnknown:        "unknown",
+	SchemeArgon2:         "argon2",
+	SchemeArgon2PHC:      "argon2-phc",
+	SchemeDual:           "dual",
+	SchemeWrapped:        "wrapped",
+	SchemeBcrypt:         "bcrypt",
+	SchemeScrypt:         "scrypt",
+	SchemeDjangoPBKDF2:   "django-pbkdf2",
+	SchemeASPNetIdentity: "aspnet-identity",
+	SchemeUnixCrypt:      "unix-crypt",
+	SchemeRegistered:     "registered",
+	SchemeEncrypted:      "encrypted",
+}
+
+// String returns the name of the scheme, e.g. "bcrypt".
+func (s Scheme) String() string {
+	if name, ok := schemeNames[s]; ok {
+		return name
+	}
+	return "Scheme(" + strconv.Itoa(int(s)) + ")"
+}
+
+// IsArgon2Hash reports whether the stored hash looks like an argon2 hash, in
+// the legacy format of the package or in the PHC string format, judging by
+// its prefix only. Use ValidateEncodedHash to check the hash itself.
+func IsArgon2Hash(hash []byte) bool {
+	s := DetectAlgorithm(hash)
+	return s == SchemeArgon2 || s == SchemeArgon2PHC
+}
+
+// DetectAlgorithm returns the scheme of the stored hash judging by its
+// prefix, as VerifyAnyScheme does to pick a verifier, without decoding it.
+func DetectAlgorithm(hash []byte) Scheme {
+	if bytes.HasPrefix(hash, []byte(dualPrefix)) {
+		return SchemeDual
+	}
+
+	id := trimEncoderID(hash)
+	if bytes.HasPrefix(id, []byte("$argon2")) || bytes.HasPrefix(id, []byte(djangoPrefix+"$argon2")) {
+		return SchemeArgon2PHC
+	}
+	for _, name := range variantNames {
+		if bytes.HasPrefix(id, []byte(name+"$")) {
+			return SchemeArgon2
+		}
+	}
+
+	verifiersMu.RLock()
+	for prefix := range verifiers {
+		if bytes.HasPrefix(hash, []byte(prefix)) {
+			verifiersMu.RUnlock()
+			return SchemeRegistered
+		}
+	}
+	verifiersMu.RUnlock()
+
+	switch {
+	case isEncrypted(hash):
+		return SchemeEncrypted
+	case isWrapped(hash):
+		return SchemeWrapped
+	case isBcrypt(hash):
+		return SchemeBcrypt
+	case isScrypt(hash):
+		return SchemeScrypt
+	case isDjangoPBKDF2(hash):
+		return SchemeDjangoPBKDF2
+	case isASPNetIdentity(hash):
+		return SchemeASPNetIdentity
+	case isUnixCrypt(hash):
+		return SchemeUnixCrypt
+	default:
+		return SchemeUnknown
+	}
+}
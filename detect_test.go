@@ -0,0 +1,42 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestDetectAlgorithm(t *testing.T) {
+	RegisterVerifier("{CORP-SHA256}", VerifierFunc(func(hash, password []byte) error { return nil }))
+	defer UnregisterVerifier("{CORP-SHA256}")
+
+	tests := []struct {
+		name   string
+		hash   string
+		want   Scheme
+		argon2 bool
+	}{
+		{name: "legacy format", hash: "argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU", want: SchemeArgon2, argon2: true},
+		{name: "phc format", hash: "$argon2i$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$wWKIMhR9lyDFvRz9YTZweHKfbftvj+qf+YFY4NeBbtA", want: SchemeArgon2PHC, argon2: true},
+		{name: "django format", hash: "argon2$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5a2V5a2V5a2V5", want: SchemeArgon2PHC, argon2: true},
+		{name: "spring security", hash: "{argon2}$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5a2V5a2V5a2V5", want: SchemeArgon2PHC, argon2: true},
+		{name: "dual record", hash: "{DUAL}$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5|$2b$04$salt", want: SchemeDual},
+		{name: "wrapped", hash: "{WRAP:$2b$10$salt}$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5", want: SchemeWrapped},
+		{name: "encrypted", hash: "{AEAD:2024}c2FsdA", want: SchemeEncrypted},
+		{name: "bcrypt", hash: "$2b$05$abcdefghijklmnopqrstuuHIrMEWpUCQe2YqFR3sXwQ75u4od..9q", want: SchemeBcrypt},
+		{name: "scrypt", hash: "16384$8$1$73696d706c652d7363727970742d3136$dea62b5899c96ae3f22eec8f08b540f07d1ca8dd0e2c503654ed32be01c8c7e2", want: SchemeScrypt},
+		{name: "django pbkdf2", hash: "pbkdf2_sha256$1000$djangosalt123456$QSt1DbmULoHfp4LOVhZ+jIEQsbsweVrWOSj+Mr/RM7A=", want: SchemeDjangoPBKDF2},
+		{name: "aspnet identity", hash: "AQAAAAEAACcQAAAAEGFzcG5ldC1zYWx0LTAwMTa/rguuc7ASvOnm/m8zTWUEDyUiwvucM9J1/wrfStMYOQ==", want: SchemeASPNetIdentity},
+		{name: "sha512-crypt", hash: "$6$shadowsalt$TnisS85/xIP2CrQ4q2tTzuYWga6aBhBGu2nFGm3bl6n56ZpqfZR.EAFWy8BXRDUhSHMvsFxqaK7QctsknbtJO0", want: SchemeUnixCrypt},
+		{name: "registered", hash: "{CORP-SHA256}c2FsdA==", want: SchemeRegistered},
+		{name: "unknown", hash: "dwiehduwehc8wh", want: SchemeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectAlgorithm([]byte(tt.hash)); got != tt.want {
+				t.Errorf("DetectAlgorithm() got = %v, want %v", got, tt.want)
+			}
+			if got := IsArgon2Hash([]byte(tt.hash)); got != tt.argon2 {
+				t.Errorf("IsArgon2Hash() got = %v, want %v", got, tt.argon2)
+			}
+		})
+	}
+}
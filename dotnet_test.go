@@ -0,0 +1,95 @@
+package argon2
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestIsopohCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		secret   []byte
+		wantErr  bool
+	}{
+		{
+			// Argon2.Hash() with the Argon2Config defaults
+			name:     "argon2id defaults",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password"),
+		},
+		{
+			// Argon2.Hash() with Type = Argon2Type.DataIndependentAddressing
+			name:     "argon2i",
+			hash:     []byte("$argon2i$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$0tUVteCsZu/B+qo6QVg4mcJh7OvToMMQdYWcfXWkmaE"),
+			password: []byte("dotnet-password"),
+		},
+		{
+			// Argon2.Hash() with MemoryCost = 4096 and Secret set
+			name:     "argon2id with secret",
+			hash:     []byte("$argon2id$v=19$m=4096,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$9QZKXbgF2UU+VDrL5nx6b2rLOGZT5shxO2dAJF9JmUE"),
+			password: []byte("dotnet-password"),
+			secret:   []byte("isopoh-secret"),
+		},
+		{
+			name:     "wrong password",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password1"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPasswordWithSecret(tt.hash, tt.password, tt.secret); (err != nil) != tt.wantErr {
+				t.Errorf("CompareHashAndPasswordWithSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKonsciousCompatibility(t *testing.T) {
+	// Konscious returns the raw key only, commonly stored base64 encoded
+	// next to the salt
+	salt, _ := base64.StdEncoding.DecodeString("ZG90bmV0LXNhbHQtMDAxNg==")
+	p := &Params{Memory: 8192, Iterations: 4, Parallelism: 8, KeyLength: 16}
+
+	tests := []struct {
+		name     string
+		key      string
+		password []byte
+		secret   []byte
+		wantErr  bool
+	}{
+		{
+			// new Argon2id(password) { DegreeOfParallelism = 8, MemorySize = 8192, Iterations = 4 }.GetBytes(16)
+			name:     "argon2id",
+			key:      "Qnupp9OVGTVCtFYyWu4Y0w==",
+			password: []byte("dotnet-password"),
+		},
+		{
+			// same as above, with KnownSecret set
+			name:     "argon2id with known secret",
+			key:      "W3n7dCoLpaAwY5ibELR6IQ==",
+			password: []byte("dotnet-password"),
+			secret:   []byte("konscious-known-secret"),
+		},
+		{
+			name:     "missing known secret",
+			key:      "W3n7dCoLpaAwY5ibELR6IQ==",
+			password: []byte("dotnet-password"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := base64.StdEncoding.DecodeString(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyRawWithSecret(tt.password, salt, key, tt.secret, p); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyRawWithSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package argon2
+
+import (
+	"strings"
+)
+
+// dovecotSchemes maps the variants Dovecot supports to its password schemes.
+var dovecotSchemes = map[Variant]string{
+	Argon2id: "ARGON2ID",
+	Argon2i:  "ARGON2I",
+}
+
+// WrapDovecot returns the encoded hash in Dovecot's password scheme notation,
+// e.g. "{ARGON2ID}$argon2id$v=19$...", ready to be written into a Dovecot
+// userdb. Dovecot verifies hashes with libsodium, so the hash is re-encoded
+// in the PHC format if needed. It returns ErrUnknownAlgorithm for Argon2d
+// hashes, ErrIncompatibleVersion for hashes of argon2 version 0x10 and
+// ErrUnsupportedParams for hashes with a key ID, associated data, a
+// pre-hash function or a normalization, which Dovecot doesn't support.
+func WrapDovecot(encodedHash []byte) ([]byte, error) {
+	p, salt, key, err := decodeHash(encodedHash)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme, ok := dovecotSchemes[p.Variant]
+	if !ok {
+		return nil, ErrUnknownAlgorithm
+	}
+	if p.Version != 0 {
+		return nil, ErrIncompatibleVersion
+	}
+	if p.extended() {
+		return nil, ErrUnsupportedParams
+	}
+	p.Format, p.URLSafe = FormatPHC, false
+
+	dst := make([]byte, 0, len(scheme)+2+len(encodedHash))
+	dst = append(dst, '{')
+	dst = append(dst, scheme...)
+	dst = append(dst, '}')
+	return appendEncoded(dst, p.Format, p, salt, key), nil
+}
+
+// UnwrapDovecot returns the encoded hash of a password in Dovecot's password
+// scheme notation, e.g. "{ARGON2ID}$argon2id$v=19$...", without the scheme.
+// It returns ErrUnknownAlgorithm if the scheme isn't an argon2 one, or doesn't
+// match the variant of the hash.
+func UnwrapDovecot(password []byte) ([]byte, error) {
+	scheme, encodedHash, err := splitScheme(password)
+	if err != nil {
+		return nil, err
+	}
+
+	p, _, _, err := decodeHashInto(nil, encodedHash, ParseStrict)
+	if err != nil {
+		return nil, err
+	}
+	if name, ok := dovecotSchemes[p.Variant]; !ok || !strings.EqualFold(name, string(scheme)) {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	return encodedHash, nil
+}
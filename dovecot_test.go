@@ -0,0 +1,117 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWrapDovecot(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    []byte
+		want    string
+		wantErr error
+	}{
+		{
+			name: "phc argon2id",
+			hash: []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			want: "{ARGON2ID}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w",
+		},
+		{
+			name: "phc argon2i",
+			hash: []byte("$argon2i$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$0tUVteCsZu/B+qo6QVg4mcJh7OvToMMQdYWcfXWkmaE"),
+			want: "{ARGON2I}$argon2i$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$0tUVteCsZu/B+qo6QVg4mcJh7OvToMMQdYWcfXWkmaE",
+		},
+		{
+			name: "legacy format",
+			hash: []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+			want: "{ARGON2ID}$argon2id$v=19$m=65536,t=3,p=2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		},
+		{
+			name:    "argon2d",
+			hash:    []byte("$argon2d$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$lV5dWxY6G2C7o1/DbQSWR0+6T2tZrVNihmbwf7L5Pq8"),
+			wantErr: ErrUnknownAlgorithm,
+		},
+		{
+			name:    "pre-hash",
+			hash:    []byte("$argon2id$v=19$m=65536,t=2,p=1,ph=sha512$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+			wantErr: ErrUnsupportedParams,
+		},
+		{
+			name:    "version 16",
+			hash:    []byte("$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$mA69JKTmZ/FjRvnUp4sXVyh4NhPgzG+xfC7IhLFkNd8"),
+			wantErr: ErrIncompatibleVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WrapDovecot(tt.hash)
+			if err != tt.wantErr {
+				t.Errorf("WrapDovecot() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("WrapDovecot() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnwrapDovecot(t *testing.T) {
+	tests := []struct {
+		name     string
+		password []byte
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "argon2id",
+			password: []byte("{ARGON2ID}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			want:     "$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w",
+		},
+		{
+			name:     "lower case scheme",
+			password: []byte("{argon2i}$argon2i$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$0tUVteCsZu/B+qo6QVg4mcJh7OvToMMQdYWcfXWkmaE"),
+			want:     "$argon2i$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$0tUVteCsZu/B+qo6QVg4mcJh7OvToMMQdYWcfXWkmaE",
+		},
+		{
+			name:     "mismatched scheme",
+			password: []byte("{ARGON2I}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			wantErr:  ErrUnknownAlgorithm,
+		},
+		{
+			name:     "other scheme",
+			password: []byte("{SHA512-CRYPT}$6$rounds=5000$salt$hash"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "missing scheme",
+			password: []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "doubled scheme",
+			password: []byte("{ARGON2ID}{ARGON2ID}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnwrapDovecot(tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UnwrapDovecot() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("UnwrapDovecot() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareDovecotPassword(t *testing.T) {
+	password := []byte("{ARGON2ID}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w")
+	if err := CompareHashAndPassword(password, []byte("dotnet-password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+}
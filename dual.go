@@ -0,0 +1,94 @@
+package argon2
+
+import (
+	"bytes"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dualPrefix starts the records encoded by DualHash.Encode.
+const dualPrefix = "{DUAL}"
+
+// dualSeparator separates the argon2 and legacy hashes of a dual record.
+// None of the supported encodings uses it.
+const dualSeparator = '|'
+
+// LegacyHasher generates the legacy hash of a password, written next to the
+// argon2 one by GenerateDualHash.
+type LegacyHasher func(password []byte) ([]byte, error)
+
+// BcryptHasher returns a LegacyHasher generating bcrypt hashes with the given
+// cost, as golang.org/x/crypto/bcrypt.GenerateFromPassword does.
+func BcryptHasher(cost int) LegacyHasher {
+	return func(password []byte) ([]byte, error) {
+		return bcrypt.GenerateFromPassword(password, cost)
+	}
+}
+
+// DualHash holds both the argon2 and legacy hashes of a password. It allows
+// services that only understand the legacy scheme to keep working during
+// a migration window, while the others verify the stronger argon2 hash.
+type DualHash struct {
+	Argon2 []byte // The argon2 hash, encoded as GenerateFromPassword does
+	Legacy []byte // The legacy hash, e.g. a bcrypt one
+}
+
+// GenerateDualHash hashes the password with both argon2, using the parameters
+// provided, and the legacy hasher.
+func GenerateDualHash(password []byte, p *Params, legacy LegacyHasher) (*DualHash, error) {
+	argon2Hash, err := GenerateFromPassword(password, p)
+	if err != nil {
+		return nil, err
+	}
+
+	legacyHash, err := legacy(password)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(legacyHash, dualSeparator) >= 0 {
+		return nil, ErrUnsupportedFormat
+	}
+
+	return &DualHash{Argon2: argon2Hash, Legacy: legacyHash}, nil
+}
+
+// ParseDualHash decodes a record encoded by DualHash.Encode.
+func ParseDualHash(record []byte) (*DualHash, error) {
+	if !bytes.HasPrefix(record, []byte(dualPrefix)) {
+		return nil, ErrInvalidHash
+	}
+	record = record[len(dualPrefix):]
+
+	i := bytes.IndexByte(record, dualSeparator)
+	if i < 1 || i == len(record)-1 {
+		return nil, ErrInvalidHash
+	}
+
+	return &DualHash{Argon2: record[:i], Legacy: record[i+1:]}, nil
+}
+
+// Encode returns the single record holding both hashes,
+// "{DUAL}<argon2 hash>|<legacy hash>". Legacy services can read their
+// hash after the last "|".
+func (d *DualHash) Encode() []byte {
+	record := make([]byte, 0, len(dualPrefix)+len(d.Argon2)+1+len(d.Legacy))
+	record = append(record, dualPrefix...)
+	record = append(record, d.Argon2...)
+	record = append(record, dualSeparator)
+	return append(record, d.Legacy...)
+}
+
+// Compare compares the argon2 hash, the stronger one, with the possible
+// cleartext equivalent. The legacy hash isn't used.
+func (d *DualHash) Compare(password []byte) error {
+	return CompareHashAndPassword(d.Argon2, password)
+}
+
+// dualArgon2 returns the argon2 hash of a record encoded by DualHash.Encode,
+// or the hash itself if it isn't one.
+func dualArgon2(hash []byte) []byte {
+	if d, err := ParseDualHash(hash); err == nil {
+		return d.Argon2
+	}
+	return hash
+}
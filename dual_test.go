@@ -0,0 +1,111 @@
+package argon2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGenerateDualHash(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+	password := []byte("dual-password")
+
+	d, err := GenerateDualHash(password, p, BcryptHasher(bcrypt.MinCost))
+	if err != nil {
+		t.Fatalf("GenerateDualHash() error = %v", err)
+	}
+	if err := d.Compare(password); err != nil {
+		t.Errorf("Compare() error = %v", err)
+	}
+
+	record := d.Encode()
+	if !bytes.HasPrefix(record, []byte("{DUAL}$argon2id$")) {
+		t.Errorf("Encode() got = %s, want prefix {DUAL}$argon2id$", record)
+	}
+
+	// legacy services read the hash after the last "|"
+	legacy := record[bytes.LastIndexByte(record, '|')+1:]
+	if err := bcrypt.CompareHashAndPassword(legacy, password); err != nil {
+		t.Errorf("bcrypt.CompareHashAndPassword() error = %v", err)
+	}
+
+	got, err := ParseDualHash(record)
+	if err != nil {
+		t.Fatalf("ParseDualHash() error = %v", err)
+	}
+	if !bytes.Equal(got.Argon2, d.Argon2) || !bytes.Equal(got.Legacy, d.Legacy) {
+		t.Errorf("ParseDualHash() got = %+v, want %+v", got, d)
+	}
+
+	if err := VerifyAnyScheme(record, password); err != nil {
+		t.Errorf("VerifyAnyScheme() error = %v", err)
+	}
+	if err := VerifyAnyScheme(record, []byte("wrong-password")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("VerifyAnyScheme() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+
+	// the record is kept during the migration window
+	newHash, err := VerifyAndMigrate(record, password, p)
+	if err != nil || newHash != nil {
+		t.Errorf("VerifyAndMigrate() got = %s, error = %v, want nil, nil", newHash, err)
+	}
+}
+
+func TestGenerateDualHashErrors(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+	errLegacy := errors.New("legacy hasher failure")
+
+	tests := []struct {
+		name    string
+		params  *Params
+		legacy  LegacyHasher
+		wantErr error
+	}{
+		{
+			name:    "invalid params",
+			params:  &Params{},
+			legacy:  BcryptHasher(bcrypt.MinCost),
+			wantErr: ErrMemoryTooSmall,
+		},
+		{
+			name:    "legacy hasher failure",
+			params:  p,
+			legacy:  func([]byte) ([]byte, error) { return nil, errLegacy },
+			wantErr: errLegacy,
+		},
+		{
+			name:    "separator in legacy hash",
+			params:  p,
+			legacy:  func([]byte) ([]byte, error) { return []byte("a|b"), nil },
+			wantErr: ErrUnsupportedFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateDualHash([]byte("dual-password"), tt.params, tt.legacy); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GenerateDualHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDualHash(t *testing.T) {
+	tests := []struct {
+		name   string
+		record []byte
+	}{
+		{name: "missing prefix", record: []byte("$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5|$2b$04$salt")},
+		{name: "missing separator", record: []byte("{DUAL}$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5")},
+		{name: "missing legacy hash", record: []byte("{DUAL}$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5|")},
+		{name: "missing argon2 hash", record: []byte("{DUAL}|$2b$04$salt")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseDualHash(tt.record); err != ErrInvalidHash {
+				t.Errorf("ParseDualHash() error = %v, want %v", err, ErrInvalidHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package argon2
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// minVerifyDuration is the duration set with SetMinVerifyDuration.
+var minVerifyDuration atomic.Int64
+
+// SetMinVerifyDuration pads every verification of a password by the
+// package, e.g. by CompareHashAndPassword, VerifyRaw, VerifyAnyScheme or a
+// Hasher, to at least d of wall-clock time, whatever the outcome, safely
+// for concurrent use. Malformed hashes, unknown peppers and
+// mismatches then take as long as successful verifications, flattening the
+// timing differences observable by remote attackers. d should exceed the
+// duration of a verification, see EstimateDuration. Zero, the default,
+// disables the padding.
+func SetMinVerifyDuration(d time.Duration) {
+	minVerifyDuration.Store(int64(d))
+}
+
+// padVerify sleeps until the minimum verification duration has elapsed
+// since start. It is deferred by every exported function verifying
+// passwords, as defer padVerify(time.Now()), the internal ones aren't
+// padded.
+func padVerify(start time.Time) {
+	d := time.Duration(minVerifyDuration.Load())
+	if d <= 0 {
+		return
+	}
+	if remaining := d - time.Since(start); remaining > 0 {
+		time.Sleep(remaining)
+	}
+}
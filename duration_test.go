@@ -0,0 +1,99 @@
+package argon2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetMinVerifyDuration(t *testing.T) {
+	defer SetMinVerifyDuration(0)
+
+	const d = 100 * time.Millisecond
+	SetMinVerifyDuration(d)
+
+	hash := MustGenerateFromPassword([]byte("password"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	h, err := NewHasher(WithMemory(8*1024), WithIterations(1))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	parsed, err := ParseHash(hash)
+	if err != nil {
+		t.Fatalf("ParseHash() error = %v", err)
+	}
+	var registry ParamsRegistry
+	keys := &KeyRing{CurrentID: "k1", Keys: map[string][]byte{"k1": []byte("secret")}}
+
+	tests := []struct {
+		name   string
+		verify func() error
+	}{
+		{name: "match", verify: func() error { return CompareHashAndPassword(hash, []byte("password")) }},
+		{name: "mismatch", verify: func() error { return CompareHashAndPassword(hash, []byte("wrong")) }},
+		{name: "malformed hash", verify: func() error { return CompareHashAndPassword([]byte("malformed"), []byte("password")) }},
+		{name: "compare and update", verify: func() error {
+			_, err := CompareAndUpdate([]byte("malformed"), []byte("password"), nil)
+			return err
+		}},
+		{name: "hasher unknown key ID", verify: func() error { return h.Verify([]byte("{PEPPER:2024}"+string(hash)), []byte("password")) }},
+		{name: "crypt", verify: func() error { return CompareCrypt([]byte("malformed"), []byte("password")) }},
+		{name: "registry", verify: func() error {
+			_, _, err := registry.CompareAndUpdate([]byte("malformed"), []byte("password"), 1)
+			return err
+		}},
+		{name: "raw", verify: func() error { return VerifyRaw([]byte("password"), parsed.Salt(), parsed.Key(), nil) }},
+		{name: "parsed hash", verify: func() error { return parsed.Verify([]byte("wrong")) }},
+		{name: "any scheme legacy", verify: func() error { return VerifyAnyScheme([]byte("$2a$malformed"), []byte("password")) }},
+		{name: "migrate legacy", verify: func() error {
+			_, err := VerifyAndMigrate([]byte("$2a$malformed"), []byte("password"), nil)
+			return err
+		}},
+		{name: "encrypted undecryptable", verify: func() error {
+			return CompareEncryptedHashAndPassword([]byte("{AEAD:k1}malformed"), []byte("password"), keys)
+		}},
+		{name: "provider unknown key ID", verify: func() error {
+			return CompareHashAndPasswordWithProvider([]byte("$argon2id$v=19$m=8192,t=1,p=1,keyid=azI$c29tZXNhbHQ$a2V5a2V5a2V5a2V5a2V5aw"), []byte("password"), keys)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			_ = tt.verify()
+			if elapsed := time.Since(start); elapsed < d {
+				t.Errorf("verification took %v, want at least %v", elapsed, d)
+			}
+		})
+	}
+
+	SetMinVerifyDuration(0)
+	start := time.Now()
+	_ = CompareHashAndPassword([]byte("malformed"), []byte("password"))
+	if elapsed := time.Since(start); elapsed >= d {
+		t.Errorf("verification without padding took %v", elapsed)
+	}
+}
+
+func TestShadowVerifier_MinVerifyDuration(t *testing.T) {
+	defer SetMinVerifyDuration(0)
+
+	const d = 100 * time.Millisecond
+	SetMinVerifyDuration(d)
+
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hash := MustGenerateFromPassword([]byte("password"), p)
+
+	var current time.Duration
+	s := &ShadowVerifier{Candidate: p, Report: func(r ShadowResult) { current = r.Current }}
+
+	start := time.Now()
+	if err := s.CompareHashAndPassword(hash, []byte("password")); err != nil {
+		t.Fatalf("CompareHashAndPassword() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < d {
+		t.Errorf("verification took %v, want at least %v", elapsed, d)
+	}
+	s.Wait()
+
+	if current <= 0 || current >= d {
+		t.Errorf("ShadowResult.Current = %v, want the verification without the padding", current)
+	}
+}
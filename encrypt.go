@@ -0,0 +1,139 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// encryptedPrefix starts the hashes encrypted by EncryptHash,
+// "{AEAD:<key ID>}<Base64 of the nonce and ciphertext>".
+const encryptedPrefix = "{AEAD:"
+
+// encryptionKeyInfo is the HKDF info label of the encryption keys derived
+// from the secrets of a SecretProvider, separating them from other uses of
+// the secrets, e.g. as peppers.
+const encryptionKeyInfo = "argon2-hashing hash encryption v1"
+
+// EncryptHash encrypts the encoded hash with AES-256-GCM under the current
+// secret of the provider, for deployments requiring credentials to be both
+// hashed and encrypted at rest. The encrypted hash records the key ID of
+// the secret, so secrets can be rotated:
+//
+//	{AEAD:2024}pXh0b3...
+//
+// The encryption key is derived from the secret with HKDF-SHA256, so it is
+// independent of the peppers and argon2 secret keys the same secrets may
+// serve as. The hash is decrypted by DecryptHash, or verified directly by
+// CompareEncryptedHashAndPassword.
+func EncryptHash(hash []byte, secrets SecretProvider) ([]byte, error) {
+	keyID, secret := secrets.Current()
+	if len(secret) == 0 {
+		return nil, ErrUnknownKeyID
+	}
+	prefix, err := appendKeyID(nil, encryptedPrefix, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newHashAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := GenerateRandomBytes(uint32(aead.NonceSize()))
+	if err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nonce, nonce, hash, prefix)
+
+	enc := base64.RawStdEncoding
+	dst := make([]byte, len(prefix), len(prefix)+enc.EncodedLen(len(sealed)))
+	copy(dst, prefix)
+	return appendBase64(dst, enc, sealed), nil
+}
+
+// DecryptHash decrypts a hash encrypted by EncryptHash with the secret of
+// the provider of the recorded key ID. It returns ErrInvalidHash if the
+// hash isn't an encrypted one or fails to decrypt, e.g. if it was tampered
+// with, and the errors of the provider if the secret can't be found.
+func DecryptHash(encrypted []byte, secrets SecretProvider) ([]byte, error) {
+	keyID, data, ok := splitKeyID(encrypted, encryptedPrefix)
+	if !ok {
+		return nil, ErrInvalidHash
+	}
+	prefix := encrypted[:len(encrypted)-len(data)]
+
+	secret, err := secrets.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) == 0 {
+		return nil, ErrUnknownKeyID
+	}
+	aead, err := newHashAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(string(data))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidHash
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	hash, err := aead.Open(sealed[:0], nonce, sealed, prefix)
+	if err != nil {
+		return nil, ErrInvalidHash
+	}
+
+	return hash, nil
+}
+
+// CompareEncryptedHashAndPassword decrypts a hash encrypted by EncryptHash,
+// and compares it with the password like CompareHashAndPassword.
+func CompareEncryptedHashAndPassword(encrypted, password []byte, secrets SecretProvider) error {
+	defer padVerify(time.Now())
+
+	hash, err := DecryptHash(encrypted, secrets)
+	if err != nil {
+		return err
+	}
+
+	return CompareHashAndPassword(hash, password)
+}
+
+// WithHashEncryption has a Hasher encrypt the hashes it generates with
+// EncryptHash under the current secret of the provider, and decrypt them
+// when verifying. Hashes that aren't encrypted are still verified, and need
+// a rehash, as do the ones encrypted under another secret than the current
+// one. It only applies to a Hasher, HashPassword rejects it with
+// ErrInvalidParams.
+func WithHashEncryption(secrets SecretProvider) Option {
+	return func(o *options) { o.encryption = secrets }
+}
+
+// isEncrypted reports whether the hash was encrypted by EncryptHash.
+func isEncrypted(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte(encryptedPrefix))
+}
+
+// newHashAEAD returns the AES-256-GCM AEAD of the key derived from secret
+// with HKDF-SHA256.
+func newHashAEAD(secret []byte) (cipher.AEAD, error) {
+	key := make([]byte, 32)
+	defer wipe(key)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, []byte(encryptionKeyInfo)), key); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
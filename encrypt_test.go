@@ -0,0 +1,144 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"testing"
+)
+
+func TestEncryptHash(t *testing.T) {
+	keys := &KeyRing{
+		CurrentID: "2023",
+		Keys:      map[string][]byte{"2023": []byte("old-secret")},
+	}
+	hash := MustGenerateFromPassword([]byte("password"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+
+	encrypted, err := EncryptHash(hash, keys)
+	if err != nil {
+		t.Fatalf("EncryptHash() error = %v", err)
+	}
+	if !bytes.HasPrefix(encrypted, []byte("{AEAD:2023}")) || bytes.Contains(encrypted, hash) {
+		t.Errorf("EncryptHash() got = %s, want {AEAD:2023} and no plaintext", encrypted)
+	}
+	if got := DetectAlgorithm(encrypted); got != SchemeEncrypted {
+		t.Errorf("DetectAlgorithm() got = %v, want %v", got, SchemeEncrypted)
+	}
+
+	decrypted, err := DecryptHash(encrypted, keys)
+	if err != nil {
+		t.Fatalf("DecryptHash() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, hash) {
+		t.Errorf("DecryptHash() got = %s, want %s", decrypted, hash)
+	}
+	if err := CompareEncryptedHashAndPassword(encrypted, []byte("password"), keys); err != nil {
+		t.Errorf("CompareEncryptedHashAndPassword() error = %v", err)
+	}
+	if err := CompareEncryptedHashAndPassword(encrypted, []byte("wrong"), keys); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareEncryptedHashAndPassword() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+
+	tampered := append([]byte(nil), encrypted...)
+	tampered[len(tampered)-2] ^= 'A' ^ 'B'
+	relabeled := append([]byte("{AEAD:2024}"), encrypted[len("{AEAD:2023}"):]...)
+	keys.Keys["2024"] = []byte("old-secret")
+
+	tests := []struct {
+		name      string
+		encrypted []byte
+		wantErr   error
+	}{
+		{name: "tampered", encrypted: tampered, wantErr: ErrInvalidHash},
+		{name: "relabeled key ID", encrypted: relabeled, wantErr: ErrInvalidHash},
+		{name: "unknown key ID", encrypted: append([]byte("{AEAD:2022}"), encrypted[len("{AEAD:2023}"):]...), wantErr: ErrUnknownKeyID},
+		{name: "not encrypted", encrypted: hash, wantErr: ErrInvalidHash},
+		{name: "truncated", encrypted: []byte("{AEAD:2023}AAAA"), wantErr: ErrInvalidHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecryptHash(tt.encrypted, keys); err != tt.wantErr {
+				t.Errorf("DecryptHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasher_WithHashEncryption(t *testing.T) {
+	keys := &KeyRing{
+		CurrentID: "2023",
+		Keys:      map[string][]byte{"2023": []byte("old-secret")},
+	}
+	h, err := NewHasher(WithMemory(8*1024), WithIterations(1), WithHashEncryption(keys))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+
+	oldHash, err := h.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if !bytes.HasPrefix(oldHash, []byte("{AEAD:2023}")) {
+		t.Errorf("Hash() got = %s, want prefix {AEAD:2023}", oldHash)
+	}
+	if err := h.Verify(oldHash, []byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if rehash, err := h.NeedsRehash(oldHash); err != nil || rehash {
+		t.Errorf("NeedsRehash() got = %v, error = %v, want false, nil", rehash, err)
+	}
+
+	// plain hashes still verify, and need to be encrypted
+	plain := MustGenerateFromPassword([]byte("password"), h.Params())
+	if err := h.Verify(plain, []byte("password")); err != nil {
+		t.Errorf("Verify() of a plain hash error = %v", err)
+	}
+	if rehash, err := h.NeedsRehash(plain); err != nil || !rehash {
+		t.Errorf("NeedsRehash() of a plain hash got = %v, error = %v, want true, nil", rehash, err)
+	}
+
+	// rotate the secret
+	keys.Keys["2024"] = []byte("new-secret")
+	keys.CurrentID = "2024"
+
+	newHash, err := h.CompareAndUpdate(oldHash, []byte("password"))
+	if err != nil {
+		t.Fatalf("CompareAndUpdate() error = %v", err)
+	}
+	if !bytes.HasPrefix(newHash, []byte("{AEAD:2024}")) {
+		t.Errorf("CompareAndUpdate() got = %s, want prefix {AEAD:2024}", newHash)
+	}
+	if err := h.Verify(newHash, []byte("wrong")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("Verify() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+}
+
+func TestEncryptHash_KeySeparation(t *testing.T) {
+	keys := &KeyRing{CurrentID: "k1", Keys: map[string][]byte{"k1": []byte("secret")}}
+	hash := MustGenerateFromPassword([]byte("password"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+
+	encrypted, err := EncryptHash(hash, keys)
+	if err != nil {
+		t.Fatalf("EncryptHash() error = %v", err)
+	}
+	_, data, _ := splitKeyID(encrypted, encryptedPrefix)
+	sealed, err := base64.RawStdEncoding.DecodeString(string(data))
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+
+	// the pepper of the label under the same secret doesn't decrypt the hash
+	block, err := aes.NewCipher(applyPepper([]byte(encryptionKeyInfo), keys.Keys["k1"]))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("NewGCM() error = %v", err)
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	if _, err := aead.Open(nil, nonce, sealed, encrypted[:len(encrypted)-len(data)]); err == nil {
+		t.Error("Open() with the pepper output succeeded, want an error")
+	}
+}
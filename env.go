@@ -0,0 +1,79 @@
+package argon2
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ParamsFromEnv reads the parameters from the environment variables of
+// the given prefix, e.g. "ARGON2" (the default for an empty prefix):
+// ARGON2_MEMORY, ARGON2_ITERATIONS, ARGON2_PARALLELISM, ARGON2_SALT_LENGTH,
+// ARGON2_KEY_LENGTH, ARGON2_VARIANT (e.g. "argon2id") and ARGON2_FORMAT
+// (e.g. "phc"). The memory is in KiB, unless it has a KiB, MiB or GiB
+// suffix, e.g. "64MiB". Unset variables are taken from the Defaults, and
+// the parameters are validated with Check. The errors of malformed
+// variables name them and wrap ErrInvalidParams.
+func ParamsFromEnv(prefix string) (*Params, error) {
+	if prefix == "" {
+		prefix = "ARGON2"
+	}
+	p := Defaults()
+
+	for _, v := range []struct {
+		name  string
+		parse func(string) error
+	}{
+		{"MEMORY", func(s string) (err error) { p.Memory, err = parseMemory(s); return }},
+		{"ITERATIONS", func(s string) (err error) { p.Iterations, err = parseUint32(s); return }},
+		{"PARALLELISM", func(s string) error {
+			v, err := strconv.ParseUint(s, 10, 8)
+			p.Parallelism = uint8(v)
+			return err
+		}},
+		{"SALT_LENGTH", func(s string) (err error) { p.SaltLength, err = parseUint32(s); return }},
+		{"KEY_LENGTH", func(s string) (err error) { p.KeyLength, err = parseUint32(s); return }},
+		{"VARIANT", func(s string) (err error) { p.Variant, err = parseVariant([]byte(strings.ToLower(s))); return }},
+		{"FORMAT", func(s string) (err error) { p.Format, err = parseFormat(strings.ToLower(s)); return }},
+	} {
+		name := prefix + "_" + v.name
+		s, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := v.parse(strings.TrimSpace(s)); err != nil {
+			if _, ok := err.(*strconv.NumError); ok {
+				err = ErrInvalidParams
+			}
+			return nil, fmt.Errorf("argon2: %s: %w", name, err)
+		}
+	}
+
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// parseMemory parses an amount of memory in KiB, or with a KiB, MiB or GiB suffix.
+func parseMemory(s string) (uint32, error) {
+	shift := uint(0)
+	for suffix, sh := range map[string]uint{"KiB": 0, "MiB": 10, "GiB": 20} {
+		if strings.HasSuffix(s, suffix) {
+			s, shift = strings.TrimSpace(strings.TrimSuffix(s, suffix)), sh
+			break
+		}
+	}
+
+	v, err := parseUint32(s)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(v)<<shift > 1<<32-1 {
+		return 0, ErrMemoryTooLarge
+	}
+
+	return v << shift, nil
+}
@@ -0,0 +1,80 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParamsFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		env     map[string]string
+		want    *Params
+		wantErr error
+	}{
+		{
+			name: "unset",
+			want: DefaultParams,
+		},
+		{
+			name: "all variables",
+			env: map[string]string{
+				"ARGON2_MEMORY":      "19456",
+				"ARGON2_ITERATIONS":  "2",
+				"ARGON2_PARALLELISM": "1",
+				"ARGON2_SALT_LENGTH": "16",
+				"ARGON2_KEY_LENGTH":  "32",
+				"ARGON2_VARIANT":     "argon2i",
+				"ARGON2_FORMAT":      "PHC",
+			},
+			want: &Params{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC, Variant: Argon2i},
+		},
+		{
+			name:   "custom prefix and memory unit",
+			prefix: "AUTH_HASH",
+			env:    map[string]string{"AUTH_HASH_MEMORY": "128MiB", "ARGON2_MEMORY": "1"},
+			want:   &Params{Memory: 128 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+		},
+		{
+			name: "memory in GiB",
+			env:  map[string]string{"ARGON2_MEMORY": "1 GiB"},
+			want: &Params{Memory: 1024 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+		},
+		{
+			name:    "memory in bytes",
+			env:     map[string]string{"ARGON2_MEMORY": "64MB"},
+			wantErr: ErrInvalidParams,
+		},
+		{
+			name:    "too small memory",
+			env:     map[string]string{"ARGON2_MEMORY": "64"},
+			wantErr: ErrMemoryTooSmall,
+		},
+		{
+			name:    "malformed iterations",
+			env:     map[string]string{"ARGON2_ITERATIONS": "three"},
+			wantErr: ErrInvalidParams,
+		},
+		{
+			name:    "unknown variant",
+			env:     map[string]string{"ARGON2_VARIANT": "scrypt"},
+			wantErr: ErrUnknownAlgorithm,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := ParamsFromEnv(tt.prefix)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParamsFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != nil && (got == nil || *got != *tt.want) {
+				t.Errorf("ParamsFromEnv() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package argon2
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+)
+
+// ConstantTimeEqual reports whether the secrets a and b are equal, e.g. an
+// API key or a token and the expected one, in constant time. Unlike
+// subtle.ConstantTimeCompare, it doesn't return early for secrets of
+// different lengths: both are hashed with SHA-256, and the digests compared.
+// The hashing time still grows with the lengths of a and b, so the timing
+// may reveal their lengths, to the 64 byte block, but not where they differ.
+// Passwords should be hashed with argon2 instead.
+func ConstantTimeEqual(a, b []byte) bool {
+	ha := sha256.Sum256(a)
+	hb := sha256.Sum256(b)
+
+	// The lengths are compared too, as a safeguard against collisions
+	var la, lb [8]byte
+	binary.BigEndian.PutUint64(la[:], uint64(len(a)))
+	binary.BigEndian.PutUint64(lb[:], uint64(len(b)))
+
+	equal := subtle.ConstantTimeCompare(ha[:], hb[:])
+	equal &= subtle.ConstantTimeCompare(la[:], lb[:])
+
+	return equal == 1
+}
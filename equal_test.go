@@ -0,0 +1,25 @@
+package argon2
+
+import "testing"
+
+func TestConstantTimeEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []byte
+		want bool
+	}{
+		{name: "equal", a: []byte("sk_live_1234"), b: []byte("sk_live_1234"), want: true},
+		{name: "different", a: []byte("sk_live_1234"), b: []byte("sk_live_1235")},
+		{name: "prefix", a: []byte("sk_live_1234"), b: []byte("sk_live_123")},
+		{name: "empty and nil", a: []byte{}, b: nil, want: true},
+		{name: "empty", a: nil, b: []byte("sk_live_1234")},
+		{name: "secure bytes", a: NewSecureBytes("token"), b: []byte("token"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConstantTimeEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("ConstantTimeEqual() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
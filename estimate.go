@@ -0,0 +1,33 @@
+package argon2
+
+import (
+	"time"
+)
+
+// EstimateDuration returns how long hashing a password with the parameters
+// provided takes on the current host, so services can sanity-check their
+// configuration at startup. The result is an extrapolation, not a
+// measurement of the full hash: a single pass over the memory is timed and
+// multiplied by the number of iterations. Measuring it takes about
+// 1/Iterations of the time of a hash. A nil p stands for the Defaults.
+func EstimateDuration(p *Params) (time.Duration, error) {
+	p = orDefault(p)
+	if err := p.Check(); err != nil {
+		return 0, err
+	}
+
+	single := *p
+	single.Iterations = 1
+
+	return measure(&single) * time.Duration(p.Iterations), nil
+}
+
+// measure returns how long deriving a key with the parameters provided takes.
+func measure(p *Params) time.Duration {
+	salt := make([]byte, p.SaltLength)
+
+	start := time.Now()
+	p.Variant.deriveKey([]byte("password"), salt, nil, p)
+
+	return time.Since(start)
+}
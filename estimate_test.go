@@ -0,0 +1,41 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestEstimateDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *Params
+		wantErr bool
+	}{
+		{
+			name:   "low memory params",
+			params: LowMemory(),
+		},
+		{
+			name:   "many iterations",
+			params: &Params{Memory: 8 * 1024, Iterations: 100, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		},
+		{
+			name: "nil params",
+		},
+		{
+			name:    "invalid params",
+			params:  &Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EstimateDuration(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EstimateDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got <= 0 {
+				t.Errorf("EstimateDuration() got = %v, want a positive duration", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package filepepper provides an argon2.SecretProvider reading the peppers
+// from files, e.g. Docker or Kubernetes secrets mounted in a directory, for
+// teams without a key management service.
+//
+// Every file of the directory holds a pepper, and its name is the key ID of
+// the pepper, e.g. /run/secrets/peppers/2024-01. Trailing newlines are
+// ignored. Hidden files and directories, such as the ones Kubernetes uses
+// to swap the mounted files atomically, are skipped.
+//
+// The current pepper, the one of new hashes, is the given one, or the last
+// one in lexical order if none is given, so peppers can be rotated by
+// adding a file named after a date, without a restart:
+//
+//	provider, err := filepepper.Watch("/run/secrets/peppers", "", time.Minute, log.Println)
+//	...
+//	defer provider.Close()
+//	hasher, err := argon2.NewHasher(argon2.WithSecretProvider(provider))
+package filepepper
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// ErrNoPeppers is returned when the directory holds no pepper.
+var ErrNoPeppers = errors.New("filepepper: no pepper files found")
+
+// keySet is a set of peppers read from the directory.
+type keySet struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+// Provider is an argon2.SecretProvider of peppers read from the files of
+// a directory. It is safe for concurrent use.
+type Provider struct {
+	dir       string
+	currentID string
+	onError   func(error)
+	keys      atomic.Pointer[keySet]
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Open reads the peppers from the files of the directory, using the one of
+// currentID for new hashes, or the last one if currentID is empty.
+func Open(dir, currentID string) (*Provider, error) {
+	p := &Provider{dir: dir, currentID: currentID}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Watch is like Open, but reads the files again every interval and on
+// SIGHUP, so peppers can be added without a restart. The errors of reading
+// them again, e.g. a missing current pepper, are passed to onError if it
+// isn't nil, and the previous peppers stay active.
+func Watch(dir, currentID string, interval time.Duration, onError func(error)) (*Provider, error) {
+	p, err := Open(dir, currentID)
+	if err != nil {
+		return nil, err
+	}
+	p.onError = onError
+	p.done = make(chan struct{})
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer signal.Stop(hup)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-hup:
+			case <-p.done:
+				return
+			}
+			if err := p.Reload(); err != nil && p.onError != nil {
+				p.onError(err)
+			}
+		}
+	}()
+
+	return p, nil
+}
+
+// Close stops watching the directory, if it is watched.
+func (p *Provider) Close() {
+	if p.done == nil {
+		return
+	}
+	close(p.done)
+	p.wg.Wait()
+}
+
+// Reload reads the peppers from the files again. On error, the previous
+// peppers stay active.
+func (p *Provider) Reload() error {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return err
+	}
+
+	set := &keySet{currentID: p.currentID, keys: make(map[string][]byte)}
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		// os.Stat follows the symlinks of mounted secrets
+		path := filepath.Join(p.dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		secret, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		secret = bytes.TrimRight(secret, "\r\n")
+		if len(secret) == 0 {
+			continue
+		}
+
+		set.keys[name] = secret
+		ids = append(ids, name)
+	}
+
+	if len(ids) == 0 {
+		return ErrNoPeppers
+	}
+	if set.currentID == "" {
+		sort.Strings(ids)
+		set.currentID = ids[len(ids)-1]
+	}
+	if _, ok := set.keys[set.currentID]; !ok {
+		return argon2.ErrUnknownKeyID
+	}
+
+	p.keys.Store(set)
+	return nil
+}
+
+// Get implements the argon2.SecretProvider interface.
+func (p *Provider) Get(keyID string) ([]byte, error) {
+	secret, ok := p.keys.Load().keys[keyID]
+	if !ok {
+		return nil, argon2.ErrUnknownKeyID
+	}
+	return secret, nil
+}
+
+// Current implements the argon2.SecretProvider interface.
+func (p *Provider) Current() (keyID string, secret []byte) {
+	set := p.keys.Load()
+	return set.currentID, set.keys[set.currentID]
+}
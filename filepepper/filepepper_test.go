@@ -0,0 +1,62 @@
+package filepepper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+func writeFile(t *testing.T, name, data string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "2023-01"), "pepper-2023\n")
+	writeFile(t, filepath.Join(dir, "2024-01"), "pepper-2024\n")
+	writeFile(t, filepath.Join(dir, ".hidden"), "ignored")
+	if err := os.Mkdir(filepath.Join(dir, "..data"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		dir       string
+		currentID string
+		wantID    string
+		wantErr   error
+	}{
+		{name: "last pepper", dir: dir, wantID: "2024-01"},
+		{name: "given pepper", dir: dir, currentID: "2023-01", wantID: "2023-01"},
+		{name: "missing pepper", dir: dir, currentID: "2025-01", wantErr: argon2.ErrUnknownKeyID},
+		{name: "empty directory", dir: t.TempDir(), wantErr: ErrNoPeppers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Open(tt.dir, tt.currentID)
+			if err != tt.wantErr {
+				t.Fatalf("Open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			keyID, secret := p.Current()
+			if keyID != tt.wantID || string(secret) != "pepper-"+tt.wantID[:4] {
+				t.Errorf("Current() got = %s, %q, want %s", keyID, secret, tt.wantID)
+			}
+			if _, err := p.Get(".hidden"); err != argon2.ErrUnknownKeyID {
+				t.Errorf("Get() error = %v, want %v", err, argon2.ErrUnknownKeyID)
+			}
+		})
+	}
+
+	if _, err := Open(filepath.Join(dir, "missing"), ""); err == nil {
+		t.Errorf("Open() error = nil for a missing directory")
+	}
+}
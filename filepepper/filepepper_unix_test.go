@@ -0,0 +1,54 @@
+//go:build unix
+
+package filepepper
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "2023-01"), "pepper-2023")
+
+	p, err := Watch(dir, "", time.Hour, nil)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer p.Close()
+
+	h, err := argon2.NewHasher(argon2.WithMemory(8*1024), argon2.WithIterations(1), argon2.WithSecretProvider(p))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	hash, err := h.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	// a new pepper is picked up on SIGHUP
+	writeFile(t, filepath.Join(dir, "2024-01"), "pepper-2024")
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for keyID, _ := p.Current(); keyID != "2024-01"; keyID, _ = p.Current() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Current() got = %s, want 2024-01", keyID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := h.Verify(hash, []byte("password")); err != nil {
+		t.Errorf("Verify() of the old hash error = %v", err)
+	}
+	if rehash, err := h.NeedsRehash(hash); err != nil || !rehash {
+		t.Errorf("NeedsRehash() got = %v, error = %v, want true, nil", rehash, err)
+	}
+}
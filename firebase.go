@@ -0,0 +1,80 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// FirebaseScryptVerifier verifies the password hashes of accounts exported
+// from Firebase Authentication, derived with its modified scrypt: the signer
+// key is encrypted with AES-256-CTR, keyed by the scrypt derivation of the
+// password and the salt followed by the salt separator. The parameters are
+// the ones shown in the password hash parameters of the Firebase project.
+type FirebaseScryptVerifier struct {
+	SignerKey     []byte // The decoded base64_signer_key
+	SaltSeparator []byte // The decoded base64_salt_separator
+	Rounds        int    // The rounds parameter, used as the scrypt r parameter
+	MemCost       int    // The mem_cost parameter, the base 2 logarithm of the scrypt N parameter
+	Prefix        string // Stripped from the hash by Verify before decoding it, e.g. "{FIREBASE}"
+}
+
+// Compare compares the decoded passwordHash and salt of an exported account
+// with the password. It returns nil on success, and
+// ErrMismatchedHashAndPassword if they don't match.
+func (v *FirebaseScryptVerifier) Compare(passwordHash, salt, password []byte) error {
+	if len(v.SignerKey) == 0 || v.Rounds < 1 || v.MemCost < 1 || v.MemCost > 30 {
+		return ErrInvalidParams
+	}
+
+	saltSeparated := make([]byte, 0, len(salt)+len(v.SaltSeparator))
+	saltSeparated = append(append(saltSeparated, salt...), v.SaltSeparator...)
+
+	key, err := scrypt.Key(password, saltSeparated, 1<<uint(v.MemCost), v.Rounds, 1, 32)
+	if err != nil {
+		return ErrInvalidParams
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	otherHash := make([]byte, len(v.SignerKey))
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(otherHash, v.SignerKey)
+
+	if subtle.ConstantTimeCompare(passwordHash, otherHash) == 1 {
+		return nil
+	}
+
+	return ErrMismatchedHashAndPassword
+}
+
+// Verify compares a hash stored as "salt$passwordHash", both base64 encoded
+// as in the Firebase export, with the password. It implements Verifier,
+// so it can be passed to VerifyAndMigrateWith or registered with
+// RegisterVerifier.
+func (v *FirebaseScryptVerifier) Verify(hash, password []byte) error {
+	if !bytes.HasPrefix(hash, []byte(v.Prefix)) {
+		return ErrInvalidHash
+	}
+	hash = hash[len(v.Prefix):]
+
+	i := bytes.IndexByte(hash, '$')
+	if i < 0 {
+		return ErrInvalidHash
+	}
+	salt, err := base64.StdEncoding.DecodeString(string(hash[:i]))
+	if err != nil {
+		return ErrInvalidHash
+	}
+	passwordHash, err := base64.StdEncoding.DecodeString(string(hash[i+1:]))
+	if err != nil || len(passwordHash) == 0 {
+		return ErrInvalidHash
+	}
+
+	return v.Compare(passwordHash, salt, password)
+}
@@ -0,0 +1,110 @@
+package argon2
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// newFirebaseTestVerifier returns a verifier with the parameters of the
+// test vector of github.com/firebase/scrypt.
+func newFirebaseTestVerifier(t *testing.T) *FirebaseScryptVerifier {
+	signerKey, err := base64.StdEncoding.DecodeString("jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saltSeparator, err := base64.StdEncoding.DecodeString("Bw==")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &FirebaseScryptVerifier{SignerKey: signerKey, SaltSeparator: saltSeparator, Rounds: 8, MemCost: 14}
+}
+
+func TestFirebaseScryptVerifier_Compare(t *testing.T) {
+	v := newFirebaseTestVerifier(t)
+	passwordHash, _ := base64.StdEncoding.DecodeString("lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ==")
+	salt, _ := base64.StdEncoding.DecodeString("42xEC+ixf3L2lw==")
+
+	tests := []struct {
+		name     string
+		password []byte
+		memCost  int
+		wantErr  error
+	}{
+		{
+			name:     "valid password",
+			password: []byte("user1password"),
+			memCost:  14,
+		},
+		{
+			name:     "mismatched password",
+			password: []byte("user2password"),
+			memCost:  14,
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "invalid mem cost",
+			password: []byte("user1password"),
+			memCost:  0,
+			wantErr:  ErrInvalidParams,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v.MemCost = tt.memCost
+			if err := v.Compare(passwordHash, salt, tt.password); err != tt.wantErr {
+				t.Errorf("Compare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFirebaseScryptVerifier_Verify(t *testing.T) {
+	v := newFirebaseTestVerifier(t)
+	v.Prefix = "{FIREBASE}"
+
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			name:     "valid hash",
+			hash:     []byte("{FIREBASE}42xEC+ixf3L2lw==$lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ=="),
+			password: []byte("user1password"),
+		},
+		{
+			name:     "missing prefix",
+			hash:     []byte("42xEC+ixf3L2lw==$lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ=="),
+			password: []byte("user1password"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "missing salt",
+			hash:     []byte("{FIREBASE}lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ=="),
+			password: []byte("user1password"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.Verify(tt.hash, tt.password); err != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// migrate through the registry
+	RegisterVerifier(v.Prefix, v)
+	defer UnregisterVerifier(v.Prefix)
+
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	newHash, err := VerifyAndMigrate(tests[0].hash, tests[0].password, p)
+	if err != nil {
+		t.Fatalf("VerifyAndMigrate() error = %v", err)
+	}
+	if err := CompareHashAndPassword(newHash, tests[0].password); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+}
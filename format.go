@@ -0,0 +1,634 @@
+package argon2
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math"
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// legacyVersion is the argon2 version 0x10 which is still supported
+// for verifying existing hashes.
+const legacyVersion = 0x10
+
+// Format describes how the parameters, the salt and the derived key
+// are encoded into a single hash string.
+type Format int
+
+const (
+	// FormatLegacy is the package's own encoding, e.g.
+	// "argon2id$19$65536$3$2$salt$key".
+	FormatLegacy Format = iota
+
+	// FormatPHC is the PHC string format used by the reference argon2
+	// implementation, libsodium, PHP and many others, e.g.
+	// "$argon2id$v=19$m=65536,t=3,p=2$salt$key".
+	FormatPHC
+
+	// FormatDjango is the PHC string format prefixed with "argon2", as
+	// stored by Django's Argon2PasswordHasher, e.g.
+	// "argon2$argon2id$v=19$m=65536,t=3,p=2$salt$key".
+	FormatDjango
+)
+
+// djangoPrefix is the algorithm prefix of Django's password hashes.
+const djangoPrefix = "argon2"
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatLegacy:
+		return "legacy"
+	case FormatPHC:
+		return "phc"
+	case FormatDjango:
+		return "django"
+	default:
+		return "Format(" + strconv.Itoa(int(f)) + ")"
+	}
+}
+
+// parseFormat returns the format of the given name, as returned by String.
+// It returns ErrUnsupportedFormat if the name is unknown.
+func parseFormat(name string) (Format, error) {
+	for _, f := range []Format{FormatLegacy, FormatPHC, FormatDjango} {
+		if f.String() == name {
+			return f, nil
+		}
+	}
+	return 0, ErrUnsupportedFormat
+}
+
+// ParseMode controls how strictly encoded hashes are parsed.
+type ParseMode int
+
+const (
+	// ParseStrict accepts only hashes exactly in one of the supported formats.
+	ParseStrict ParseMode = iota
+
+	// ParseLenient additionally trims surrounding whitespace and newlines,
+	// removes encoder ids and password schemes, e.g. "{argon2}", accepts a
+	// leading "$" in front of the legacy format and tolerates padded Base64
+	// salt and key. It is meant for ingesting hashes from exports, e.g. CSV
+	// files.
+	ParseLenient
+)
+
+// valid reports whether the format is supported.
+func (f Format) valid() bool {
+	return f == FormatLegacy || f == FormatPHC || f == FormatDjango
+}
+
+// ConvertFormat re-encodes an existing hash into the target format.
+// The parameters, salt and derived key are kept as is, so the password
+// is not needed and the result verifies the same way as the original.
+// Hashes with a key ID, associated data, a pre-hash function or a
+// normalization, which only this package understands, are rejected with
+// ErrUnsupportedParams.
+func ConvertFormat(encodedHash []byte, target Format) ([]byte, error) {
+	if !target.valid() {
+		return nil, ErrUnsupportedFormat
+	}
+
+	p, salt, key, err := decodeHash(encodedHash)
+	if err != nil {
+		return nil, err
+	}
+	if p.extended() {
+		return nil, ErrUnsupportedParams
+	}
+	p.Format = target
+
+	return appendEncoded(nil, p.Format, p, salt, key), nil
+}
+
+// DecodeHash extracts the parameters, salt and derived key from the provided
+// hash, in any of the supported formats, without verifying it. The detected
+// format is reported in the Format field of the returned parameters. It lets
+// applications and tools inspect, migrate and re-encode stored hashes.
+func DecodeHash(encodedHash []byte) (p *Params, salt, key []byte, err error) {
+	return decodeHash(encodedHash)
+}
+
+// ExtractParams returns the parameters of the provided hash, without
+// verifying it, e.g. for audit tooling reading the settings of stored
+// credentials.
+func ExtractParams(encodedHash []byte) (*Params, error) {
+	p, _, _, err := decodeHash(encodedHash)
+	return p, err
+}
+
+// Cost returns the cost of the provided hash, like bcrypt.Cost: the memory
+// (kibibytes) times the number of iterations, the amount of memory an
+// attacker has to fill for every guess.
+func Cost(encodedHash []byte) (uint64, error) {
+	p, err := ExtractParams(encodedHash)
+	if err != nil {
+		return 0, err
+	}
+	return p.cost(), nil
+}
+
+// EncodeHash encodes the parameters, salt and derived key in the format
+// chosen by p.Format, the reverse of DecodeHash. The salt and key lengths
+// of p are ignored in favor of the actual ones.
+func EncodeHash(p *Params, salt, key []byte) []byte {
+	return appendEncoded(nil, p.Format, p, salt, key)
+}
+
+// appendEncoded appends the params, the salt and the derived key to dst,
+// encoded in the given format. Salt and key are encoded to Base64.
+func appendEncoded(dst []byte, f Format, p *Params, salt, key []byte) []byte {
+	version := p.Version
+	if version == 0 {
+		version = argon2.Version
+	}
+
+	if f == FormatDjango {
+		dst = append(dst, djangoPrefix...)
+	}
+
+	if f == FormatPHC || f == FormatDjango {
+		dst = append(dst, '$')
+		dst = append(dst, p.Variant.String()...)
+		dst = append(dst, "$v="...)
+		dst = strconv.AppendUint(dst, uint64(version), 10)
+		dst = append(dst, "$m="...)
+		dst = strconv.AppendUint(dst, uint64(p.Memory), 10)
+		dst = append(dst, ",t="...)
+		dst = strconv.AppendUint(dst, uint64(p.Iterations), 10)
+		dst = append(dst, ",p="...)
+		dst = strconv.AppendUint(dst, uint64(p.Parallelism), 10)
+		if p.KeyID != "" {
+			dst = append(dst, ",keyid="...)
+			dst = appendBase64(dst, base64.RawStdEncoding, []byte(p.KeyID))
+		}
+		if p.Data != "" {
+			dst = append(dst, ",data="...)
+			dst = appendBase64(dst, base64.RawStdEncoding, []byte(p.Data))
+		}
+		if p.PreHash != NoPreHash {
+			dst = append(dst, ",ph="...)
+			dst = append(dst, p.PreHash.String()...)
+		}
+		if p.Normalization != NoNormalization {
+			dst = append(dst, ",norm="...)
+			dst = append(dst, p.Normalization.String()...)
+		}
+	} else {
+		dst = append(dst, p.Variant.String()...)
+		dst = append(dst, '$')
+		dst = strconv.AppendUint(dst, uint64(version), 10)
+		dst = append(dst, '$')
+		dst = strconv.AppendUint(dst, uint64(p.Memory), 10)
+		dst = append(dst, '$')
+		dst = strconv.AppendUint(dst, uint64(p.Iterations), 10)
+		dst = append(dst, '$')
+		dst = strconv.AppendUint(dst, uint64(p.Parallelism), 10)
+	}
+	enc := base64Encoding(p.URLSafe)
+	dst = append(dst, '$')
+	dst = appendBase64(dst, enc, salt)
+	dst = append(dst, '$')
+	return appendBase64(dst, enc, key)
+}
+
+// EncodedLen returns the exact length of the hashes encoded with the given
+// parameters, or the Defaults if p is nil, e.g. to size fixed buffers.
+func EncodedLen(p *Params) int {
+	p = orDefault(p)
+
+	// the separators of the legacy format: variant$v$m$t$p$salt$key
+	n := len(p.Variant.String()) + 6 +
+		digits(uint64(p.version())) +
+		digits(uint64(p.Memory)) +
+		digits(uint64(p.Iterations)) +
+		digits(uint64(p.Parallelism))
+	if p.Format == FormatPHC || p.Format == FormatDjango {
+		// $variant$v=v$m=m,t=t,p=p$salt$key
+		n += 1 + len("v=m=t=p=")
+	}
+	if p.Format == FormatDjango {
+		n += len(djangoPrefix)
+	}
+	if p.Format == FormatPHC || p.Format == FormatDjango {
+		if p.KeyID != "" {
+			n += len(",keyid=") + base64.RawStdEncoding.EncodedLen(len(p.KeyID))
+		}
+		if p.Data != "" {
+			n += len(",data=") + base64.RawStdEncoding.EncodedLen(len(p.Data))
+		}
+		if p.PreHash != NoPreHash {
+			n += len(",ph=") + len(p.PreHash.String())
+		}
+		if p.Normalization != NoNormalization {
+			n += len(",norm=") + len(p.Normalization.String())
+		}
+	}
+
+	enc := base64Encoding(p.URLSafe)
+	return n + enc.EncodedLen(int(p.SaltLength)) + enc.EncodedLen(int(p.KeyLength))
+}
+
+// MaxEncodedLen returns the maximum length of the hashes encoded with the
+// salt and key lengths of the given parameters, or the Defaults if p is nil,
+// whatever their costs, variant and format, as long as Check accepts them.
+// It is meant to size database columns, leaving room for raising the costs.
+func MaxEncodedLen(p *Params) int {
+	p = orDefault(p)
+	return EncodedLen(&Params{
+		Memory:        maxMemoryValue,
+		Iterations:    math.MaxUint32,
+		Parallelism:   math.MaxUint8,
+		SaltLength:    p.SaltLength,
+		KeyLength:     p.KeyLength,
+		Format:        FormatDjango,
+		Variant:       Argon2id,
+		KeyID:         p.KeyID,
+		Data:          p.Data,
+		PreHash:       p.PreHash,
+		Normalization: p.Normalization,
+	})
+}
+
+// digits returns the number of decimal digits of v.
+func digits(v uint64) int {
+	n := 1
+	for ; v >= 10; v /= 10 {
+		n++
+	}
+	return n
+}
+
+// base64Encoding returns the unpadded standard or URL-safe Base64 encoding.
+func base64Encoding(urlSafe bool) *base64.Encoding {
+	if urlSafe {
+		return base64.RawURLEncoding
+	}
+	return base64.RawStdEncoding
+}
+
+// appendBase64 appends the Base64 encoding of src to dst.
+func appendBase64(dst []byte, enc *base64.Encoding, src []byte) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, enc.EncodedLen(len(src)))...)
+	enc.Encode(dst[n:], src)
+	return dst
+}
+
+// decodeHash extracts the parameters, salt and derived key from the
+// provided hash, as stored, e.g. with the encoder id of Spring Security's
+// DelegatingPasswordEncoder. It returns an error if the hash format is
+// invalid and/or the parameters are invalid.
+func decodeHash(encodedHash []byte) (p *Params, salt, hash []byte, err error) {
+	return decodeHashInto(nil, trimEncoderID(encodedHash), ParseStrict)
+}
+
+// decodeHashInto is like decodeHash, but decodes the salt and derived key
+// into buf, growing it if needed, and parses the hash in the given mode.
+// The returned salt starts at the beginning of the used buffer and the
+// derived key follows it.
+// Both the legacy and the PHC formats are accepted, the detected one is
+// reported in the Format field of the returned params.
+func decodeHashInto(buf, encodedHash []byte, mode ParseMode) (p *Params, salt, hash []byte, err error) {
+	if mode == ParseLenient {
+		encodedHash = trimEncoderID(bytes.TrimSpace(encodedHash))
+	}
+
+	var fields [8][]byte
+	vals := fields[:]
+	n := splitHash(encodedHash, vals)
+
+	// A leading "$" in front of the legacy format is tolerated in lenient mode
+	if mode == ParseLenient && n == 8 && len(vals[0]) == 0 {
+		vals, n = vals[1:], n-1
+	}
+
+	// The algorithm identifier is the first field of the legacy format
+	// and the second one of the PHC format, right after the leading "$",
+	// or of the Django format, right after the "argon2$" prefix.
+	var id []byte
+	switch {
+	case n == 7:
+		id = vals[0]
+		p, err = decodeLegacyParams(vals[1:5])
+	case (n == 6 || n == 5) && len(vals[0]) == 0:
+		id = vals[1]
+		p, err = decodePHCParams(vals[2 : n-2])
+		if err == nil {
+			p.Format = FormatPHC
+		}
+	case (n == 6 || n == 5) && string(vals[0]) == djangoPrefix:
+		id = vals[1]
+		p, err = decodePHCParams(vals[2 : n-2])
+		if err == nil {
+			p.Format = FormatDjango
+		}
+	default:
+		return nil, nil, nil, &HashError{Err: ErrInvalidHash}
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	p.Variant, err = parseVariant(id)
+	if err != nil {
+		return nil, nil, nil, &HashError{Field: "algorithm", Err: err}
+	}
+
+	b64Salt, b64Key := vals[n-2], vals[n-1]
+	if mode == ParseLenient {
+		b64Salt, b64Key = bytes.TrimRight(b64Salt, "="), bytes.TrimRight(b64Key, "=")
+	}
+
+	salt, hash, p.URLSafe, err = decodeSaltAndKey(buf, b64Salt, b64Key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(hash))
+
+	// Parameters outside of the spec would have the key derivation panic
+	if err := joinErrors(p.checkSpec()); err != nil {
+		return nil, nil, nil, &HashError{Field: "params", Err: ErrInvalidHash, Cause: err}
+	}
+
+	return p, salt, hash, nil
+}
+
+// trimEncoderID removes the encoder id Spring Security's DelegatingPasswordEncoder
+// prefixes hashes with, e.g. "{argon2}" or "{argon2@SpringSecurity_v5_8}",
+// as well as Dovecot and LDAP password schemes, e.g. "{ARGON2ID}".
+func trimEncoderID(encodedHash []byte) []byte {
+	const id = "{argon2"
+	if len(encodedHash) < len(id) || !bytes.EqualFold(encodedHash[:len(id)], []byte(id)) {
+		return encodedHash
+	}
+	i := bytes.IndexByte(encodedHash, '}')
+	if i < 0 {
+		return encodedHash
+	}
+	return encodedHash[i+1:]
+}
+
+// splitScheme splits a "{SCHEME}hash" password value, as stored by Dovecot
+// and LDAP directories, into its scheme and encoded hash.
+func splitScheme(password []byte) (scheme, encodedHash []byte, err error) {
+	if len(password) == 0 || password[0] != '{' {
+		return nil, nil, ErrInvalidHash
+	}
+	i := bytes.IndexByte(password, '}')
+	if i < 0 {
+		return nil, nil, ErrInvalidHash
+	}
+	return password[1:i], password[i+1:], nil
+}
+
+// splitHash splits the encoded hash into vals by the "$" separator and
+// returns the number of fields found, or -1 if there are more fields
+// than vals can hold.
+func splitHash(encodedHash []byte, vals [][]byte) int {
+	for n, rest := 0, encodedHash; n < len(vals); n++ {
+		i := bytes.IndexByte(rest, '$')
+		if i < 0 {
+			vals[n] = rest
+			return n + 1
+		}
+		vals[n], rest = rest[:i], rest[i+1:]
+	}
+	return -1
+}
+
+// decodeLegacyParams parses the version, memory, iterations and parallelism
+// fields of a hash in the legacy format.
+func decodeLegacyParams(vals [][]byte) (*Params, error) {
+	// Check argon2 version
+	version, err := parsePHCParam("version", vals[0], 32)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsing parameters
+	p := &Params{}
+
+	if p.Version, err = checkVersion(version); err != nil {
+		return nil, &HashError{Field: "version", Err: err}
+	}
+
+	memory, err := parsePHCParam("memory", vals[1], 32)
+	if err != nil {
+		return nil, err
+	}
+	p.Memory = uint32(memory)
+
+	iterations, err := parsePHCParam("iterations", vals[2], 32)
+	if err != nil {
+		return nil, err
+	}
+	p.Iterations = uint32(iterations)
+
+	parallelism, err := parsePHCParam("parallelism", vals[3], 8)
+	if err != nil {
+		return nil, err
+	}
+	p.Parallelism = uint8(parallelism)
+
+	return p, nil
+}
+
+// decodePHCParams parses the optional "v=" version and the "m=,t=,p="
+// parameters fields of a hash in the PHC format. A missing version means
+// version 0x10, as in the reference implementation.
+func decodePHCParams(vals [][]byte) (*Params, error) {
+	p := &Params{}
+
+	// Check argon2 version
+	version := uint64(legacyVersion)
+	if bytes.HasPrefix(vals[0], []byte("v=")) {
+		v, err := strconv.ParseUint(string(vals[0][2:]), 10, 32)
+		if err != nil {
+			return nil, &HashError{Field: "version", Err: ErrInvalidHash, Cause: err}
+		}
+		version, vals = v, vals[1:]
+	}
+
+	var err error
+	if p.Version, err = checkVersion(version); err != nil {
+		return nil, &HashError{Field: "version", Err: err}
+	}
+
+	if len(vals) != 1 {
+		return nil, &HashError{Field: "params", Err: ErrInvalidHash}
+	}
+
+	// Parsing parameters. The reference implementation emits them in the
+	// "m,t,p" order, but other implementations, e.g. Python's passlib,
+	// don't always, and may append the optional "keyid" and "data" ones.
+	var memory, iterations, parallelism, keyID, data, preHash, normalization []byte
+	for field := vals[0]; field != nil; {
+		var param []byte
+		if i := bytes.IndexByte(field, ','); i >= 0 {
+			param, field = field[:i], field[i+1:]
+		} else {
+			param, field = field, nil
+		}
+
+		i := bytes.IndexByte(param, '=')
+		if i < 0 {
+			return nil, &HashError{Field: "params", Err: ErrInvalidHash}
+		}
+		name, value := string(param[:i]), param[i+1:]
+
+		switch name {
+		case "m":
+			if memory != nil {
+				return nil, &HashError{Field: "memory", Err: ErrInvalidHash}
+			}
+			memory = value
+		case "t":
+			if iterations != nil {
+				return nil, &HashError{Field: "iterations", Err: ErrInvalidHash}
+			}
+			iterations = value
+		case "p":
+			if parallelism != nil {
+				return nil, &HashError{Field: "parallelism", Err: ErrInvalidHash}
+			}
+			parallelism = value
+		case "keyid":
+			if keyID != nil {
+				return nil, &HashError{Field: name, Err: ErrInvalidHash}
+			}
+			keyID = value
+		case "data":
+			if data != nil {
+				return nil, &HashError{Field: name, Err: ErrInvalidHash}
+			}
+			data = value
+		case "ph":
+			if preHash != nil {
+				return nil, &HashError{Field: name, Err: ErrInvalidHash}
+			}
+			preHash = value
+		case "norm":
+			if normalization != nil {
+				return nil, &HashError{Field: name, Err: ErrInvalidHash}
+			}
+			normalization = value
+		default:
+			return nil, &HashError{Field: "params", Err: ErrInvalidHash}
+		}
+	}
+
+	m, err := parsePHCParam("memory", memory, 32)
+	if err != nil {
+		return nil, err
+	}
+	p.Memory = uint32(m)
+
+	t, err := parsePHCParam("iterations", iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	p.Iterations = uint32(t)
+
+	l, err := parsePHCParam("parallelism", parallelism, 8)
+	if err != nil {
+		return nil, err
+	}
+	p.Parallelism = uint8(l)
+
+	if keyID != nil {
+		id, err := base64.RawStdEncoding.DecodeString(string(keyID))
+		if err != nil || len(id) == 0 {
+			return nil, &HashError{Field: "keyid", Err: ErrInvalidHash, Cause: err}
+		}
+		p.KeyID = string(id)
+	}
+	if data != nil {
+		ad, err := base64.RawStdEncoding.DecodeString(string(data))
+		if err != nil || len(ad) == 0 {
+			return nil, &HashError{Field: "data", Err: ErrInvalidHash, Cause: err}
+		}
+		p.Data = string(ad)
+	}
+	if preHash != nil {
+		if p.PreHash, err = parsePreHash(preHash); err != nil {
+			return nil, &HashError{Field: "ph", Err: err}
+		}
+	}
+	if normalization != nil {
+		if p.Normalization, err = parseNormalization(normalization); err != nil {
+			return nil, &HashError{Field: "norm", Err: err}
+		}
+	}
+
+	return p, nil
+}
+
+// checkVersion checks that the decoded argon2 version is supported and
+// returns it as stored in Params, where the current version is zero.
+func checkVersion(version uint64) (uint32, error) {
+	switch version {
+	case argon2.Version:
+		return 0, nil
+	case legacyVersion:
+		return legacyVersion, nil
+	default:
+		return 0, ErrIncompatibleVersion
+	}
+}
+
+// parsePHCParam parses the value of the named PHC parameter into an
+// unsigned integer of the given bit size. A missing (nil) value is invalid.
+func parsePHCParam(name string, value []byte, bitSize int) (uint64, error) {
+	if value == nil {
+		return 0, &HashError{Field: name, Err: ErrInvalidHash}
+	}
+	v, err := strconv.ParseUint(string(value), 10, bitSize)
+	if err != nil {
+		return 0, &HashError{Field: name, Err: ErrInvalidHash, Cause: err}
+	}
+	return v, nil
+}
+
+// decodeSaltAndKey decodes the Base64 salt and derived key one after
+// another into buf, growing it if needed. The alphabet, standard or
+// URL-safe, is detected from the encoded values and reported as urlSafe.
+func decodeSaltAndKey(buf, b64Salt, b64Key []byte) (salt, key []byte, urlSafe bool, err error) {
+	// The Base64 decoder skips newlines, they are not part of a valid hash
+	if bytes.ContainsAny(b64Salt, "\r\n") {
+		return nil, nil, false, &HashError{Field: "salt", Err: ErrInvalidHash}
+	}
+	if bytes.ContainsAny(b64Key, "\r\n") {
+		return nil, nil, false, &HashError{Field: "key", Err: ErrInvalidHash}
+	}
+
+	std := bytes.ContainsAny(b64Salt, "+/") || bytes.ContainsAny(b64Key, "+/")
+	urlSafe = bytes.ContainsAny(b64Salt, "-_") || bytes.ContainsAny(b64Key, "-_")
+	if std && urlSafe {
+		return nil, nil, false, &HashError{Field: "key", Err: ErrInvalidHash}
+	}
+	enc := base64Encoding(urlSafe)
+
+	saltLen := enc.DecodedLen(len(b64Salt))
+	keyLen := enc.DecodedLen(len(b64Key))
+	if cap(buf) < saltLen+keyLen {
+		buf = make([]byte, saltLen+keyLen)
+	}
+	buf = buf[:saltLen+keyLen]
+
+	sn, err := enc.Decode(buf, b64Salt)
+	if err != nil {
+		return nil, nil, false, &HashError{Field: "salt", Err: ErrInvalidHash, Cause: err}
+	}
+
+	kn, err := enc.Decode(buf[sn:], b64Key)
+	if err != nil {
+		return nil, nil, false, &HashError{Field: "key", Err: ErrInvalidHash, Cause: err}
+	}
+
+	return buf[:sn], buf[sn : sn+kn], urlSafe, nil
+}
@@ -0,0 +1,404 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvertFormat(t *testing.T) {
+	type args struct {
+		encodedHash []byte
+		target      Format
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "legacy to phc",
+			args: args{
+				encodedHash: []byte("argon2id$19$65536$2$1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				target:      FormatPHC,
+			},
+			want: "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		},
+		{
+			name: "phc to legacy",
+			args: args{
+				encodedHash: []byte("$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				target:      FormatLegacy,
+			},
+			want: "argon2id$19$65536$2$1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		},
+		{
+			name: "legacy to legacy",
+			args: args{
+				encodedHash: []byte("argon2id$19$65536$2$1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				target:      FormatLegacy,
+			},
+			want: "argon2id$19$65536$2$1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		},
+		{
+			name: "version 16 legacy to phc",
+			args: args{
+				encodedHash: []byte("argon2id$16$65536$2$1$c29tZXNhbHQ$mA69JKTmZ/FjRvnUp4sXVyh4NhPgzG+xfC7IhLFkNd8"),
+				target:      FormatPHC,
+			},
+			want: "$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$mA69JKTmZ/FjRvnUp4sXVyh4NhPgzG+xfC7IhLFkNd8",
+		},
+		{
+			name: "url-safe legacy to phc",
+			args: args{
+				encodedHash: []byte("argon2id$19$65536$3$2$6pAg-fVI2vB9uenAuOTK0A$VPg50e-vxRnvQ8dIFSg1HFNYHYcxEW-Dx47O6vipImU"),
+				target:      FormatPHC,
+			},
+			want: "$argon2id$v=19$m=65536,t=3,p=2$6pAg-fVI2vB9uenAuOTK0A$VPg50e-vxRnvQ8dIFSg1HFNYHYcxEW-Dx47O6vipImU",
+		},
+		{
+			name: "legacy to django",
+			args: args{
+				encodedHash: []byte("argon2id$19$65536$2$1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				target:      FormatDjango,
+			},
+			want: "argon2$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		},
+		{
+			name: "django to phc",
+			args: args{
+				encodedHash: []byte("argon2$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				target:      FormatPHC,
+			},
+			want: "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		},
+		{
+			name: "pre-hash",
+			args: args{
+				encodedHash: []byte("$argon2id$v=19$m=65536,t=2,p=1,ph=sha512$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				target:      FormatDjango,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported format",
+			args: args{
+				encodedHash: []byte("argon2id$19$65536$2$1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+				target:      Format(42),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid hash",
+			args: args{
+				encodedHash: []byte("dwiehduwehc8wh"),
+				target:      FormatPHC,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertFormat(tt.args.encodedHash, tt.args.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("ConvertFormat() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPythonCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			// argon2-cffi PasswordHasher defaults since 21.2.0
+			name:     "argon2-cffi defaults",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$c29kaXVtIGNobG9yaWRlIQ$TmZ7lGATwrISgelS83VCQaRltHf2I9OSO5Zu/17BZTQ"),
+			password: []byte("s3kr3tp4ssw0rd"),
+		},
+		{
+			// argon2-cffi PasswordHasher defaults before 21.2.0
+			name:     "argon2-cffi legacy defaults",
+			hash:     []byte("$argon2i$v=19$m=102400,t=2,p=8$c29kaXVtIGNobG9yaWRlIQ$osFZYUQb/hLpfHzsMFyZ6Q"),
+			password: []byte("s3kr3tp4ssw0rd"),
+		},
+		{
+			// passlib argon2 hash of version 0x10, without the "v=" field
+			name:     "passlib version 16",
+			hash:     []byte("$argon2i$m=512,t=2,p=2$c29tZXNhbHQ$hSa1mIWN7NgKD7QmOkMriQ"),
+			password: []byte("password"),
+		},
+		{
+			name:     "parameters in a different order",
+			hash:     []byte("$argon2id$v=19$t=3,p=4,m=65536$c29kaXVtIGNobG9yaWRlIQ$TmZ7lGATwrISgelS83VCQaRltHf2I9OSO5Zu/17BZTQ"),
+			password: []byte("s3kr3tp4ssw0rd"),
+		},
+		{
+			name:     "associated data",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4,data=dXNlcjE$c29kaXVtIGNobG9yaWRlIQ$TmZ7lGATwrISgelS83VCQaRltHf2I9OSO5Zu/17BZTQ"),
+			password: []byte("s3kr3tp4ssw0rd"),
+			wantErr:  ErrUnsupportedParams,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPassword(tt.hash, tt.password); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CompareHashAndPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDjangoCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  bool
+	}{
+		{
+			// Argon2PasswordHasher with the default parameters
+			name:     "argon2id defaults",
+			hash:     []byte("argon2$argon2id$v=19$m=102400,t=2,p=8$cFJRRmJ2UWEzYlV3OXM3UnEyVjZZbw$qI5pNOzIbgov3aYHfay/UXDimAJeK2Flosi3sVSeUDg"),
+			password: []byte("django-secret"),
+		},
+		{
+			// Argon2PasswordHasher of Django 1.10
+			name:     "argon2i django 1.10",
+			hash:     []byte("argon2$argon2i$v=19$m=512,t=2,p=2$cFJRRmJ2UWEzYlV3OXM3UnEyVjZZbw$pqpz3K+TRuU/lfRZsbcXng"),
+			password: []byte("django-secret"),
+		},
+		{
+			name:     "wrong password",
+			hash:     []byte("argon2$argon2id$v=19$m=102400,t=2,p=8$cFJRRmJ2UWEzYlV3OXM3UnEyVjZZbw$qI5pNOzIbgov3aYHfay/UXDimAJeK2Flosi3sVSeUDg"),
+			password: []byte("django-secret1"),
+			wantErr:  true,
+		},
+		{
+			name:     "wrong prefix",
+			hash:     []byte("argon3$argon2id$v=19$m=102400,t=2,p=8$cFJRRmJ2UWEzYlV3OXM3UnEyVjZZbw$qI5pNOzIbgov3aYHfay/UXDimAJeK2Flosi3sVSeUDg"),
+			password: []byte("django-secret"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPassword(tt.hash, tt.password); (err != nil) != tt.wantErr {
+				t.Errorf("CompareHashAndPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			got, err := ConvertFormat(tt.hash, FormatDjango)
+			if err != nil {
+				t.Fatalf("ConvertFormat() error = %v", err)
+			}
+			if string(got) != string(tt.hash) {
+				t.Errorf("ConvertFormat() got = %s, want %s", got, tt.hash)
+			}
+		})
+	}
+}
+
+func TestSpringSecurityCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  bool
+	}{
+		{
+			// Argon2PasswordEncoder.defaultsForSpringSecurity_v5_8()
+			name:     "spring security 5.8 defaults",
+			hash:     []byte("$argon2id$v=19$m=16384,t=2,p=1$a2V5Y2xvYWstc2FsdC0xNg$kpxriceRF605iPYBTOI1R+FuYvSzwCfqTu3xXOP3zW4"),
+			password: []byte("spring-secret"),
+		},
+		{
+			// Argon2PasswordEncoder.defaultsForSpringSecurity_v5_2()
+			name:     "spring security 5.2 defaults",
+			hash:     []byte("$argon2id$v=19$m=4096,t=3,p=1$a2V5Y2xvYWstc2FsdC0xNg$0XHJX7OscM4nyF7tnG05d4HBVnMuWnw1NJGdKNeipvM"),
+			password: []byte("spring-secret"),
+		},
+		{
+			// DelegatingPasswordEncoder output
+			name:     "delegating password encoder",
+			hash:     []byte("{argon2}$argon2id$v=19$m=16384,t=2,p=1$a2V5Y2xvYWstc2FsdC0xNg$kpxriceRF605iPYBTOI1R+FuYvSzwCfqTu3xXOP3zW4"),
+			password: []byte("spring-secret"),
+		},
+		{
+			name:     "delegating password encoder with versioned id",
+			hash:     []byte("{argon2@SpringSecurity_v5_8}$argon2id$v=19$m=16384,t=2,p=1$a2V5Y2xvYWstc2FsdC0xNg$kpxriceRF605iPYBTOI1R+FuYvSzwCfqTu3xXOP3zW4"),
+			password: []byte("spring-secret"),
+		},
+		{
+			name:     "other encoder id",
+			hash:     []byte("{bcrypt}$argon2id$v=19$m=16384,t=2,p=1$a2V5Y2xvYWstc2FsdC0xNg$kpxriceRF605iPYBTOI1R+FuYvSzwCfqTu3xXOP3zW4"),
+			password: []byte("spring-secret"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPassword(tt.hash, tt.password); (err != nil) != tt.wantErr {
+				t.Errorf("CompareHashAndPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHashError(t *testing.T) {
+	tests := []struct {
+		name      string
+		hash      []byte
+		wantField string
+		wantErr   error
+		wantCause bool
+	}{
+		{
+			name:    "wrong number of fields",
+			hash:    []byte("argon2id$19$65536$3$2"),
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:      "invalid memory",
+			hash:      []byte("$argon2id$v=19$m=64k,t=3,p=2$c29tZXNhbHQ$a2V5a2V5a2V5a2V5"),
+			wantField: "memory",
+			wantErr:   ErrInvalidHash,
+			wantCause: true,
+		},
+		{
+			name:      "invalid salt",
+			hash:      []byte("argon2id$19$65536$3$2$c29tZ!NhbHQ$a2V5a2V5a2V5a2V5"),
+			wantField: "salt",
+			wantErr:   ErrInvalidHash,
+			wantCause: true,
+		},
+		{
+			name:      "incompatible version",
+			hash:      []byte("$argon2id$v=18$m=65536,t=3,p=2$c29tZXNhbHQ$a2V5a2V5a2V5a2V5"),
+			wantField: "version",
+			wantErr:   ErrIncompatibleVersion,
+		},
+		{
+			name:      "unknown algorithm",
+			hash:      []byte("$argon2x$v=19$m=65536,t=3,p=2$c29tZXNhbHQ$a2V5a2V5a2V5a2V5"),
+			wantField: "algorithm",
+			wantErr:   ErrUnknownAlgorithm,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CompareHashAndPassword(tt.hash, []byte("password"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CompareHashAndPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var he *HashError
+			if !errors.As(err, &he) {
+				t.Fatalf("CompareHashAndPassword() error = %v, want a *HashError", err)
+			}
+			if he.Field != tt.wantField {
+				t.Errorf("HashError.Field = %q, want %q", he.Field, tt.wantField)
+			}
+			if (he.Cause != nil) != tt.wantCause {
+				t.Errorf("HashError.Cause = %v, wantCause %v", he.Cause, tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestDecodeHash(t *testing.T) {
+	hashes := []string{
+		"argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		"$argon2id$v=19$m=65536,t=3,p=2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		"argon2$argon2i$v=19$m=8192,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$a2V5a2V5a2V5a2V5a2V5aw",
+	}
+	for _, hash := range hashes {
+		t.Run(hash, func(t *testing.T) {
+			p, salt, key, err := DecodeHash([]byte(hash))
+			if err != nil {
+				t.Fatalf("DecodeHash() error = %v", err)
+			}
+			if int(p.SaltLength) != len(salt) || int(p.KeyLength) != len(key) {
+				t.Errorf("DecodeHash() got lengths %d, %d for salt %x and key %x", p.SaltLength, p.KeyLength, salt, key)
+			}
+			if got := EncodeHash(p, salt, key); string(got) != hash {
+				t.Errorf("EncodeHash() got = %s, want %s", got, hash)
+			}
+		})
+	}
+
+	if _, _, _, err := DecodeHash([]byte("argon2id$19$65536")); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("DecodeHash() error = %v, want %v", err, ErrInvalidHash)
+	}
+}
+
+func TestExtractParams(t *testing.T) {
+	hash := []byte("$argon2id$v=19$m=65536,t=3,p=2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU")
+
+	p, err := ExtractParams(hash)
+	if err != nil {
+		t.Fatalf("ExtractParams() error = %v", err)
+	}
+	want := &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+	if *p != *want {
+		t.Errorf("ExtractParams() got = %+v, want %+v", p, want)
+	}
+
+	cost, err := Cost(hash)
+	if err != nil || cost != 3*64*1024 {
+		t.Errorf("Cost() got = %d, error = %v, want %d", cost, err, 3*64*1024)
+	}
+
+	if _, err := Cost([]byte("$2b$10$salt")); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Cost() error = %v, want %v", err, ErrInvalidHash)
+	}
+}
+
+func TestEncodedLen(t *testing.T) {
+	base := Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	tests := []struct {
+		name   string
+		modify func(p *Params)
+	}{
+		{name: "legacy format", modify: func(p *Params) {}},
+		{name: "phc format", modify: func(p *Params) { p.Format = FormatPHC }},
+		{name: "django format", modify: func(p *Params) { p.Format = FormatDjango }},
+		{name: "argon2i", modify: func(p *Params) { p.Variant = Argon2i; p.Format = FormatPHC }},
+		{name: "larger costs", modify: func(p *Params) { p.Memory = 64 * 1024; p.Iterations = 12; p.Parallelism = 16 }},
+		{name: "odd lengths", modify: func(p *Params) { p.SaltLength = 17; p.KeyLength = 33; p.URLSafe = true }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := base
+			tt.modify(&p)
+
+			hash, err := GenerateFromPassword([]byte("password"), &p)
+			if err != nil {
+				t.Fatalf("GenerateFromPassword() error = %v", err)
+			}
+			if got := EncodedLen(&p); got != len(hash) {
+				t.Errorf("EncodedLen() got = %d, want %d for %s", got, len(hash), hash)
+			}
+			if got := MaxEncodedLen(&p); got < len(hash) {
+				t.Errorf("MaxEncodedLen() got = %d, want at least %d", got, len(hash))
+			}
+		})
+	}
+
+	if got, want := EncodedLen(nil), len("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"); got != want {
+		t.Errorf("EncodedLen(nil) got = %d, want %d", got, want)
+	}
+	if got, want := MaxEncodedLen(nil), len("argon2$argon2id$v=19$m=4194304,t=4294967295,p=255$")+22+1+43; got != want {
+		t.Errorf("MaxEncodedLen(nil) got = %d, want %d", got, want)
+	}
+}
@@ -1,5 +1,7 @@
 module github.com/andskur/argon2-hashing
 
-go 1.14
+go 1.20
 
 require golang.org/x/crypto v0.17.0
+
+require golang.org/x/sys v0.15.0 // indirect
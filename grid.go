@@ -0,0 +1,60 @@
+package argon2
+
+import (
+	"time"
+)
+
+// Grid is a set of memory, iterations and parallelism values to benchmark,
+// every combination of them is timed by Benchmark.
+type Grid struct {
+	Memory      []uint32 // The memory values to benchmark (kibibytes)
+	Iterations  []uint32 // The iterations values to benchmark
+	Parallelism []uint8  // The parallelism values to benchmark
+}
+
+// Timing is the time a hash with the given parameters took on the current host.
+type Timing struct {
+	Memory      uint32        `json:"memory"`
+	Iterations  uint32        `json:"iterations"`
+	Parallelism uint8         `json:"parallelism"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// Benchmark hashes a password with every combination of the grid values and
+// returns the timings, ordered by memory, iterations and parallelism, e.g. to
+// feed capacity planning. The parameters are checked before anything is
+// timed, and ErrInvalidParams is returned if any combination is invalid.
+func Benchmark(g Grid) ([]Timing, error) {
+	defaults := Defaults()
+	params := make([]*Params, 0, len(g.Memory)*len(g.Iterations)*len(g.Parallelism))
+	for _, m := range g.Memory {
+		for _, t := range g.Iterations {
+			for _, p := range g.Parallelism {
+				params = append(params, &Params{
+					Memory:      m,
+					Iterations:  t,
+					Parallelism: p,
+					SaltLength:  defaults.SaltLength,
+					KeyLength:   defaults.KeyLength,
+				})
+			}
+		}
+	}
+	for _, p := range params {
+		if err := p.Check(); err != nil {
+			return nil, err
+		}
+	}
+
+	timings := make([]Timing, len(params))
+	for i, p := range params {
+		timings[i] = Timing{
+			Memory:      p.Memory,
+			Iterations:  p.Iterations,
+			Parallelism: p.Parallelism,
+			Duration:    measure(p),
+		}
+	}
+
+	return timings, nil
+}
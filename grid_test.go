@@ -0,0 +1,49 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestBenchmark(t *testing.T) {
+	tests := []struct {
+		name    string
+		grid    Grid
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "grid",
+			grid: Grid{Memory: []uint32{8 * 1024, 16 * 1024}, Iterations: []uint32{1, 2}, Parallelism: []uint8{1, 2}},
+			want: 8,
+		},
+		{
+			name: "empty grid",
+			grid: Grid{Memory: []uint32{8 * 1024}, Iterations: []uint32{1}},
+			want: 0,
+		},
+		{
+			name:    "invalid memory",
+			grid:    Grid{Memory: []uint32{8 * 1024, 1024}, Iterations: []uint32{1}, Parallelism: []uint8{1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Benchmark(tt.grid)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Benchmark() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("Benchmark() got %d timings, want %d", len(got), tt.want)
+			}
+			for i, timing := range got {
+				if timing.Duration <= 0 {
+					t.Errorf("Benchmark() timing %d got duration %v, want a positive one", i, timing.Duration)
+				}
+			}
+			if tt.want == 8 && (got[0].Memory != 8*1024 || got[0].Parallelism != 1 || got[7].Memory != 16*1024 || got[7].Iterations != 2) {
+				t.Errorf("Benchmark() got = %v, want ordered by memory, iterations and parallelism", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package argon2
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+)
+
+// Versions of the binary encoding of Hash. The second one appends the key
+// ID, associated data, pre-hash function and normalization, it is only
+// used if one of them is set so the encoding of other hashes is unchanged.
+const (
+	binaryVersion         = 1
+	binaryVersionExtended = 2
+)
+
+// Flags of the binary encoding of Hash, recording how the hash is encoded
+// as text so a binary round trip keeps its textual representation.
+const (
+	flagPHC     = 1 << iota // the hash is encoded in FormatPHC
+	flagURLSafe             // the salt and key use URL-safe Base64
+	flagDjango              // the hash is encoded in FormatDjango
+)
+
+// Hash is a decoded argon2 hash record: the variant, version and parameters
+// the key was derived with, the salt and the derived key.
+type Hash struct {
+	params Params
+	salt   []byte
+	key    []byte
+}
+
+// ParseHash decodes the provided hash, in any of the supported formats,
+// into a Hash record.
+func ParseHash(encodedHash []byte) (*Hash, error) {
+	return ParseHashMode(encodedHash, ParseStrict)
+}
+
+// ParseHashMode is like ParseHash, but parses the provided hash in the
+// given mode. Use ParseLenient for hashes of uncertain provenance, such as
+// lines of an exported file.
+func ParseHashMode(encodedHash []byte, mode ParseMode) (*Hash, error) {
+	p, salt, key, err := decodeHashInto(nil, encodedHash, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hash{params: *p, salt: salt, key: key}, nil
+}
+
+// Algorithm returns the argon2 variant the key was derived with.
+func (h *Hash) Algorithm() Variant {
+	return h.params.Variant
+}
+
+// Version returns the argon2 version the key was derived with,
+// e.g. 0x13 (19).
+func (h *Hash) Version() uint32 {
+	return h.params.version()
+}
+
+// Params returns a copy of the parameters the key was derived with,
+// including the format the hash was encoded in.
+func (h *Hash) Params() *Params {
+	return h.params.Clone()
+}
+
+// Salt returns a copy of the salt.
+func (h *Hash) Salt() []byte {
+	return append([]byte(nil), h.salt...)
+}
+
+// Key returns a copy of the derived key.
+func (h *Hash) Key() []byte {
+	return append([]byte(nil), h.key...)
+}
+
+// Verify compares the hash with the password, like CompareHashAndPassword,
+// without parsing it again. It returns nil on success, and
+// ErrMismatchedHashAndPassword if they do not match.
+func (h *Hash) Verify(password []byte) error {
+	defer padVerify(time.Now())
+
+	if err := h.params.checkInputs(nil, nil); err != nil {
+		return err
+	}
+	if err := verifyRaw(password, h.salt, h.key, nil, &h.params); err != nil {
+		return err
+	}
+	notifyWeakHash(&h.params)
+	return nil
+}
+
+// Encode returns the hash encoded as text, in the format it was parsed from.
+func (h *Hash) Encode() []byte {
+	return appendEncoded(nil, h.params.Format, &h.params, h.salt, h.key)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface,
+// encoding the hash as Encode does.
+func (h *Hash) MarshalText() ([]byte, error) {
+	return h.Encode(), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, decoding
+// a hash in any of the supported formats, like ParseHash. It returns an
+// error if the text isn't a valid hash.
+func (h *Hash) UnmarshalText(text []byte) error {
+	p, salt, key, err := decodeHashInto(nil, text, ParseStrict)
+	if err != nil {
+		return err
+	}
+
+	h.params, h.salt, h.key = *p, salt, key
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The compact binary encoding holds the encoding version, the variant,
+// the argon2 version, the parameters, the salt and the derived key,
+// followed by the key ID, associated data, pre-hash function and
+// normalization if one of them is set.
+func (h *Hash) MarshalBinary() ([]byte, error) {
+	var flags byte
+	switch h.params.Format {
+	case FormatPHC:
+		flags |= flagPHC
+	case FormatDjango:
+		flags |= flagDjango
+	}
+	if h.params.URLSafe {
+		flags |= flagURLSafe
+	}
+
+	p := &h.params
+	version := byte(binaryVersion)
+	if p.extended() {
+		version = binaryVersionExtended
+	}
+
+	b := make([]byte, 0, 7+6*binary.MaxVarintLen32+len(h.salt)+len(h.key)+len(p.KeyID)+len(p.Data))
+	b = append(b, version, byte(p.Variant), byte(p.version()), flags)
+	b = appendUvarint(b, uint64(p.Memory))
+	b = appendUvarint(b, uint64(p.Iterations))
+	b = append(b, p.Parallelism)
+	b = appendUvarint(b, uint64(len(h.salt)))
+	b = append(b, h.salt...)
+	b = appendUvarint(b, uint64(len(h.key)))
+	b = append(b, h.key...)
+	if p.extended() {
+		b = appendUvarint(b, uint64(len(p.KeyID)))
+		b = append(b, p.KeyID...)
+		b = appendUvarint(b, uint64(len(p.Data)))
+		b = append(b, p.Data...)
+		b = append(b, byte(p.PreHash), byte(p.Normalization))
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It returns ErrInvalidHash if the data is not a valid binary encoding
+// of a hash, and ErrUnknownAlgorithm or ErrIncompatibleVersion if the
+// variant or the argon2 version are not supported.
+func (h *Hash) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 || (data[0] != binaryVersion && data[0] != binaryVersionExtended) {
+		return ErrInvalidHash
+	}
+
+	var p Params
+	p.Variant = Variant(data[1])
+	if _, ok := variantNames[p.Variant]; !ok {
+		return ErrUnknownAlgorithm
+	}
+
+	var err error
+	if p.Version, err = checkVersion(uint64(data[2])); err != nil {
+		return err
+	}
+
+	flags := data[3]
+	switch {
+	case flags&flagPHC != 0 && flags&flagDjango != 0:
+		return ErrInvalidHash
+	case flags&flagPHC != 0:
+		p.Format = FormatPHC
+	case flags&flagDjango != 0:
+		p.Format = FormatDjango
+	}
+	p.URLSafe = flags&flagURLSafe != 0
+
+	d := binaryDecoder{data: data[4:]}
+	p.Memory = uint32(d.uvarint(32))
+	p.Iterations = uint32(d.uvarint(32))
+	p.Parallelism = d.byte()
+	salt := d.bytes()
+	key := d.bytes()
+	if data[0] == binaryVersionExtended {
+		p.KeyID = string(d.bytes())
+		p.Data = string(d.bytes())
+		p.PreHash = PreHash(d.byte())
+		p.Normalization = Normalization(d.byte())
+	}
+	if d.err != nil || len(d.data) != 0 {
+		return ErrInvalidHash
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	if err := p.checkExtensions(); err != nil {
+		return err
+	}
+	// Parameters outside of the spec would have the key derivation panic
+	if err := joinErrors(p.checkSpec()); err != nil {
+		return &HashError{Field: "params", Err: ErrInvalidHash, Cause: err}
+	}
+
+	h.params = p
+	h.salt = append([]byte(nil), salt...)
+	h.key = append([]byte(nil), key...)
+
+	return nil
+}
+
+// hashJSON is the JSON representation of Hash. Salt and key are
+// encoded as standard Base64 strings.
+type hashJSON struct {
+	Algorithm   string `json:"alg"`
+	Version     uint32 `json:"v"`
+	Memory      uint32 `json:"m"`
+	Iterations  uint32 `json:"t"`
+	Parallelism uint8  `json:"p"`
+	Salt        []byte `json:"salt"`
+	Key         []byte `json:"hash"`
+
+	KeyID         string `json:"keyid,omitempty"`
+	Data          []byte `json:"data,omitempty"`
+	PreHash       string `json:"ph,omitempty"`
+	Normalization string `json:"norm,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The hash is
+// represented as an object with the "alg", "v", "m", "t", "p", "salt"
+// and "hash" fields, and the "keyid", "data", "ph" and "norm" fields of
+// the PHC format if they are set.
+func (h *Hash) MarshalJSON() ([]byte, error) {
+	v := hashJSON{
+		Algorithm:   h.params.Variant.String(),
+		Version:     h.params.version(),
+		Memory:      h.params.Memory,
+		Iterations:  h.params.Iterations,
+		Parallelism: h.params.Parallelism,
+		Salt:        h.salt,
+		Key:         h.key,
+		KeyID:       h.params.KeyID,
+	}
+	if h.params.Data != "" {
+		v.Data = []byte(h.params.Data)
+	}
+	if h.params.PreHash != NoPreHash {
+		v.PreHash = h.params.PreHash.String()
+	}
+	if h.params.Normalization != NoNormalization {
+		v.Normalization = h.params.Normalization.String()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It returns
+// ErrInvalidHash if a field is missing or invalid, and ErrUnknownAlgorithm
+// or ErrIncompatibleVersion if the variant or the argon2 version are not
+// supported.
+func (h *Hash) UnmarshalJSON(data []byte) error {
+	var v hashJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var p Params
+	var err error
+	if p.Variant, err = parseVariant([]byte(v.Algorithm)); err != nil {
+		return err
+	}
+	if p.Version, err = checkVersion(uint64(v.Version)); err != nil {
+		return err
+	}
+	p.Memory = v.Memory
+	p.Iterations = v.Iterations
+	p.Parallelism = v.Parallelism
+	p.SaltLength = uint32(len(v.Salt))
+	p.KeyLength = uint32(len(v.Key))
+	p.KeyID = v.KeyID
+	p.Data = string(v.Data)
+	if v.PreHash != "" {
+		if p.PreHash, err = parsePreHash([]byte(v.PreHash)); err != nil {
+			return err
+		}
+	}
+	if v.Normalization != "" {
+		if p.Normalization, err = parseNormalization([]byte(v.Normalization)); err != nil {
+			return err
+		}
+	}
+	// Only the PHC format records the extensions
+	if p.extended() {
+		p.Format = FormatPHC
+	}
+	// Parameters outside of the spec would have the key derivation panic
+	if err := joinErrors(p.checkSpec()); err != nil {
+		return &HashError{Field: "params", Err: ErrInvalidHash, Cause: err}
+	}
+
+	h.params = p
+	h.salt = v.Salt
+	h.key = v.Key
+
+	return nil
+}
+
+// checkExtensions returns ErrUnsupportedParams if the pre-hash function or
+// the normalization of the decoded parameters is unknown, and
+// ErrInvalidHash if the legacy format would have to record extensions.
+func (p *Params) checkExtensions() error {
+	if _, ok := preHashNames[p.PreHash]; !ok && p.PreHash != NoPreHash {
+		return ErrUnsupportedParams
+	}
+	if p.Normalization != NoNormalization && p.Normalization != NFKC {
+		return ErrUnsupportedParams
+	}
+	if p.extended() && p.Format == FormatLegacy {
+		return ErrInvalidHash
+	}
+	return nil
+}
+
+// appendUvarint appends the varint encoding of v to b.
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// binaryDecoder reads the fields of the binary encoding of Hash,
+// remembering the first error.
+type binaryDecoder struct {
+	data []byte
+	err  error
+}
+
+// byte reads a single byte.
+func (d *binaryDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) < 1 {
+		d.err = ErrInvalidHash
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+// uvarint reads a varint that must fit into bitSize bits.
+func (d *binaryDecoder) uvarint(bitSize uint) uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 || v>>bitSize != 0 {
+		d.err = ErrInvalidHash
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// bytes reads a length-prefixed byte string.
+func (d *binaryDecoder) bytes() []byte {
+	n := d.uvarint(32)
+	if d.err != nil {
+		return nil
+	}
+	if uint64(len(d.data)) < n {
+		d.err = ErrInvalidHash
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
@@ -0,0 +1,525 @@
+package argon2
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseHash(t *testing.T) {
+	tests := []struct {
+		name        string
+		encodedHash []byte
+		wantErr     bool
+	}{
+		{
+			name:        "legacy hash",
+			encodedHash: []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+			wantErr:     false,
+		},
+		{
+			name:        "phc hash",
+			encodedHash: []byte("$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+			wantErr:     false,
+		},
+		{
+			name:        "invalid hash",
+			encodedHash: []byte("dwiehduwehc8wh"),
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHash(tt.encodedHash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHash() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(got.Encode()) != string(tt.encodedHash) {
+				t.Errorf("Encode() got = %s, want %s", got.Encode(), tt.encodedHash)
+			}
+		})
+	}
+}
+
+func TestParseHashMode(t *testing.T) {
+	type args struct {
+		encodedHash []byte
+		mode        ParseMode
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "strict valid hash",
+			args: args{
+				encodedHash: []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				mode:        ParseStrict,
+			},
+			want: "argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		},
+		{
+			name: "strict trailing newline",
+			args: args{
+				encodedHash: []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU\n"),
+				mode:        ParseStrict,
+			},
+			wantErr: true,
+		},
+		{
+			name: "strict leading dollar",
+			args: args{
+				encodedHash: []byte("$argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				mode:        ParseStrict,
+			},
+			wantErr: true,
+		},
+		{
+			name: "strict padded base64",
+			args: args{
+				encodedHash: []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A==$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="),
+				mode:        ParseStrict,
+			},
+			wantErr: true,
+		},
+		{
+			name: "strict encoder id",
+			args: args{
+				encodedHash: []byte("{argon2}$argon2id$v=19$m=65536,t=3,p=2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				mode:        ParseStrict,
+			},
+			wantErr: true,
+		},
+		{
+			name: "lenient encoder id",
+			args: args{
+				encodedHash: []byte("{ARGON2ID}$argon2id$v=19$m=65536,t=3,p=2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				mode:        ParseLenient,
+			},
+			want: "$argon2id$v=19$m=65536,t=3,p=2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		},
+		{
+			name: "lenient surrounding whitespace",
+			args: args{
+				encodedHash: []byte(" argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU\r\n"),
+				mode:        ParseLenient,
+			},
+			want: "argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		},
+		{
+			name: "lenient leading dollar",
+			args: args{
+				encodedHash: []byte("$argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+				mode:        ParseLenient,
+			},
+			want: "argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		},
+		{
+			name: "lenient padded base64",
+			args: args{
+				encodedHash: []byte("$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ=$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc=\n"),
+				mode:        ParseLenient,
+			},
+			want: "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		},
+		{
+			name: "lenient invalid hash",
+			args: args{
+				encodedHash: []byte("  dwiehduwehc8wh\n"),
+				mode:        ParseLenient,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHashMode(tt.args.encodedHash, tt.args.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHashMode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(got.Encode()) != tt.want {
+				t.Errorf("Encode() got = %s, want %s", got.Encode(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHash_MarshalBinary(t *testing.T) {
+	tests := []struct {
+		name        string
+		encodedHash []byte
+	}{
+		{
+			name:        "legacy hash",
+			encodedHash: []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+		},
+		{
+			name:        "phc hash",
+			encodedHash: []byte("$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+		},
+		{
+			name:        "django hash",
+			encodedHash: []byte("argon2$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+		},
+		{
+			name:        "version 16 argon2d url-safe hash",
+			encodedHash: []byte("$argon2d$v=16$m=65536,t=3,p=2$6pAg-fVI2vB9uenAuOTK0A$VPg50e-vxRnvQ8dIFSg1HFNYHYcxEW-Dx47O6vipImU"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseHash(tt.encodedHash)
+			if err != nil {
+				t.Fatalf("ParseHash() error = %v", err)
+			}
+			data, err := h.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			if len(data) >= len(tt.encodedHash) {
+				t.Errorf("MarshalBinary() got len = %v, want less than %v", len(data), len(tt.encodedHash))
+			}
+
+			got := &Hash{}
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, h) {
+				t.Errorf("UnmarshalBinary() got = %v, want %v", got, h)
+			}
+			if string(got.Encode()) != string(tt.encodedHash) {
+				t.Errorf("Encode() got = %s, want %s", got.Encode(), tt.encodedHash)
+			}
+		})
+	}
+}
+
+func TestHash_UnmarshalBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{
+			name:    "valid data",
+			data:    []byte{1, 0, 0x13, 0, 0x80, 0x80, 0x04, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's'},
+			wantErr: nil,
+		},
+		{
+			name:    "empty data",
+			data:    nil,
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "unknown encoding version",
+			data:    []byte{3, 0, 0x13, 0, 0x80, 0x80, 0x04, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's'},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "unknown variant",
+			data:    []byte{1, 42, 0x13, 0, 0x80, 0x80, 0x04, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's'},
+			wantErr: ErrUnknownAlgorithm,
+		},
+		{
+			name:    "incompatible version",
+			data:    []byte{1, 0, 0x12, 0, 0x80, 0x80, 0x04, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's'},
+			wantErr: ErrIncompatibleVersion,
+		},
+		{
+			name:    "truncated key",
+			data:    []byte{1, 0, 0x13, 0, 0x80, 0x80, 0x04, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y'},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "trailing data",
+			data:    []byte{1, 0, 0x13, 0, 0x80, 0x80, 0x04, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's', 'e'},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "out of spec memory",
+			data:    []byte{1, 0, 0x13, 0, 0xff, 0xff, 0xff, 0xff, 0x0f, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's'},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "too short salt",
+			data:    []byte{1, 0, 0x13, 0, 0x80, 0x80, 0x04, 3, 2, 3, 's', 'a', 'l', 4, 'k', 'e', 'y', 's'},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "too little memory per lane",
+			data:    []byte{1, 0, 0x13, 0, 16, 3, 4, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's'},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "valid extended data",
+			data:    []byte{2, 0, 0x13, 1, 0x80, 0x80, 0x04, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's', 1, 'k', 0, 0, 0},
+			wantErr: nil,
+		},
+		{
+			name:    "out of spec extended data",
+			data:    []byte{2, 0, 0x13, 1, 0xff, 0xff, 0xff, 0xff, 0x0f, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's', 1, 'k', 0, 0, 0},
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "memory overflow",
+			data:    []byte{1, 0, 0x13, 0, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01, 3, 2, 8, 's', 'a', 'l', 't', 's', 'a', 'l', 't', 4, 'k', 'e', 'y', 's'},
+			wantErr: ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Hash{}).UnmarshalBinary(tt.data); !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("UnmarshalBinary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHash_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		encodedHash []byte
+		password    []byte
+		want        string
+	}{
+		{
+			name:        "legacy hash",
+			encodedHash: []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+			password:    []byte("qwerty123"),
+			want:        `{"alg":"argon2id","v":19,"m":65536,"t":3,"p":2,"salt":"6pAg+fVI2vB9uenAuOTK0A==","hash":"VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="}`,
+		},
+		{
+			name:        "version 16 hash",
+			encodedHash: []byte("$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$mA69JKTmZ/FjRvnUp4sXVyh4NhPgzG+xfC7IhLFkNd8"),
+			password:    []byte("password"),
+			want:        `{"alg":"argon2id","v":16,"m":65536,"t":2,"p":1,"salt":"c29tZXNhbHQ=","hash":"mA69JKTmZ/FjRvnUp4sXVyh4NhPgzG+xfC7IhLFkNd8="}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseHash(tt.encodedHash)
+			if err != nil {
+				t.Fatalf("ParseHash() error = %v", err)
+			}
+			data, err := json.Marshal(h)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("MarshalJSON() got = %s, want %s", data, tt.want)
+			}
+
+			got := &Hash{}
+			if err := json.Unmarshal(data, got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if err := CompareHashAndPassword(got.Encode(), tt.password); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestHash_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name:    "valid hash",
+			data:    `{"alg":"argon2id","v":19,"m":65536,"t":3,"p":2,"salt":"6pAg+fVI2vB9uenAuOTK0A==","hash":"VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="}`,
+			wantErr: false,
+		},
+		{
+			name:    "unknown algorithm",
+			data:    `{"alg":"bcrypt","v":19,"m":65536,"t":3,"p":2,"salt":"6pAg+fVI2vB9uenAuOTK0A==","hash":"VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="}`,
+			wantErr: true,
+		},
+		{
+			name:    "incompatible version",
+			data:    `{"alg":"argon2id","v":18,"m":65536,"t":3,"p":2,"salt":"6pAg+fVI2vB9uenAuOTK0A==","hash":"VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing iterations",
+			data:    `{"alg":"argon2id","v":19,"m":65536,"p":2,"salt":"6pAg+fVI2vB9uenAuOTK0A==","hash":"VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing hash",
+			data:    `{"alg":"argon2id","v":19,"m":65536,"t":3,"p":2,"salt":"6pAg+fVI2vB9uenAuOTK0A=="}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid salt",
+			data:    `{"alg":"argon2id","v":19,"m":65536,"t":3,"p":2,"salt":"$$$","hash":"VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="}`,
+			wantErr: true,
+		},
+		{
+			name:    "out of spec params",
+			data:    `{"alg":"argon2id","v":19,"m":4294967295,"t":3,"p":2,"salt":"c2FsdA==","hash":"VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="}`,
+			wantErr: true,
+		},
+		{
+			name:    "too little memory per lane",
+			data:    `{"alg":"argon2id","v":19,"m":16,"t":3,"p":4,"salt":"6pAg+fVI2vB9uenAuOTK0A==","hash":"VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU="}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.data), &Hash{}); (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHash_Accessors(t *testing.T) {
+	h, err := ParseHash([]byte("$argon2i$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$wWKIMhR9lyDFvRz9YTZweHKfbftvj+qf+YFY4NeBbtA"))
+	if err != nil {
+		t.Fatalf("ParseHash() error = %v", err)
+	}
+
+	if got := h.Algorithm(); got != Argon2i {
+		t.Errorf("Algorithm() got = %v, want %v", got, Argon2i)
+	}
+	if got := h.Version(); got != 0x13 {
+		t.Errorf("Version() got = %#x, want 0x13", got)
+	}
+	want := &Params{Memory: 64 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 8, KeyLength: 32, Format: FormatPHC, Variant: Argon2i}
+	if got := h.Params(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params() got = %+v, want %+v", got, want)
+	}
+	if got := h.Salt(); string(got) != "somesalt" {
+		t.Errorf("Salt() got = %q, want %q", got, "somesalt")
+	}
+	if got := h.Key(); len(got) != 32 {
+		t.Errorf("Key() got %d bytes, want 32", len(got))
+	}
+
+	// the accessors return copies
+	h.Salt()[0] = 'x'
+	if got := h.Salt(); string(got) != "somesalt" {
+		t.Errorf("Salt() got = %q after modifying a copy", got)
+	}
+
+	if err := h.Verify([]byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := h.Verify([]byte("wrong")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("Verify() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+}
+
+func TestHash_MarshalText(t *testing.T) {
+	hashes := []string{
+		"argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+	}
+	for _, hash := range hashes {
+		t.Run(hash, func(t *testing.T) {
+			var h Hash
+			if err := h.UnmarshalText([]byte(hash)); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+			got, err := h.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+			if string(got) != hash {
+				t.Errorf("MarshalText() got = %s, want %s", got, hash)
+			}
+		})
+	}
+
+	var h Hash
+	if err := h.UnmarshalText([]byte("dwiehduwehc8wh")); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("UnmarshalText() error = %v, want %v", err, ErrInvalidHash)
+	}
+}
+
+func TestHash_RoundTripExtensions(t *testing.T) {
+	password := []byte("password")
+	opts := []Option{WithMemory(8 * 1024), WithIterations(1), WithParallelism(1), WithFormat(FormatPHC)}
+
+	preHashed, err := HashPassword(password, append(opts, WithPreHash(PreHashSHA512, 0))...)
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	withData, err := HashPassword(password, append(opts, WithAssociatedData([]byte("user-42")))...)
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	withKeyID, err := GenerateFromPasswordWithProvider(password, &KeyRing{CurrentID: "k1", Keys: map[string][]byte{"k1": []byte("secret")}}, &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithProvider() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		encodedHash []byte
+		verify      bool
+	}{
+		{name: "pre-hash", encodedHash: preHashed, verify: true},
+		{name: "associated data", encodedHash: withData},
+		{name: "key id", encodedHash: withKeyID},
+		{name: "django pre-hash", encodedHash: append([]byte("argon2"), preHashed...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseHash(tt.encodedHash)
+			if err != nil {
+				t.Fatalf("ParseHash() error = %v", err)
+			}
+
+			data, err := h.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			fromBinary := &Hash{}
+			if err := fromBinary.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !reflect.DeepEqual(fromBinary, h) {
+				t.Errorf("UnmarshalBinary() got = %v, want %v", fromBinary, h)
+			}
+
+			data, err = json.Marshal(h)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			fromJSON := &Hash{}
+			if err := json.Unmarshal(data, fromJSON); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if !fromJSON.Params().Equal(h.Params()) {
+				t.Errorf("UnmarshalJSON() got params = %v, want %v", fromJSON.Params(), h.Params())
+			}
+
+			for _, got := range []*Hash{fromBinary, fromJSON} {
+				if !tt.verify {
+					continue
+				}
+				if err := got.Verify(password); err != nil {
+					t.Errorf("Verify() error = %v", err)
+				}
+				if err := CompareHashAndPassword(got.Encode(), password); err != nil {
+					t.Errorf("CompareHashAndPassword() of %s error = %v", got.Encode(), err)
+				}
+			}
+		})
+	}
+}
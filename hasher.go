@@ -0,0 +1,221 @@
+package argon2
+
+import "time"
+
+// PasswordHasher hashes passwords. It is implemented by Hasher, so
+// application code can depend on it and substitute fakes in tests.
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the password.
+	Hash(password []byte) ([]byte, error)
+}
+
+// PasswordVerifier verifies passwords against encoded hashes. It is
+// implemented by Hasher.
+type PasswordVerifier interface {
+	// Verify compares the encoded hash with the password. It returns nil on
+	// success, and ErrMismatchedHashAndPassword if they do not match.
+	Verify(hash, password []byte) error
+}
+
+// Hasher hashes and verifies passwords with a configuration bound once, so
+// services can be handed a single configured object instead of threading
+// the parameters, pepper and rehash policy through every call. A Hasher is
+// immutable and safe for concurrent use.
+type Hasher struct {
+	params  Params
+	secret  []byte
+	pepper  []byte
+	peppers PepperMAC
+	policy  RehashPolicy
+
+	preHash          PreHash
+	preHashThreshold int
+
+	encryption     SecretProvider
+	passwordPolicy Policy
+}
+
+// NewHasher returns a Hasher using the Defaults adjusted by the given
+// options, e.g. WithParams, WithFormat, WithPepper, WithSecretProvider or
+// WithPepperMAC and WithRehashPolicy. It returns an error if the resulting
+// parameters are invalid, and ErrInvalidParams if WithSalt or
+// WithAssociatedData is given, as every hash must get its own random salt
+// and associated data.
+func NewHasher(opts ...Option) (*Hasher, error) {
+	o := options{params: *Defaults()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.salt != nil || o.params.Data != "" {
+		return nil, ErrInvalidParams
+	}
+	if err := o.params.Check(); err != nil {
+		return nil, err
+	}
+	if err := checkPreHash(&o.params, o.preHash); err != nil {
+		return nil, err
+	}
+	if err := o.checkNormalization(); err != nil {
+		return nil, err
+	}
+
+	h := &Hasher{
+		params:  o.params,
+		secret:  append([]byte(nil), o.secret...),
+		pepper:  append([]byte(nil), o.pepper...),
+		peppers: o.peppers,
+		policy:  o.policy,
+
+		preHash:          o.preHash,
+		preHashThreshold: o.preHashThreshold,
+
+		encryption:     o.encryption,
+		passwordPolicy: o.passwordPolicy,
+	}
+	if h.policy == nil {
+		h.policy = preHashPolicy(paramsPolicy(&h.params), h.preHash)
+	}
+
+	return h, nil
+}
+
+// Params returns a copy of the parameters of new hashes.
+func (h *Hasher) Params() *Params {
+	return h.params.Clone()
+}
+
+// Hash returns the encoded hash of the password, like GenerateFromPassword.
+// With a SecretProvider or a PepperMAC, it is generated with the current
+// pepper and prefixed with its key ID. With WithHashEncryption, it is
+// encrypted by EncryptHash.
+func (h *Hasher) Hash(password []byte) ([]byte, error) {
+	hash, err := h.hash(password)
+	if err != nil || h.encryption == nil {
+		return hash, err
+	}
+
+	return EncryptHash(hash, h.encryption)
+}
+
+func (h *Hasher) hash(password []byte) ([]byte, error) {
+	if err := checkNewPasswordWith(password, h.passwordPolicy); err != nil {
+		return nil, err
+	}
+	if h.peppers == nil {
+		peppered := applyPepper(password, h.pepper)
+		defer wipePeppered(peppered, password)
+
+		return appendDerived(nil, peppered, h.secret, h.paramsFor(peppered))
+	}
+
+	keyID := h.peppers.CurrentID()
+	dst, err := appendPepperID(nil, keyID)
+	if err != nil {
+		return nil, err
+	}
+	peppered, err := h.peppers.MAC(keyID, password)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(peppered)
+
+	return appendDerived(dst, peppered, h.secret, h.paramsFor(peppered))
+}
+
+// paramsFor returns the parameters of the hash of the password, pre-hashing
+// it if it exceeds the threshold set by WithPreHash.
+func (h *Hasher) paramsFor(password []byte) *Params {
+	return preHashParams(&h.params, h.preHash, h.preHashThreshold, password)
+}
+
+// Verify compares the encoded hash with the password, like
+// CompareHashAndPassword. It returns nil on success, and
+// ErrMismatchedHashAndPassword if they do not match.
+func (h *Hasher) Verify(hash, password []byte) error {
+	defer padVerify(time.Now())
+
+	if err := checkPassword(password); err != nil {
+		return err
+	}
+	hash, _, err := h.decrypt(hash)
+	if err != nil {
+		return err
+	}
+	keyID, hash, ok := splitPepperID(hash)
+	if !ok {
+		peppered := applyPepper(password, h.pepper)
+		defer wipePeppered(peppered, password)
+
+		_, err := compareHashAndPassword(nil, hash, peppered, h.secret)
+		return err
+	}
+
+	if h.peppers == nil {
+		return ErrUnknownKeyID
+	}
+	peppered, err := h.peppers.MAC(keyID, password)
+	if err != nil {
+		return err
+	}
+	defer wipe(peppered)
+
+	_, err = compareHashAndPassword(nil, hash, peppered, h.secret)
+	return err
+}
+
+// NeedsRehash reports whether the encoded hash should be regenerated,
+// as decided by the rehash policy of the Hasher. With a SecretProvider or
+// a PepperMAC, the hashes of another pepper than the current one need a
+// rehash too, as do the hashes that aren't encrypted under the current
+// secret with WithHashEncryption.
+func (h *Hasher) NeedsRehash(hash []byte) (bool, error) {
+	hash, reencrypt, err := h.decrypt(hash)
+	if err != nil {
+		return false, err
+	}
+
+	keyID, rest, ok := splitPepperID(hash)
+	rehash, err := NeedsRehashWithPolicy(rest, h.policy)
+	if err != nil || rehash || reencrypt || h.peppers == nil {
+		return err == nil && (rehash || reencrypt), err
+	}
+
+	return !ok || keyID != h.peppers.CurrentID(), nil
+}
+
+// decrypt returns the hash decrypted by DecryptHash with WithHashEncryption,
+// or as is otherwise. reencrypt reports whether the hash isn't encrypted
+// under the current secret.
+func (h *Hasher) decrypt(hash []byte) (plain []byte, reencrypt bool, err error) {
+	if h.encryption == nil {
+		return hash, false, nil
+	}
+
+	keyID, _, ok := splitKeyID(hash, encryptedPrefix)
+	if !ok {
+		return hash, true, nil
+	}
+	if plain, err = DecryptHash(hash, h.encryption); err != nil {
+		return nil, false, err
+	}
+
+	currentID, _ := h.encryption.Current()
+	return plain, keyID != currentID, nil
+}
+
+// CompareAndUpdate verifies the password like Verify, and on success
+// returns a fresh hash of the password if the hash needs a rehash, as
+// reported by NeedsRehash, or nil otherwise.
+func (h *Hasher) CompareAndUpdate(hash, password []byte) (newHash []byte, err error) {
+	if err := h.Verify(hash, password); err != nil {
+		return nil, err
+	}
+
+	rehash, err := h.NeedsRehash(hash)
+	if err != nil || !rehash || checkNewPasswordWith(password, h.passwordPolicy) != nil {
+		return nil, err
+	}
+
+	return h.Hash(password)
+}
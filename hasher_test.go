@@ -0,0 +1,95 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewHasher(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "defaults"},
+		{name: "adjusted params", opts: []Option{WithMemory(8 * 1024), WithFormat(FormatPHC)}},
+		{name: "invalid params", opts: []Option{WithIterations(0)}, wantErr: ErrIterationsTooSmall},
+		{name: "fixed salt", opts: []Option{WithSalt([]byte("somesaltsomesalt"))}, wantErr: ErrInvalidParams},
+		{name: "associated data", opts: []Option{WithFormat(FormatPHC), WithAssociatedData([]byte("user-1"))}, wantErr: ErrInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHasher(tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewHasher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasher(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	pepper := []byte("server-pepper")
+
+	h, err := NewHasher(WithParams(p), WithSecret(pepper))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	hash, err := h.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	if err := h.Verify(hash, []byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := h.Verify(hash, []byte("wrong")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("Verify() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+	if err := CompareHashAndPassword(hash, []byte("password")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareHashAndPassword() without pepper error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+	if rehash, err := h.NeedsRehash(hash); err != nil || rehash {
+		t.Errorf("NeedsRehash() got = %v, error = %v, want false, nil", rehash, err)
+	}
+
+	stronger, err := NewHasher(WithParams(p), WithIterations(2), WithSecret(pepper))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	if rehash, err := stronger.NeedsRehash(hash); err != nil || !rehash {
+		t.Errorf("NeedsRehash() got = %v, error = %v, want true, nil", rehash, err)
+	}
+	newHash, err := stronger.CompareAndUpdate(hash, []byte("password"))
+	if err != nil || newHash == nil {
+		t.Fatalf("CompareAndUpdate() got = %s, error = %v", newHash, err)
+	}
+	if err := stronger.Verify(newHash, []byte("password")); err != nil {
+		t.Errorf("Verify() of the new hash error = %v", err)
+	}
+
+	never, err := NewHasher(WithParams(p), WithIterations(2), WithRehashPolicy(RehashPolicyFunc(func(*Params) bool { return false })))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	if rehash, err := never.NeedsRehash(hash); err != nil || rehash {
+		t.Errorf("NeedsRehash() with policy got = %v, error = %v, want false, nil", rehash, err)
+	}
+}
+
+func TestHasher_Interfaces(t *testing.T) {
+	h, err := NewHasher(WithMemory(8*1024), WithIterations(1))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+
+	var hasher PasswordHasher = h
+	var verifier PasswordVerifier = h
+
+	hash, err := hasher.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if err := verifier.Verify(hash, []byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
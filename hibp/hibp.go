@@ -0,0 +1,160 @@
+// Package hibp provides an argon2.Policy rejecting the passwords known to be
+// breached, with the Pwned Passwords range API of Have I Been Pwned, so they
+// are never stored:
+//
+//	argon2.SetPolicy(&hibp.Checker{})
+//
+// The password never leaves the process: only the first 5 hexadecimal
+// characters of its SHA-1 hash are sent, and the suffixes of the breached
+// hashes starting with them are compared locally (k-anonymity). The
+// responses are padded, so their size doesn't reveal the prefix either.
+package hibp
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// DefaultEndpoint is the URL of the Pwned Passwords range API, followed by
+// the hash prefix.
+const DefaultEndpoint = "https://api.pwnedpasswords.com/range/"
+
+// DefaultTimeout is the default timeout of the requests to the API.
+const DefaultTimeout = 2 * time.Second
+
+// ErrBreached is returned when a password appears in a data breach.
+var ErrBreached = errors.New("hibp: the password appears in a data breach")
+
+// ErrUnavailable is returned when the API can't be queried, unless the
+// Checker fails open.
+var ErrUnavailable = errors.New("hibp: the Pwned Passwords API is unavailable")
+
+// BreachedError is returned for a breached password, with the number of
+// times it appears in the breaches. It wraps ErrBreached and
+// argon2.ErrPolicyViolation, so it can be matched with errors.Is.
+type BreachedError struct {
+	Count int // The number of times the password appears in the breaches
+}
+
+// Error implements the error interface.
+func (e *BreachedError) Error() string {
+	return ErrBreached.Error() + " " + strconv.Itoa(e.Count) + " times"
+}
+
+// Unwrap returns ErrBreached and argon2.ErrPolicyViolation.
+func (e *BreachedError) Unwrap() []error {
+	return []error{ErrBreached, argon2.ErrPolicyViolation}
+}
+
+// Checker is an argon2.Policy rejecting the breached passwords. Its zero
+// value queries the public API, fails closed, and rejects a password
+// appearing in any breach. It is safe for concurrent use.
+type Checker struct {
+	Endpoint  string        // The URL of the range API, DefaultEndpoint if empty
+	Client    *http.Client  // The client of the requests, http.DefaultClient if nil
+	Timeout   time.Duration // The timeout of the requests, DefaultTimeout if zero
+	MinCount  int           // The number of breaches rejecting a password, 1 if zero
+	FailOpen  bool          // Accept the passwords when the API can't be queried, instead of rejecting them
+	UserAgent string        // The User-Agent of the requests, required by the API, "argon2-hashing" if empty
+}
+
+// Check implements the argon2.Policy interface. It returns a BreachedError
+// if the password is breached, and an error wrapping ErrUnavailable if the
+// API can't be queried, unless the Checker fails open.
+func (c *Checker) Check(password []byte) error {
+	return c.CheckContext(context.Background(), password)
+}
+
+// CheckContext is like Check, but the request is also canceled with ctx.
+func (c *Checker) CheckContext(ctx context.Context, password []byte) error {
+	count, err := c.Count(ctx, password)
+	if err != nil {
+		if c.FailOpen {
+			return nil
+		}
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	minCount := c.MinCount
+	if minCount < 1 {
+		minCount = 1
+	}
+	if count >= minCount {
+		return &BreachedError{Count: count}
+	}
+	return nil
+}
+
+// Count returns the number of times the password appears in the breaches,
+// zero if it doesn't.
+func (c *Checker) Count(ctx context.Context, password []byte) (int, error) {
+	sum := sha1.Sum(password)
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	userAgent := c.UserAgent
+	if userAgent == "" {
+		userAgent = "argon2-hashing"
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Add-Padding", "true")
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("hibp: unexpected response status %s", resp.Status)
+	}
+
+	// Every line is "<hash suffix>:<count>", the padding ones with a zero count
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.IndexByte(line, ':')
+		if i < 0 || !strings.EqualFold(line[:i], suffix) {
+			continue
+		}
+		count, err := strconv.Atoi(line[i+1:])
+		if err != nil {
+			return 0, fmt.Errorf("hibp: invalid response line %q", line)
+		}
+		return count, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, nil
+}
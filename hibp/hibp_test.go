@@ -0,0 +1,131 @@
+package hibp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// The SHA-1 hash of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+const breachedRange = `003D68EB55068C33ACE09247EE4C639306B:0
+1E4C9B93F3F0682250B6CF8331B7EE68FD8:9659365
+011053FD0102E94D6AE2F8B83D76FAF94F6:1
+`
+
+func newServer(t *testing.T, handler http.HandlerFunc) *Checker {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Checker{Endpoint: srv.URL + "/range/", Client: srv.Client()}
+}
+
+func rangeHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/range/5BAA6" {
+			fmt.Fprint(w, "003D68EB55068C33ACE09247EE4C639306B:0\n")
+			return
+		}
+		if r.Header.Get("Add-Padding") != "true" || r.Header.Get("User-Agent") == "" {
+			t.Errorf("request headers = %v, want Add-Padding and User-Agent", r.Header)
+		}
+		fmt.Fprint(w, breachedRange)
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		minCount int
+		wantErr  error
+	}{
+		{name: "breached", password: "password", wantErr: ErrBreached},
+		{name: "not breached", password: "correct horse battery staple"},
+		{name: "below the minimum count", password: "password", minCount: 10000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newServer(t, rangeHandler(t))
+			c.MinCount = tt.minCount
+
+			err := c.Check([]byte(tt.password))
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+
+			var be *BreachedError
+			if !errors.As(err, &be) || be.Count != 9659365 {
+				t.Errorf("Check() error = %v, want a BreachedError with count 9659365", err)
+			}
+			if !errors.Is(err, argon2.ErrPolicyViolation) {
+				t.Errorf("Check() error = %v, want %v", err, argon2.ErrPolicyViolation)
+			}
+		})
+	}
+}
+
+func TestChecker_Unavailable(t *testing.T) {
+	failing := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		failOpen bool
+		wantErr  error
+	}{
+		{name: "fail closed", handler: failing, wantErr: ErrUnavailable},
+		{name: "fail open", handler: failing, failOpen: true},
+		{name: "timeout", handler: slow, wantErr: context.DeadlineExceeded},
+		{name: "timeout fail open", handler: slow, failOpen: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newServer(t, tt.handler)
+			c.FailOpen = tt.failOpen
+			c.Timeout = 50 * time.Millisecond
+
+			err := c.Check([]byte("password"))
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChecker_Policy(t *testing.T) {
+	defer argon2.SetPolicy(nil)
+	argon2.SetPolicy(newServer(t, rangeHandler(t)))
+
+	p := &argon2.Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	if _, err := argon2.GenerateFromPassword([]byte("password"), p); !errors.Is(err, ErrBreached) {
+		t.Errorf("GenerateFromPassword() error = %v, want %v", err, ErrBreached)
+	}
+	if _, err := argon2.GenerateFromPassword([]byte("correct horse battery staple"), p); err != nil {
+		t.Errorf("GenerateFromPassword() error = %v", err)
+	}
+}
+
+func TestBreachedError(t *testing.T) {
+	err := &BreachedError{Count: 3}
+	if got := err.Error(); !strings.Contains(got, "3 times") {
+		t.Errorf("Error() got = %q, want the count", got)
+	}
+}
@@ -0,0 +1,133 @@
+// Package history enforces "cannot reuse your last N passwords" policies on
+// top of argon2 hashes, keeping the last hashes of every user in a Store:
+//
+//	h := &history.History{Store: store, Size: 5}
+//	if err := h.Check(ctx, userID, newPassword); err != nil {
+//		return err // history.ErrReused if it is one of the last 5
+//	}
+//	hash, err := argon2.GenerateFromPassword(newPassword, nil)
+//	...
+//	err = h.Add(ctx, userID, hash)
+//
+// Checking a password verifies it against every hash of the history, so
+// it costs up to Size argon2 verifications, on password changes only.
+package history
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// ErrReused is returned when a new password matches one of the hashes of
+// the history.
+var ErrReused = errors.New("history: the password was used recently")
+
+// Store persists the last password hashes of the users, e.g. in a table of
+// the database holding the credentials.
+type Store interface {
+	// Hashes returns the hashes of the user, the most recent first, or none
+	// if the user has no history.
+	Hashes(ctx context.Context, userID string) ([][]byte, error)
+	// Add records a new hash of the user, keeping at most the limit most
+	// recent ones.
+	Add(ctx context.Context, userID string, hash []byte, limit int) error
+}
+
+// CheckReuse returns ErrReused if the new password matches one of the hashes
+// of the history, compared with argon2.CompareHashAndPassword. It returns
+// the errors of the comparison for malformed hashes.
+func CheckReuse(history [][]byte, newPassword []byte) error {
+	return CheckReuseWith(verifierFunc(argon2.CompareHashAndPassword), history, newPassword)
+}
+
+// CheckReuseWith is like CheckReuse, but compares the hashes with the
+// verifier, e.g. an argon2.Hasher with a pepper.
+func CheckReuseWith(v argon2.PasswordVerifier, history [][]byte, newPassword []byte) error {
+	for _, hash := range history {
+		switch err := v.Verify(hash, newPassword); err {
+		case nil:
+			return ErrReused
+		case argon2.ErrMismatchedHashAndPassword:
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifierFunc is an adapter to allow the use of ordinary functions as
+// an argon2.PasswordVerifier.
+type verifierFunc func(hash, password []byte) error
+
+// Verify calls f(hash, password).
+func (f verifierFunc) Verify(hash, password []byte) error {
+	return f(hash, password)
+}
+
+// History checks the new passwords of the users against their last Size
+// hashes kept in the Store.
+type History struct {
+	Store    Store                   // The store of the hashes
+	Size     int                     // The number of hashes kept per user
+	Verifier argon2.PasswordVerifier // The verifier of the hashes, argon2.CompareHashAndPassword if nil
+}
+
+// Check returns ErrReused if the new password of the user matches one of the
+// last Size hashes of its history.
+func (h *History) Check(ctx context.Context, userID string, newPassword []byte) error {
+	hashes, err := h.Store.Hashes(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(hashes) > h.Size {
+		hashes = hashes[:h.Size]
+	}
+
+	v := h.Verifier
+	if v == nil {
+		v = verifierFunc(argon2.CompareHashAndPassword)
+	}
+	return CheckReuseWith(v, hashes, newPassword)
+}
+
+// Add records the hash of the new password of the user, keeping the last
+// Size hashes.
+func (h *History) Add(ctx context.Context, userID string, hash []byte) error {
+	return h.Store.Add(ctx, userID, hash, h.Size)
+}
+
+// MemoryStore is a Store keeping the hashes in memory, e.g. for tests. It
+// is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.Mutex
+	hashes map[string][][]byte
+}
+
+// Hashes implements the Store interface.
+func (s *MemoryStore) Hashes(ctx context.Context, userID string) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([][]byte(nil), s.hashes[userID]...), nil
+}
+
+// Add implements the Store interface.
+func (s *MemoryStore) Add(ctx context.Context, userID string, hash []byte, limit int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hashes == nil {
+		s.hashes = make(map[string][][]byte)
+	}
+	hashes := append([][]byte{append([]byte(nil), hash...)}, s.hashes[userID]...)
+	if len(hashes) > limit {
+		hashes = hashes[:limit]
+	}
+	s.hashes[userID] = hashes
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+var testParams = &argon2.Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestCheckReuse(t *testing.T) {
+	history := [][]byte{
+		argon2.MustGenerateFromPassword([]byte("password-2"), testParams),
+		argon2.MustGenerateFromPassword([]byte("password-1"), testParams),
+	}
+
+	tests := []struct {
+		name     string
+		history  [][]byte
+		password string
+		wantErr  error
+	}{
+		{name: "latest", history: history, password: "password-2", wantErr: ErrReused},
+		{name: "older", history: history, password: "password-1", wantErr: ErrReused},
+		{name: "new", history: history, password: "password-3"},
+		{name: "empty history", password: "password-1"},
+		{name: "malformed hash", history: [][]byte{[]byte("malformed")}, password: "password-1", wantErr: argon2.ErrInvalidHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckReuse(tt.history, []byte(tt.password)); !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("CheckReuse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckReuseWith(t *testing.T) {
+	hasher, err := argon2.NewHasher(argon2.WithParams(testParams), argon2.WithPepper([]byte("pepper")))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	hash, err := hasher.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	if err := CheckReuseWith(hasher, [][]byte{hash}, []byte("password")); err != ErrReused {
+		t.Errorf("CheckReuseWith() error = %v, want %v", err, ErrReused)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	h := &History{Store: &MemoryStore{}, Size: 3}
+
+	for i := 1; i <= 4; i++ {
+		hash := argon2.MustGenerateFromPassword([]byte("password-"+strconv.Itoa(i)), testParams)
+		if err := h.Add(ctx, "alice", hash); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		userID   string
+		password string
+		wantErr  error
+	}{
+		{name: "latest", userID: "alice", password: "password-4", wantErr: ErrReused},
+		{name: "oldest kept", userID: "alice", password: "password-2", wantErr: ErrReused},
+		{name: "dropped", userID: "alice", password: "password-1"},
+		{name: "other user", userID: "bob", password: "password-4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := h.Check(ctx, tt.userID, []byte(tt.password)); err != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	hashes, err := h.Store.Hashes(ctx, "alice")
+	if err != nil || len(hashes) != 3 {
+		t.Errorf("Hashes() got %d hashes, error = %v, want 3", len(hashes), err)
+	}
+}
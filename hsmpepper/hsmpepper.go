@@ -0,0 +1,78 @@
+// Package hsmpepper provides an argon2.PepperMAC computing the pepper HMACs
+// inside a hardware security module, e.g. through PKCS#11, so the peppers
+// never enter the memory of the process.
+//
+// The package doesn't depend on any PKCS#11 binding: the HMAC is computed
+// by a function, e.g. with github.com/miekg/pkcs11 and an open session:
+//
+//	sign := func(label string, data []byte) ([]byte, error) {
+//		template := []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_LABEL, label)}
+//		if err := ctx.FindObjectsInit(session, template); err != nil {
+//			return nil, err
+//		}
+//		keys, _, err := ctx.FindObjects(session, 1)
+//		ctx.FindObjectsFinal(session)
+//		if err != nil {
+//			return nil, err
+//		}
+//		if len(keys) == 0 {
+//			return nil, argon2.ErrUnknownKeyID
+//		}
+//		mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_SHA256_HMAC, nil)}
+//		if err := ctx.SignInit(session, mechanism, keys[0]); err != nil {
+//			return nil, err
+//		}
+//		return ctx.Sign(session, data)
+//	}
+//	hasher, err := argon2.NewHasher(argon2.WithPepperMAC(hsmpepper.New(sign, "pepper-2024")))
+//
+// The key IDs recorded in the hashes are the labels of the keys in the HSM.
+package hsmpepper
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sync"
+)
+
+// ErrInvalidMAC is returned when the HSM doesn't return an HMAC-SHA256.
+var ErrInvalidMAC = errors.New("hsmpepper: the HSM returned an invalid HMAC")
+
+// SignFunc computes the HMAC-SHA256 of data with the secret key of the
+// label inside the HSM, e.g. with the CKM_SHA256_HMAC mechanism of PKCS#11.
+type SignFunc func(keyLabel string, data []byte) ([]byte, error)
+
+// Provider is an argon2.PepperMAC computing the pepper HMACs in an HSM.
+// The calls to the SignFunc are serialized, as PKCS#11 sessions must not be
+// used concurrently, so it is safe for concurrent use.
+type Provider struct {
+	mu        sync.Mutex
+	sign      SignFunc
+	currentID string
+}
+
+// New returns a Provider computing the pepper HMACs with sign, using the
+// key labeled currentID for new hashes.
+func New(sign SignFunc, currentID string) *Provider {
+	return &Provider{sign: sign, currentID: currentID}
+}
+
+// MAC implements the argon2.PepperMAC interface.
+func (p *Provider) MAC(keyID string, password []byte) ([]byte, error) {
+	p.mu.Lock()
+	mac, err := p.sign(keyID, password)
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(mac) != sha256.Size {
+		return nil, ErrInvalidMAC
+	}
+
+	return mac, nil
+}
+
+// CurrentID implements the argon2.PepperMAC interface.
+func (p *Provider) CurrentID() string {
+	return p.currentID
+}
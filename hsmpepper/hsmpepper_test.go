@@ -0,0 +1,67 @@
+package hsmpepper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// keys stand in for the secret keys stored in the HSM.
+var keys = map[string][]byte{"pepper-2024": []byte("hsm-resident-key")}
+
+// softHSM computes the HMACs as the HSM would.
+func softHSM(label string, data []byte) ([]byte, error) {
+	key, ok := keys[label]
+	if !ok {
+		return nil, argon2.ErrUnknownKeyID
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func TestProvider(t *testing.T) {
+	h, err := argon2.NewHasher(argon2.WithMemory(8*1024), argon2.WithIterations(1), argon2.WithPepperMAC(New(softHSM, "pepper-2024")))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	hash, err := h.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if err := h.Verify(hash, []byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := h.Verify(hash, []byte("wrong")); err != argon2.ErrMismatchedHashAndPassword {
+		t.Errorf("Verify() error = %v, want %v", err, argon2.ErrMismatchedHashAndPassword)
+	}
+
+	// the hashes verify with the same pepper held in memory
+	ring := &argon2.KeyRing{CurrentID: "pepper-2024", Keys: keys}
+	soft, err := argon2.NewHasher(argon2.WithMemory(8*1024), argon2.WithIterations(1), argon2.WithSecretProvider(ring))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	if err := soft.Verify(hash, []byte("password")); err != nil {
+		t.Errorf("Verify() with a SecretProvider error = %v", err)
+	}
+
+	missing, _ := argon2.NewHasher(argon2.WithPepperMAC(New(softHSM, "pepper-2025")))
+	if _, err := missing.Hash([]byte("password")); err != argon2.ErrUnknownKeyID {
+		t.Errorf("Hash() error = %v, want %v", err, argon2.ErrUnknownKeyID)
+	}
+}
+
+func TestProvider_InvalidMAC(t *testing.T) {
+	p := New(func(label string, data []byte) ([]byte, error) {
+		sum := sha512.Sum512(data)
+		return sum[:], nil
+	}, "pepper-2024")
+
+	if _, err := p.MAC("pepper-2024", []byte("password")); err != ErrInvalidMAC {
+		t.Errorf("MAC() error = %v, want %v", err, ErrInvalidMAC)
+	}
+}
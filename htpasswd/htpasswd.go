@@ -0,0 +1,225 @@
+// Package htpasswd reads and writes htpasswd files with argon2 entries, so
+// services protected by basic auth can move their users to argon2.
+//
+// Entries are verified with argon2, as well as with the bcrypt and Apache
+// MD5 ("$apr1$") hashes generated by Apache's htpasswd tool, and new
+// entries are always written as argon2 hashes in the PHC format. Users can
+// therefore be migrated on their next successful login:
+//
+//	if err := f.Verify(user, password); err == nil && !htpasswd.IsArgon2(f.Hash(user)) {
+//		err = f.Set(user, password, argon2.DefaultParams)
+//		...
+//	}
+package htpasswd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/andskur/argon2-hashing"
+	"github.com/andskur/argon2-hashing/internal/md5crypt"
+)
+
+// ErrInvalidEntry is returned when a line of an htpasswd file or a user
+// name is malformed.
+var ErrInvalidEntry = errors.New("htpasswd: invalid entry")
+
+// ErrUnknownUser is returned when the user has no entry in the file.
+var ErrUnknownUser = errors.New("htpasswd: unknown user")
+
+// ErrUnsupportedHash is returned when the hash of an entry uses a scheme
+// that can't be verified, such as crypt(3) DES, SHA-1 or plain text.
+var ErrUnsupportedHash = errors.New("htpasswd: unsupported hash scheme")
+
+// File is an htpasswd file. Comments and blank lines are kept, so writing
+// the file back only changes the entries that were set or deleted.
+type File struct {
+	lines []line
+}
+
+// line is a line of an htpasswd file: either an entry, or a comment or
+// blank line kept as is in text.
+type line struct {
+	user, hash string
+	text       string
+}
+
+// ReadFile reads and parses the named htpasswd file.
+func ReadFile(name string) (*File, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+// Parse parses an htpasswd file, made of "user:hash" lines. It returns
+// ErrInvalidEntry if a line, other than a comment or blank line, isn't
+// an entry.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if trimmed := strings.TrimSpace(text); trimmed == "" || trimmed[0] == '#' {
+			f.lines = append(f.lines, line{text: text})
+			continue
+		}
+
+		i := strings.IndexByte(text, ':')
+		if i < 1 || i == len(text)-1 {
+			return nil, ErrInvalidEntry
+		}
+		f.lines = append(f.lines, line{user: text[:i], hash: text[i+1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Users returns the users of the file, in the order of their entries.
+func (f *File) Users() []string {
+	var users []string
+	for _, l := range f.lines {
+		if l.user != "" {
+			users = append(users, l.user)
+		}
+	}
+	return users
+}
+
+// Hash returns the hash of the user's entry, or an empty string if the user
+// has no entry.
+func (f *File) Hash(user string) string {
+	if i := f.index(user); i >= 0 {
+		return f.lines[i].hash
+	}
+	return ""
+}
+
+// Verify compares the password with the hash of the user's entry. It returns
+// nil on success, ErrUnknownUser if the user has no entry,
+// argon2.ErrMismatchedHashAndPassword if the password doesn't match, and
+// ErrUnsupportedHash if the hash can't be verified.
+func (f *File) Verify(user string, password []byte) error {
+	i := f.index(user)
+	if i < 0 {
+		return ErrUnknownUser
+	}
+	hash := []byte(f.lines[i].hash)
+
+	switch {
+	case IsArgon2(f.lines[i].hash):
+		return argon2.CompareHashAndPassword(hash, password)
+	case bytes.HasPrefix(hash, []byte("$2a$")), bytes.HasPrefix(hash, []byte("$2b$")), bytes.HasPrefix(hash, []byte("$2y$")):
+		err := bcrypt.CompareHashAndPassword(hash, password)
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return argon2.ErrMismatchedHashAndPassword
+		}
+		return err
+	case bytes.HasPrefix(hash, []byte(md5crypt.MagicAPR1)):
+		salt := hash[len(md5crypt.MagicAPR1):]
+		j := bytes.IndexByte(salt, '$')
+		if j < 0 {
+			return ErrInvalidEntry
+		}
+		if subtle.ConstantTimeCompare(hash, md5crypt.Crypt(password, salt[:j], md5crypt.MagicAPR1)) == 1 {
+			return nil
+		}
+		return argon2.ErrMismatchedHashAndPassword
+	default:
+		return ErrUnsupportedHash
+	}
+}
+
+// Set sets the user's entry to the argon2 hash of the password, generated
+// with the parameters provided, or argon2.Defaults if nil. The hash is
+// always encoded in the PHC format. The entry is added at the end of the
+// file if the user has none yet.
+func (f *File) Set(user string, password []byte, p *argon2.Params) error {
+	if trimmed := strings.TrimSpace(user); trimmed == "" || trimmed[0] == '#' || strings.ContainsAny(user, ":\r\n") {
+		return ErrInvalidEntry
+	}
+
+	if p == nil {
+		p = argon2.Defaults()
+	}
+	phc := *p
+	phc.Format = argon2.FormatPHC
+
+	hash, err := argon2.GenerateFromPassword(password, &phc)
+	if err != nil {
+		return err
+	}
+
+	if i := f.index(user); i >= 0 {
+		f.lines[i].hash = string(hash)
+		return nil
+	}
+	f.lines = append(f.lines, line{user: user, hash: string(hash)})
+	return nil
+}
+
+// Delete removes the user's entry, and reports whether there was one.
+func (f *File) Delete(user string) bool {
+	i := f.index(user)
+	if i < 0 {
+		return false
+	}
+	f.lines = append(f.lines[:i], f.lines[i+1:]...)
+	return true
+}
+
+// WriteTo writes the file to w, implementing io.WriterTo.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, l := range f.lines {
+		if l.user == "" {
+			buf.WriteString(l.text)
+		} else {
+			buf.WriteString(l.user)
+			buf.WriteByte(':')
+			buf.WriteString(l.hash)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.WriteTo(w)
+}
+
+// WriteFile writes the file to the named file, creating it with the given
+// permissions if needed.
+func (f *File) WriteFile(name string, perm os.FileMode) error {
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, buf.Bytes(), perm)
+}
+
+// IsArgon2 reports whether the hash of an entry is an argon2 hash.
+func IsArgon2(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2")
+}
+
+// index returns the index of the user's first entry, or -1 if there is none.
+func (f *File) index(user string) int {
+	for i, l := range f.lines {
+		if l.user != "" && l.user == user {
+			return i
+		}
+	}
+	return -1
+}
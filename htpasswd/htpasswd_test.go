@@ -0,0 +1,225 @@
+package htpasswd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+const testFile = `# basic auth users
+argon:$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w
+bcrypt:$2y$05$htpasswdsaltsaltsalt..UyPcWgfGd6J5xAEcY9Ua/rrqrEPU0ze
+
+md5:$apr1$3sRpnJ1o$Pp7IuLY.247r9waDj/mzU/
+sha:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantUsers []string
+		wantErr   bool
+	}{
+		{
+			name:      "valid file",
+			input:     testFile,
+			wantUsers: []string{"argon", "bcrypt", "md5", "sha"},
+		},
+		{
+			name:      "crlf line endings",
+			input:     "md5:$apr1$3sRpnJ1o$Pp7IuLY.247r9waDj/mzU/\r\n",
+			wantUsers: []string{"md5"},
+		},
+		{
+			name:    "missing hash",
+			input:   "user:\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing separator",
+			input:   "user\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if users := got.Users(); !reflect.DeepEqual(users, tt.wantUsers) {
+				t.Errorf("Users() = %v, want %v", users, tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestFile_Verify(t *testing.T) {
+	f, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "argon2",
+			user:     "argon",
+			password: "dotnet-password",
+		},
+		{
+			name:     "argon2 mismatch",
+			user:     "argon",
+			password: "htpasswd-secret",
+			wantErr:  argon2.ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "bcrypt",
+			user:     "bcrypt",
+			password: "htpasswd-secret",
+		},
+		{
+			name:     "bcrypt mismatch",
+			user:     "bcrypt",
+			password: "dotnet-password",
+			wantErr:  argon2.ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "apache md5",
+			user:     "md5",
+			password: "htpasswd-secret",
+		},
+		{
+			name:     "apache md5 mismatch",
+			user:     "md5",
+			password: "dotnet-password",
+			wantErr:  argon2.ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "sha1",
+			user:     "sha",
+			password: "htpasswd-secret",
+			wantErr:  ErrUnsupportedHash,
+		},
+		{
+			name:     "unknown user",
+			user:     "nobody",
+			password: "htpasswd-secret",
+			wantErr:  ErrUnknownUser,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := f.Verify(tt.user, []byte(tt.password)); err != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFile_Set(t *testing.T) {
+	f, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &argon2.Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	// migrate an existing entry and add a new one
+	if err := f.Set("md5", []byte("new-secret"), p); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := f.Set("new", []byte("new-secret"), p); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	for _, user := range []string{"md5", "new"} {
+		if !IsArgon2(f.Hash(user)) {
+			t.Errorf("Hash(%q) = %s, want an argon2 hash in the PHC format", user, f.Hash(user))
+		}
+		if err := f.Verify(user, []byte("new-secret")); err != nil {
+			t.Errorf("Verify(%q) error = %v", user, err)
+		}
+	}
+
+	want := []string{"argon", "bcrypt", "md5", "sha", "new"}
+	if users := f.Users(); !reflect.DeepEqual(users, want) {
+		t.Errorf("Users() = %v, want %v", users, want)
+	}
+
+	for _, user := range []string{"", "a:b", "#comment", "a\nb"} {
+		if err := f.Set(user, []byte("new-secret"), p); err != ErrInvalidEntry {
+			t.Errorf("Set(%q) error = %v, want %v", user, err, ErrInvalidEntry)
+		}
+	}
+}
+
+func TestFile_Delete(t *testing.T) {
+	f, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !f.Delete("sha") {
+		t.Errorf("Delete() = false, want true")
+	}
+	if f.Delete("sha") {
+		t.Errorf("Delete() = true, want false")
+	}
+	if err := f.Verify("sha", []byte("htpasswd-secret")); err != ErrUnknownUser {
+		t.Errorf("Verify() error = %v, want %v", err, ErrUnknownUser)
+	}
+}
+
+func TestFile_WriteTo(t *testing.T) {
+	f, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if buf.String() != testFile {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), testFile)
+	}
+}
+
+func TestReadWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "htpasswd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, ".htpasswd")
+
+	f, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.WriteFile(name, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	got, err := ReadFile(name)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, f) {
+		t.Errorf("ReadFile() = %v, want %v", got, f)
+	}
+}
@@ -0,0 +1,82 @@
+// Package bcryptcore computes bcrypt hashes with a given salt and cost, which
+// golang.org/x/crypto/bcrypt doesn't expose. It follows the structure of that
+// package (Copyright 2011 The Go Authors, BSD-style license) and is built on
+// golang.org/x/crypto/blowfish.
+package bcryptcore
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+
+	"golang.org/x/crypto/blowfish"
+)
+
+const (
+	minCost         = 4
+	maxCost         = 31
+	encodedSaltSize = 22
+	encodedHashSize = 31
+)
+
+// ErrInvalidSettings is returned when the settings aren't the ones of a
+// bcrypt hash.
+var ErrInvalidSettings = errors.New("bcryptcore: invalid settings")
+
+// magicCipherData is "OrpheanBeholderScryDoubt" in big-endian bytes.
+var magicCipherData = []byte("OrpheanBeholderScryDoubt")
+
+var encoding = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+
+// Crypt returns the bcrypt hash of the password with the settings of an
+// existing hash: its "$2b$cost$salt" prefix, e.g. "$2b$10$N9qo8uLOickgx2ZMRZoMye",
+// or the whole hash. The version, cost and salt of the settings are kept.
+func Crypt(password, settings []byte) ([]byte, error) {
+	if len(settings) < 4 || settings[0] != '$' || settings[1] != '2' {
+		return nil, ErrInvalidSettings
+	}
+	n := 3
+	if settings[2] != '$' {
+		n = 4
+	}
+	if len(settings) < n+3+encodedSaltSize || settings[n-1] != '$' || settings[n+2] != '$' {
+		return nil, ErrInvalidSettings
+	}
+
+	cost, err := strconv.Atoi(string(settings[n : n+2]))
+	if err != nil || cost < minCost || cost > maxCost {
+		return nil, ErrInvalidSettings
+	}
+	encodedSalt := settings[n+3 : n+3+encodedSaltSize]
+	salt, err := encoding.DecodeString(string(encodedSalt))
+	if err != nil {
+		return nil, ErrInvalidSettings
+	}
+
+	// Bug compatibility with C bcrypt implementations: they use the trailing
+	// NUL in the key string during expansion
+	key := append(password[:len(password):len(password)], 0)
+	c, err := blowfish.NewSaltedCipher(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < 1<<uint(cost); i++ {
+		blowfish.ExpandKey(key, c)
+		blowfish.ExpandKey(salt, c)
+	}
+
+	cipherData := make([]byte, len(magicCipherData))
+	copy(cipherData, magicCipherData)
+	for i := 0; i < len(cipherData); i += blowfish.BlockSize {
+		for j := 0; j < 64; j++ {
+			c.Encrypt(cipherData[i:i+blowfish.BlockSize], cipherData[i:i+blowfish.BlockSize])
+		}
+	}
+
+	// Bug compatibility with C bcrypt implementations: only 23 of the 24
+	// bytes are encoded
+	out := make([]byte, 0, n+3+encodedSaltSize+encodedHashSize)
+	out = append(out, settings[:n+3+encodedSaltSize]...)
+	out = append(out, encoding.EncodeToString(cipherData[:23])...)
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package bcryptcore
+
+import (
+	"testing"
+)
+
+func TestCrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		settings string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "2b settings",
+			password: "pw",
+			settings: "$2b$05$abcdefghijklmnopqrstuu",
+			want:     "$2b$05$abcdefghijklmnopqrstuuHIrMEWpUCQe2YqFR3sXwQ75u4od..9q",
+		},
+		{
+			name:     "2y whole hash",
+			password: "htpasswd-secret",
+			settings: "$2y$05$htpasswdsaltsaltsalt..UyPcWgfGd6J5xAEcY9Ua/rrqrEPU0ze",
+			want:     "$2y$05$htpasswdsaltsaltsalt..UyPcWgfGd6J5xAEcY9Ua/rrqrEPU0ze",
+		},
+		{
+			name:     "cost too low",
+			password: "pw",
+			settings: "$2b$03$abcdefghijklmnopqrstuu",
+			wantErr:  true,
+		},
+		{
+			name:     "short salt",
+			password: "pw",
+			settings: "$2b$05$abcdefghij",
+			wantErr:  true,
+		},
+		{
+			name:     "not bcrypt",
+			password: "pw",
+			settings: "$6$saltsalt$",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Crypt([]byte(tt.password), []byte(tt.settings))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Crypt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("Crypt() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+)
+
+func TestDeriveKey(t *testing.T) {
+	// Test vectors from RFC 9106, section 5
+	password := bytes.Repeat([]byte{0x01}, 32)
+	salt := bytes.Repeat([]byte{0x02}, 16)
+	secret := bytes.Repeat([]byte{0x03}, 8)
+	data := bytes.Repeat([]byte{0x04}, 12)
+
+	tests := []struct {
+		name string
+		mode Mode
+		want string
+	}{
+		{
+			name: "argon2d",
+			mode: Argon2d,
+			want: "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb",
+		},
+		{
+			name: "argon2i",
+			mode: Argon2i,
+			want: "c814d9d1dc7f37aa13f0d77f2494bda1c8de6b016dd388d29952a4c4672b6ce8",
+		},
+		{
+			name: "argon2id",
+			mode: Argon2id,
+			want: "0d640df58d78766c08c037a34a8b53c9d01ef0452d75b65eb52520e96b01e659",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeriveKey(tt.mode, Version13, password, salt, secret, data, 3, 32, 4, 32)
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("DeriveKey() got = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveKeyVersion10(t *testing.T) {
+	// Test vectors from the argon2 reference implementation
+	tests := []struct {
+		name   string
+		mode   Mode
+		time   uint32
+		memory uint32
+		want   string
+	}{
+		{
+			name:   "argon2i t=2 m=65536",
+			mode:   Argon2i,
+			time:   2,
+			memory: 1 << 16,
+			want:   "f6c4db4a54e2a370627aff3db6176b94a2a209a62c8e36152711802f7b30c694",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeriveKey(tt.mode, Version10, []byte("password"), []byte("somesalt"), nil, nil, tt.time, tt.memory, 1, 32)
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("DeriveKey() got = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveKeyMatchesXCrypto(t *testing.T) {
+	password, salt := []byte("qwerty123"), []byte("somesalt")
+
+	tests := []struct {
+		name        string
+		mode        Mode
+		time        uint32
+		memory      uint32
+		threads     uint8
+		keyLen      uint32
+		xcryptoFunc func(password, salt []byte, time, memory uint32, threads uint8, keyLen uint32) []byte
+	}{
+		{name: "argon2i", mode: Argon2i, time: 3, memory: 8 * 1024, threads: 2, keyLen: 32, xcryptoFunc: argon2.Key},
+		{name: "argon2id", mode: Argon2id, time: 3, memory: 8 * 1024, threads: 2, keyLen: 32, xcryptoFunc: argon2.IDKey},
+		{name: "argon2id long key", mode: Argon2id, time: 1, memory: 8 * 1024, threads: 1, keyLen: 100, xcryptoFunc: argon2.IDKey},
+		{name: "argon2id odd memory", mode: Argon2id, time: 2, memory: 8*1024 + 3, threads: 3, keyLen: 16, xcryptoFunc: argon2.IDKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeriveKey(tt.mode, Version13, password, salt, nil, nil, tt.time, tt.memory, tt.threads, tt.keyLen)
+			want := tt.xcryptoFunc(password, salt, tt.time, tt.memory, tt.threads, tt.keyLen)
+			if !bytes.Equal(got, want) {
+				t.Errorf("DeriveKey() got = %x, want %x", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Package md5crypt implements the MD5-based crypt(3) algorithm by
+// Poul-Henning Kamp, as used by "$1$" hashes and, with the "$apr1$" magic,
+// by Apache's htpasswd. It is only meant for verifying legacy hashes.
+package md5crypt
+
+import (
+	"crypto/md5"
+)
+
+// Magic strings of the supported hash formats.
+const (
+	MagicCrypt = "$1$"
+	MagicAPR1  = "$apr1$"
+)
+
+const itoa64 = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Crypt returns the encoded MD5-crypt hash of the password with the given
+// salt and magic, e.g. "$apr1$salt$hash". Only the first 8 bytes of the salt
+// are used.
+func Crypt(password, salt []byte, magic string) []byte {
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+
+	d := md5.New()
+	d.Write(password)
+	d.Write([]byte(magic))
+	d.Write(salt)
+
+	alt := md5.New()
+	alt.Write(password)
+	alt.Write(salt)
+	alt.Write(password)
+	mixin := alt.Sum(nil)
+
+	for i := len(password); i > 0; i -= 16 {
+		if i > 16 {
+			d.Write(mixin)
+		} else {
+			d.Write(mixin[:i])
+		}
+	}
+
+	// The reference implementation writes the first byte of the password,
+	// which is its terminating NUL for an empty password
+	first := []byte{0}
+	if len(password) > 0 {
+		first[0] = password[0]
+	}
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			d.Write([]byte{0})
+		} else {
+			d.Write(first)
+		}
+	}
+	final := d.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		r := md5.New()
+		if i&1 != 0 {
+			r.Write(password)
+		} else {
+			r.Write(final)
+		}
+		if i%3 != 0 {
+			r.Write(salt)
+		}
+		if i%7 != 0 {
+			r.Write(password)
+		}
+		if i&1 != 0 {
+			r.Write(final)
+		} else {
+			r.Write(password)
+		}
+		final = r.Sum(final[:0])
+	}
+
+	out := make([]byte, 0, len(magic)+len(salt)+1+22)
+	out = append(out, magic...)
+	out = append(out, salt...)
+	out = append(out, '$')
+	for _, g := range [5][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		out = appendBase64(out, uint(final[g[0]])<<16|uint(final[g[1]])<<8|uint(final[g[2]]), 4)
+	}
+	return appendBase64(out, uint(final[11]), 2)
+}
+
+// appendBase64 appends the n least significant 6-bit groups of v, least
+// significant first, in the crypt(3) base64 alphabet.
+func appendBase64(dst []byte, v uint, n int) []byte {
+	for ; n > 0; n-- {
+		dst = append(dst, itoa64[v&0x3f])
+		v >>= 6
+	}
+	return dst
+}
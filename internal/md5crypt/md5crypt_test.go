@@ -0,0 +1,51 @@
+package md5crypt
+
+import (
+	"testing"
+)
+
+func TestCrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		salt     string
+		magic    string
+		want     string
+	}{
+		{
+			name:     "apr1",
+			password: "password",
+			salt:     "abcdefgh",
+			magic:    MagicAPR1,
+			want:     "$apr1$abcdefgh$FBwExRW4dCc8aL.OvjpIE1",
+		},
+		{
+			name:     "apr1 empty password and short salt",
+			password: "",
+			salt:     "xy",
+			magic:    MagicAPR1,
+			want:     "$apr1$xy$43..WIhbfuznGvwoCyUek/",
+		},
+		{
+			name:     "crypt long password",
+			password: "a much longer password than sixteen bytes",
+			salt:     "saltsalt",
+			magic:    MagicCrypt,
+			want:     "$1$saltsalt$kL1Bms5vqNqrS8fEEnfCW1",
+		},
+		{
+			name:     "salt truncated to 8 bytes",
+			password: "password",
+			salt:     "abcdefghijkl",
+			magic:    MagicAPR1,
+			want:     "$apr1$abcdefgh$FBwExRW4dCc8aL.OvjpIE1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Crypt([]byte(tt.password), []byte(tt.salt), tt.magic); string(got) != tt.want {
+				t.Errorf("Crypt() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
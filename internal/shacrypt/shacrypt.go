@@ -0,0 +1,178 @@
+// Package shacrypt implements the SHA-256 and SHA-512 based crypt(3)
+// algorithms by Ulrich Drepper, as used by "$5$" and "$6$" hashes, e.g. in
+// /etc/shadow. It is only meant for verifying legacy hashes.
+package shacrypt
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strconv"
+)
+
+// Magic strings of the supported hash formats.
+const (
+	MagicSHA256 = "$5$"
+	MagicSHA512 = "$6$"
+)
+
+// Bounds and default of the number of rounds.
+const (
+	RoundsMin     = 1000
+	RoundsMax     = 999999999
+	RoundsDefault = 5000
+)
+
+const (
+	saltMax      = 16
+	roundsPrefix = "rounds="
+	itoa64       = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// byte permutations of the final digests, by groups of 3 bytes
+var (
+	permSHA256 = [][3]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+	}
+	permSHA512 = [][3]int{
+		{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+		{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+		{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+		{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+		{62, 20, 41},
+	}
+)
+
+// Crypt returns the encoded hash of the password with the given salt, number
+// of rounds and magic, e.g. "$6$rounds=10000$salt$hash". Only the first 16
+// bytes of the salt are used. A zero rounds uses the default number of rounds
+// and omits it from the output, and other values are clamped to the allowed
+// range. It panics if the magic isn't MagicSHA256 or MagicSHA512.
+func Crypt(password, salt []byte, rounds int, magic string) []byte {
+	var newHash func() hash.Hash
+	switch magic {
+	case MagicSHA256:
+		newHash = sha256.New
+	case MagicSHA512:
+		newHash = sha512.New
+	default:
+		panic("shacrypt: unknown magic " + magic)
+	}
+
+	if len(salt) > saltMax {
+		salt = salt[:saltMax]
+	}
+	explicitRounds := rounds != 0
+	switch {
+	case !explicitRounds:
+		rounds = RoundsDefault
+	case rounds < RoundsMin:
+		rounds = RoundsMin
+	case rounds > RoundsMax:
+		rounds = RoundsMax
+	}
+
+	b := newHash()
+	b.Write(password)
+	b.Write(salt)
+	b.Write(password)
+	sumB := b.Sum(nil)
+	size := len(sumB)
+
+	a := newHash()
+	a.Write(password)
+	a.Write(salt)
+	writeRepeated(a, sumB, len(password))
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			a.Write(sumB)
+		} else {
+			a.Write(password)
+		}
+	}
+	sumA := a.Sum(nil)
+
+	dp := newHash()
+	for i := 0; i < len(password); i++ {
+		dp.Write(password)
+	}
+	p := repeat(dp.Sum(nil), len(password))
+
+	ds := newHash()
+	for i := 0; i < 16+int(sumA[0]); i++ {
+		ds.Write(salt)
+	}
+	s := repeat(ds.Sum(nil), len(salt))
+
+	c := sumA
+	for i := 0; i < rounds; i++ {
+		h := newHash()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(s)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(c)
+		} else {
+			h.Write(p)
+		}
+		c = h.Sum(c[:0])
+	}
+
+	out := make([]byte, 0, 128)
+	out = append(out, magic...)
+	if explicitRounds {
+		out = append(out, roundsPrefix...)
+		out = strconv.AppendInt(out, int64(rounds), 10)
+		out = append(out, '$')
+	}
+	out = append(out, salt...)
+	out = append(out, '$')
+
+	perm := permSHA512
+	if size == sha256.Size {
+		perm = permSHA256
+	}
+	for _, g := range perm {
+		out = appendBase64(out, uint(c[g[0]])<<16|uint(c[g[1]])<<8|uint(c[g[2]]), 4)
+	}
+	if size == sha256.Size {
+		return appendBase64(out, uint(c[31])<<8|uint(c[30]), 3)
+	}
+	return appendBase64(out, uint(c[63]), 2)
+}
+
+// writeRepeated writes n bytes of sum repeated to h.
+func writeRepeated(h hash.Hash, sum []byte, n int) {
+	for ; n > len(sum); n -= len(sum) {
+		h.Write(sum)
+	}
+	h.Write(sum[:n])
+}
+
+// repeat returns n bytes of sum repeated.
+func repeat(sum []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for ; n > len(sum); n -= len(sum) {
+		out = append(out, sum...)
+	}
+	return append(out, sum[:n]...)
+}
+
+// appendBase64 appends the n least significant 6-bit groups of v, least
+// significant first, in the crypt(3) base64 alphabet.
+func appendBase64(dst []byte, v uint, n int) []byte {
+	for ; n > 0; n-- {
+		dst = append(dst, itoa64[v&0x3f])
+		v >>= 6
+	}
+	return dst
+}
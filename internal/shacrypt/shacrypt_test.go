@@ -0,0 +1,69 @@
+package shacrypt
+
+import (
+	"testing"
+)
+
+func TestCrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		salt     string
+		rounds   int
+		magic    string
+		want     string
+	}{
+		{
+			name:     "sha512 default rounds",
+			password: "Hello world!",
+			salt:     "saltstring",
+			magic:    MagicSHA512,
+			want:     "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1",
+		},
+		{
+			name:     "sha512 explicit rounds and long salt",
+			password: "Hello world!",
+			salt:     "saltstringsaltstring",
+			rounds:   10000,
+			magic:    MagicSHA512,
+			want:     "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.",
+		},
+		{
+			name:     "sha512 rounds too low",
+			password: "the minimum number is still observed",
+			salt:     "roundstoolow",
+			rounds:   10,
+			magic:    MagicSHA512,
+			want:     "$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX.",
+		},
+		{
+			name:     "sha512 empty password",
+			password: "",
+			salt:     "empty",
+			magic:    MagicSHA512,
+			want:     "$6$empty$MWslJBrCvUsbDfvDkNQwBNtJFEGiZ5CHosSR8Ol/yMiSd9JINPGkSH4OfOOVEIp87YcT49Wr.Qp4a8bJCR6y2/",
+		},
+		{
+			name:     "sha256 default rounds",
+			password: "Hello world!",
+			salt:     "saltstring",
+			magic:    MagicSHA256,
+			want:     "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5",
+		},
+		{
+			name:     "sha256 explicit rounds",
+			password: "Hello world!",
+			salt:     "saltstringsaltstring",
+			rounds:   10000,
+			magic:    MagicSHA256,
+			want:     "$5$rounds=10000$saltstringsaltst$3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Crypt([]byte(tt.password), []byte(tt.salt), tt.rounds, tt.magic); string(got) != tt.want {
+				t.Errorf("Crypt() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
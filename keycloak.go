@@ -0,0 +1,110 @@
+package argon2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+)
+
+// keycloakSecretData is the secret data of a Keycloak password credential.
+type keycloakSecretData struct {
+	Value string `json:"value"`
+	Salt  string `json:"salt"`
+}
+
+// keycloakCredentialData is the credential data of a Keycloak password
+// credential. The argon2 parameters, except the iterations, are stored as
+// lists of strings in the additional parameters.
+type keycloakCredentialData struct {
+	HashIterations       uint32              `json:"hashIterations"`
+	Algorithm            string              `json:"algorithm"`
+	AdditionalParameters map[string][]string `json:"additionalParameters"`
+}
+
+// keycloakVariants maps the Keycloak "type" parameter to the variants.
+var keycloakVariants = map[string]Variant{
+	"id": Argon2id,
+	"d":  Argon2d,
+	"i":  Argon2i,
+}
+
+// keycloakVersions maps the Keycloak "version" parameter to the argon2
+// versions, as stored in Params.
+var keycloakVersions = map[string]uint32{
+	"1.3": 0,
+	"1.0": legacyVersion,
+}
+
+// ParseKeycloakCredential decodes the "secretData" and "credentialData"
+// JSON documents of an argon2 password credential, as exported from Keycloak,
+// into a Hash record. It returns ErrUnknownAlgorithm if the credential
+// isn't an argon2 one, ErrInvalidHash if it is malformed or its parameters
+// are outside of the spec, and the errors of SetVerifyLimits if they exceed
+// the verification limits.
+func ParseKeycloakCredential(secretData, credentialData []byte) (*Hash, error) {
+	var secret keycloakSecretData
+	if err := json.Unmarshal(secretData, &secret); err != nil {
+		return nil, ErrInvalidHash
+	}
+
+	var cred keycloakCredentialData
+	if err := json.Unmarshal(credentialData, &cred); err != nil {
+		return nil, ErrInvalidHash
+	}
+	if cred.Algorithm != "argon2" {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	param := func(name string) string {
+		if v := cred.AdditionalParameters[name]; len(v) == 1 {
+			return v[0]
+		}
+		return ""
+	}
+
+	var p Params
+	var ok bool
+	if p.Variant, ok = keycloakVariants[param("type")]; !ok {
+		return nil, ErrUnknownAlgorithm
+	}
+	if p.Version, ok = keycloakVersions[param("version")]; !ok {
+		return nil, ErrIncompatibleVersion
+	}
+
+	memory, err := strconv.ParseUint(param("memory"), 10, 32)
+	if err != nil {
+		return nil, ErrInvalidHash
+	}
+	p.Memory = uint32(memory)
+
+	parallelism, err := strconv.ParseUint(param("parallelism"), 10, 8)
+	if err != nil {
+		return nil, ErrInvalidHash
+	}
+	p.Parallelism = uint8(parallelism)
+	p.Iterations = cred.HashIterations
+
+	salt, err := base64.StdEncoding.DecodeString(secret.Salt)
+	if err != nil {
+		return nil, ErrInvalidHash
+	}
+	key, err := base64.StdEncoding.DecodeString(secret.Value)
+	if err != nil {
+		return nil, ErrInvalidHash
+	}
+	if hashLength := param("hashLength"); hashLength != "" && hashLength != strconv.Itoa(len(key)) {
+		return nil, ErrInvalidHash
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+
+	// Parameters outside of the spec would have the key derivation panic
+	if err := joinErrors(p.checkSpec()); err != nil {
+		return nil, &HashError{Field: "params", Err: ErrInvalidHash, Cause: err}
+	}
+	if err := p.checkLimits(); err != nil {
+		return nil, err
+	}
+
+	return &Hash{params: p, salt: salt, key: key}, nil
+}
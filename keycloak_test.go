@@ -0,0 +1,113 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseKeycloakCredential(t *testing.T) {
+	type args struct {
+		secretData     string
+		credentialData string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		password []byte
+		wantErr  bool
+	}{
+		{
+			name: "default argon2 credential",
+			args: args{
+				secretData:     `{"value":"oNAcaMje0b0XYDOdrJ1EJubIJSAW+3VZEaU+u9wi4WA=","salt":"a2V5Y2xvYWstc2FsdC0xNg==","additionalParameters":{}}`,
+				credentialData: `{"hashIterations":5,"algorithm":"argon2","additionalParameters":{"hashLength":["32"],"memory":["7168"],"type":["id"],"version":["1.3"],"parallelism":["1"]}}`,
+			},
+			password: []byte("keycloak-secret"),
+			wantErr:  false,
+		},
+		{
+			name: "pbkdf2 credential",
+			args: args{
+				secretData:     `{"value":"oNAcaMje0b0XYDOdrJ1EJubIJSAW+3VZEaU+u9wi4WA=","salt":"a2V5Y2xvYWstc2FsdC0xNg==","additionalParameters":{}}`,
+				credentialData: `{"hashIterations":27500,"algorithm":"pbkdf2-sha256","additionalParameters":{}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown type",
+			args: args{
+				secretData:     `{"value":"oNAcaMje0b0XYDOdrJ1EJubIJSAW+3VZEaU+u9wi4WA=","salt":"a2V5Y2xvYWstc2FsdC0xNg==","additionalParameters":{}}`,
+				credentialData: `{"hashIterations":5,"algorithm":"argon2","additionalParameters":{"hashLength":["32"],"memory":["7168"],"type":["x"],"version":["1.3"],"parallelism":["1"]}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "mismatched hash length",
+			args: args{
+				secretData:     `{"value":"oNAcaMje0b0XYDOdrJ1EJubIJSAW+3VZEaU+u9wi4WA=","salt":"a2V5Y2xvYWstc2FsdC0xNg==","additionalParameters":{}}`,
+				credentialData: `{"hashIterations":5,"algorithm":"argon2","additionalParameters":{"hashLength":["16"],"memory":["7168"],"type":["id"],"version":["1.3"],"parallelism":["1"]}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing memory",
+			args: args{
+				secretData:     `{"value":"oNAcaMje0b0XYDOdrJ1EJubIJSAW+3VZEaU+u9wi4WA=","salt":"a2V5Y2xvYWstc2FsdC0xNg==","additionalParameters":{}}`,
+				credentialData: `{"hashIterations":5,"algorithm":"argon2","additionalParameters":{"hashLength":["32"],"type":["id"],"version":["1.3"],"parallelism":["1"]}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "out of spec memory",
+			args: args{
+				secretData:     `{"value":"oNAcaMje0b0XYDOdrJ1EJubIJSAW+3VZEaU+u9wi4WA=","salt":"a2V5Y2xvYWstc2FsdC0xNg==","additionalParameters":{}}`,
+				credentialData: `{"hashIterations":5,"algorithm":"argon2","additionalParameters":{"hashLength":["32"],"memory":["4294967295"],"type":["id"],"version":["1.3"],"parallelism":["1"]}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "too short salt",
+			args: args{
+				secretData:     `{"value":"oNAcaMje0b0XYDOdrJ1EJubIJSAW+3VZEaU+u9wi4WA=","salt":"c2FsdA==","additionalParameters":{}}`,
+				credentialData: `{"hashIterations":5,"algorithm":"argon2","additionalParameters":{"hashLength":["32"],"memory":["7168"],"type":["id"],"version":["1.3"],"parallelism":["1"]}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid secret data",
+			args: args{
+				secretData:     `{"value":"not base64!","salt":"a2V5Y2xvYWstc2FsdC0xNg==","additionalParameters":{}}`,
+				credentialData: `{"hashIterations":5,"algorithm":"argon2","additionalParameters":{"hashLength":["32"],"memory":["7168"],"type":["id"],"version":["1.3"],"parallelism":["1"]}}`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeycloakCredential([]byte(tt.args.secretData), []byte(tt.args.credentialData))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseKeycloakCredential() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if err := CompareHashAndPassword(got.Encode(), tt.password); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseKeycloakCredential_Limits(t *testing.T) {
+	SetVerifyLimits(&VerifyLimits{MaxMemory: 4 * 1024})
+	defer SetVerifyLimits(nil)
+
+	_, err := ParseKeycloakCredential(
+		[]byte(`{"value":"oNAcaMje0b0XYDOdrJ1EJubIJSAW+3VZEaU+u9wi4WA=","salt":"a2V5Y2xvYWstc2FsdC0xNg==","additionalParameters":{}}`),
+		[]byte(`{"hashIterations":5,"algorithm":"argon2","additionalParameters":{"hashLength":["32"],"memory":["7168"],"type":["id"],"version":["1.3"],"parallelism":["1"]}}`),
+	)
+	if !errors.Is(err, ErrHashTooExpensive) {
+		t.Errorf("ParseKeycloakCredential() error = %v, want %v", err, ErrHashTooExpensive)
+	}
+}
@@ -0,0 +1,65 @@
+package kmspepper
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// azureAPIVersion is the version of the Key Vault REST API.
+const azureAPIVersion = "7.4"
+
+// Azure unwraps data keys with a key of Azure Key Vault, through its REST
+// API, so no cloud SDK is needed. Its Decrypt method is a DecryptFunc:
+//
+//	cred, err := azidentity.NewDefaultAzureCredential(nil)
+//	...
+//	vault := &kmspepper.Azure{
+//		VaultURL: "https://my-vault.vault.azure.net",
+//		KeyName:  "pepper",
+//		Token: func(ctx context.Context) (string, error) {
+//			tok, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{"https://vault.azure.net/.default"}})
+//			return tok.Token, err
+//		},
+//	}
+//	provider, err := kmspepper.New(ctx, vault.Decrypt, "2024", dataKeys)
+//
+// The data keys are the results of the wrapkey operation of the key for
+// random peppers.
+type Azure struct {
+	VaultURL   string       // The URL of the vault, e.g. https://my-vault.vault.azure.net
+	KeyName    string       // The name of the key
+	KeyVersion string       // The version of the key. Empty means the latest one
+	Algorithm  string       // The algorithm the data keys were wrapped with. Empty means RSA-OAEP-256
+	Token      TokenFunc    // Returns the OAuth2 access tokens of the requests
+	Client     *http.Client // The HTTP client of the requests. Nil means http.DefaultClient
+}
+
+// Decrypt unwraps the data key with the unwrapkey operation of the key. It
+// implements DecryptFunc.
+func (a *Azure) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	alg := a.Algorithm
+	if alg == "" {
+		alg = "RSA-OAEP-256"
+	}
+
+	in := struct {
+		Alg   string `json:"alg"`
+		Value string `json:"value"`
+	}{alg, base64.RawURLEncoding.EncodeToString(ciphertext)}
+	var out struct {
+		Value string `json:"value"`
+	}
+	path := "/keys/" + url.PathEscape(a.KeyName)
+	if a.KeyVersion != "" {
+		path += "/" + url.PathEscape(a.KeyVersion)
+	}
+	u := strings.TrimSuffix(a.VaultURL, "/") + path + "/unwrapkey?api-version=" + azureAPIVersion
+	if err := post(ctx, a.Client, u, a.Token, in, &out); err != nil {
+		return nil, err
+	}
+
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(out.Value, "="))
+}
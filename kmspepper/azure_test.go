@@ -0,0 +1,92 @@
+package kmspepper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// azureServer is a stand-in for Key Vault, whose "key wrapping" reverses
+// the bytes of the data key.
+func azureServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if (path != "/keys/pepper/unwrapkey" && path != "/keys/pepper/v1/unwrapkey") ||
+			r.URL.Query().Get("api-version") != azureAPIVersion ||
+			r.Header.Get("Authorization") != "Bearer azure-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"code": "Unauthorized", "message": "AKV10000: Request is missing a Bearer or PoP token."}}`))
+			return
+		}
+
+		var in struct {
+			Alg   string `json:"alg"`
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		ciphertext, _ := base64.RawURLEncoding.DecodeString(in.Value)
+		plaintext, err := decryptReversed(r.Context(), ciphertext)
+		if err != nil || in.Alg != "RSA-OAEP-256" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": "BadParameter", "message": "Unwrap failed"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"kid": "https://vault/keys/pepper/v1", "value": base64.RawURLEncoding.EncodeToString(plaintext)})
+	}))
+}
+
+func TestAzure_Decrypt(t *testing.T) {
+	srv := azureServer(t)
+	defer srv.Close()
+
+	token := func(context.Context) (string, error) { return "azure-token", nil }
+	tests := []struct {
+		name       string
+		vault      *Azure
+		ciphertext string
+		want       string
+		wantErr    bool
+	}{
+		{name: "latest version", vault: &Azure{VaultURL: srv.URL, KeyName: "pepper", Token: token}, ciphertext: "4202-reppep", want: "pepper-2024"},
+		{name: "pinned version", vault: &Azure{VaultURL: srv.URL + "/", KeyName: "pepper", KeyVersion: "v1", Token: token}, ciphertext: "4202-reppep", want: "pepper-2024"},
+		{name: "corrupt data key", vault: &Azure{VaultURL: srv.URL, KeyName: "pepper", Token: token}, ciphertext: "corrupt", wantErr: true},
+		{name: "other algorithm", vault: &Azure{VaultURL: srv.URL, KeyName: "pepper", Algorithm: "RSA1_5", Token: token}, ciphertext: "4202-reppep", wantErr: true},
+		{name: "no token", vault: &Azure{VaultURL: srv.URL, KeyName: "pepper"}, ciphertext: "4202-reppep", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.vault.Decrypt(context.Background(), []byte(tt.ciphertext))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Decrypt() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAzure_Provider(t *testing.T) {
+	srv := azureServer(t)
+	defer srv.Close()
+
+	vault := &Azure{VaultURL: srv.URL, KeyName: "pepper", Token: func(context.Context) (string, error) { return "azure-token", nil }}
+	p, err := New(context.Background(), vault.Decrypt, "2024", map[string][]byte{
+		"2023": []byte("3202-reppep"),
+		"2024": []byte("4202-reppep"),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if keyID, secret := p.Current(); keyID != "2024" || string(secret) != "pepper-2024" {
+		t.Errorf("Current() got = %s, %s, want 2024, pepper-2024", keyID, secret)
+	}
+	if secret, err := p.Get("2023"); err != nil || string(secret) != "pepper-2023" {
+		t.Errorf("Get() got = %s, error = %v, want pepper-2023", secret, err)
+	}
+}
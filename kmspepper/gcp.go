@@ -0,0 +1,59 @@
+package kmspepper
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// gcpEndpoint is the endpoint of the Cloud KMS REST API.
+const gcpEndpoint = "https://cloudkms.googleapis.com"
+
+// GCP decrypts data keys with a symmetric key of GCP Cloud KMS, through its
+// REST API, so no cloud SDK is needed. Its Decrypt method is a DecryptFunc:
+//
+//	ts, err := google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/cloudkms")
+//	...
+//	kms := &kmspepper.GCP{
+//		KeyName: "projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/pepper",
+//		Token: func(context.Context) (string, error) {
+//			tok, err := ts.Token()
+//			if err != nil {
+//				return "", err
+//			}
+//			return tok.AccessToken, nil
+//		},
+//	}
+//	provider, err := kmspepper.New(ctx, kms.Decrypt, "2024", dataKeys)
+//
+// The data keys are the ciphertexts returned by the encrypt method of the
+// key for random peppers.
+type GCP struct {
+	KeyName  string       // The resource name of the key, projects/*/locations/*/keyRings/*/cryptoKeys/*
+	Token    TokenFunc    // Returns the OAuth2 access tokens of the requests
+	Client   *http.Client // The HTTP client of the requests. Nil means http.DefaultClient
+	Endpoint string       // The endpoint of the API. Empty means https://cloudkms.googleapis.com
+}
+
+// Decrypt decrypts the data key with the decrypt method of the key. It
+// implements DecryptFunc.
+func (g *GCP) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = gcpEndpoint
+	}
+
+	in := struct {
+		Ciphertext string `json:"ciphertext"`
+	}{base64.StdEncoding.EncodeToString(ciphertext)}
+	var out struct {
+		Plaintext string `json:"plaintext"`
+	}
+	url := strings.TrimSuffix(endpoint, "/") + "/v1/" + g.KeyName + ":decrypt"
+	if err := post(ctx, g.Client, url, g.Token, in, &out); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(out.Plaintext)
+}
@@ -0,0 +1,89 @@
+package kmspepper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const gcpKeyName = "projects/p/locations/global/keyRings/r/cryptoKeys/pepper"
+
+// gcpServer is a stand-in for Cloud KMS, whose "encryption" reverses the
+// bytes of the data key.
+func gcpServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/"+gcpKeyName+":decrypt" || r.Header.Get("Authorization") != "Bearer gcp-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"code": 403, "message": "permission denied", "status": "PERMISSION_DENIED"}}`))
+			return
+		}
+
+		var in struct {
+			Ciphertext string `json:"ciphertext"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		ciphertext, _ := base64.StdEncoding.DecodeString(in.Ciphertext)
+		plaintext, err := decryptReversed(r.Context(), ciphertext)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": 400, "message": "Decryption failed"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)})
+	}))
+}
+
+func TestGCP_Decrypt(t *testing.T) {
+	srv := gcpServer(t)
+	defer srv.Close()
+
+	token := func(context.Context) (string, error) { return "gcp-token", nil }
+	tests := []struct {
+		name       string
+		kms        *GCP
+		ciphertext string
+		want       string
+		wantErr    bool
+	}{
+		{name: "valid", kms: &GCP{KeyName: gcpKeyName, Token: token, Endpoint: srv.URL}, ciphertext: "4202-reppep", want: "pepper-2024"},
+		{name: "corrupt data key", kms: &GCP{KeyName: gcpKeyName, Token: token, Endpoint: srv.URL}, ciphertext: "corrupt", wantErr: true},
+		{name: "unknown key", kms: &GCP{KeyName: gcpKeyName + "-2", Token: token, Endpoint: srv.URL}, ciphertext: "4202-reppep", wantErr: true},
+		{name: "no token", kms: &GCP{KeyName: gcpKeyName, Endpoint: srv.URL}, ciphertext: "4202-reppep", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.kms.Decrypt(context.Background(), []byte(tt.ciphertext))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Decrypt() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGCP_Provider(t *testing.T) {
+	srv := gcpServer(t)
+	defer srv.Close()
+
+	kms := &GCP{KeyName: gcpKeyName, Endpoint: srv.URL, Token: func(context.Context) (string, error) { return "gcp-token", nil }}
+	p, err := New(context.Background(), kms.Decrypt, "2024", map[string][]byte{
+		"2023": []byte("3202-reppep"),
+		"2024": []byte("4202-reppep"),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if keyID, secret := p.Current(); keyID != "2024" || string(secret) != "pepper-2024" {
+		t.Errorf("Current() got = %s, %s, want 2024, pepper-2024", keyID, secret)
+	}
+	if secret, err := p.Get("2023"); err != nil || string(secret) != "pepper-2023" {
+		t.Errorf("Get() got = %s, error = %v, want pepper-2023", secret, err)
+	}
+}
@@ -0,0 +1,149 @@
+// Package kmspepper provides an argon2.SecretProvider whose peppers are data
+// keys encrypted by a key management service, such as AWS KMS, GCP Cloud KMS
+// or Azure Key Vault, so they never live in plaintext configuration.
+//
+// The encrypted data keys are decrypted once, when the Provider is created,
+// and the plaintext peppers are cached in memory. Peppers are rotated by
+// adding a data key and making it the current one, keeping the previous ones
+// to verify the existing hashes until they are rehashed.
+//
+// The package doesn't depend on any cloud SDK: the decryption is a function,
+// e.g. for AWS KMS with github.com/aws/aws-sdk-go-v2/service/kms:
+//
+//	client := kms.NewFromConfig(cfg)
+//	decrypt := func(ctx context.Context, ciphertext []byte) ([]byte, error) {
+//		out, err := client.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: ciphertext})
+//		if err != nil {
+//			return nil, err
+//		}
+//		return out.Plaintext, nil
+//	}
+//	provider, err := kmspepper.New(ctx, decrypt, "2024", map[string][]byte{
+//		"2023": encryptedKey2023,
+//		"2024": encryptedKey2024,
+//	})
+//	...
+//	hasher, err := argon2.NewHasher(argon2.WithSecretProvider(provider))
+//
+// The data keys can be generated with the GenerateDataKey operation of AWS
+// KMS, storing its CiphertextBlob in the configuration.
+//
+// For GCP Cloud KMS and Azure Key Vault, GCP and Azure provide the
+// decryption through the REST APIs of the services.
+package kmspepper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// ErrEmptyKey is returned when a data key decrypts to an empty pepper.
+var ErrEmptyKey = errors.New("kmspepper: the data key is empty")
+
+// DecryptFunc decrypts an encrypted data key with a key management service.
+type DecryptFunc func(ctx context.Context, ciphertext []byte) ([]byte, error)
+
+// TokenFunc returns an OAuth2 access token to authenticate the requests to
+// a key management service.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// Provider is an argon2.SecretProvider of peppers decrypted by a key
+// management service. It is safe for concurrent use.
+type Provider struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+// New decrypts the encrypted data keys, by key ID, and returns a Provider
+// of the decrypted peppers, using the one of currentID for new hashes.
+// It returns an error if a data key can't be decrypted, or if there is no
+// data key for currentID.
+func New(ctx context.Context, decrypt DecryptFunc, currentID string, dataKeys map[string][]byte) (*Provider, error) {
+	if _, ok := dataKeys[currentID]; !ok {
+		return nil, fmt.Errorf("kmspepper: %s: %w", currentID, argon2.ErrUnknownKeyID)
+	}
+
+	p := &Provider{currentID: currentID, keys: make(map[string][]byte, len(dataKeys))}
+	for keyID, ciphertext := range dataKeys {
+		secret, err := decrypt(ctx, ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("kmspepper: %s: %w", keyID, err)
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("kmspepper: %s: %w", keyID, ErrEmptyKey)
+		}
+		p.keys[keyID] = secret
+	}
+
+	return p, nil
+}
+
+// Get implements the argon2.SecretProvider interface.
+func (p *Provider) Get(keyID string) ([]byte, error) {
+	secret, ok := p.keys[keyID]
+	if !ok {
+		return nil, argon2.ErrUnknownKeyID
+	}
+	return secret, nil
+}
+
+// Current implements the argon2.SecretProvider interface.
+func (p *Provider) Current() (keyID string, secret []byte) {
+	return p.currentID, p.keys[p.currentID]
+}
+
+// post sends the JSON of in to the REST API of a key management service,
+// authenticated with the token, and decodes the response into out.
+func post(ctx context.Context, client *http.Client, url string, token TokenFunc, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != nil {
+		tok, err := token(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The services report errors as {"error": {"message": ...}}
+	body, err = io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &e) == nil && e.Error.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, e.Error.Message)
+		}
+		return errors.New(resp.Status)
+	}
+
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,107 @@
+package kmspepper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andskur/argon2-hashing"
+)
+
+// decryptReversed is a stand-in for a key management service, whose
+// "encryption" reverses the bytes of the data key.
+func decryptReversed(_ context.Context, ciphertext []byte) ([]byte, error) {
+	if bytes.Equal(ciphertext, []byte("corrupt")) {
+		return nil, errors.New("invalid ciphertext")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	for i, b := range ciphertext {
+		plaintext[len(ciphertext)-1-i] = b
+	}
+	return plaintext, nil
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		currentID string
+		dataKeys  map[string][]byte
+		wantErr   error
+	}{
+		{name: "valid", currentID: "2024", dataKeys: map[string][]byte{"2023": []byte("3202-reppep"), "2024": []byte("4202-reppep")}},
+		{name: "missing current key", currentID: "2025", dataKeys: map[string][]byte{"2024": []byte("4202-reppep")}, wantErr: argon2.ErrUnknownKeyID},
+		{name: "empty key", currentID: "2024", dataKeys: map[string][]byte{"2024": {}}, wantErr: ErrEmptyKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(context.Background(), decryptReversed, tt.currentID, tt.dataKeys); !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := New(context.Background(), decryptReversed, "2024", map[string][]byte{"2024": []byte("corrupt")}); err == nil {
+		t.Errorf("New() error = nil for a corrupt data key")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	p, err := New(context.Background(), decryptReversed, "2024", map[string][]byte{
+		"2023": []byte("3202-reppep"),
+		"2024": []byte("4202-reppep"),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if keyID, secret := p.Current(); keyID != "2024" || string(secret) != "pepper-2024" {
+		t.Errorf("Current() got = %s, %s, want 2024, pepper-2024", keyID, secret)
+	}
+	if secret, err := p.Get("2023"); err != nil || string(secret) != "pepper-2023" {
+		t.Errorf("Get() got = %s, error = %v, want pepper-2023", secret, err)
+	}
+	if _, err := p.Get("2022"); err != argon2.ErrUnknownKeyID {
+		t.Errorf("Get() error = %v, want %v", err, argon2.ErrUnknownKeyID)
+	}
+
+	h, err := argon2.NewHasher(argon2.WithMemory(8*1024), argon2.WithIterations(1), argon2.WithSecretProvider(p))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	hash, err := h.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if err := h.Verify(hash, []byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestProvider_Rotation(t *testing.T) {
+	ctx := context.Background()
+	dataKeys := map[string][]byte{"2023": []byte("3202-reppep")}
+
+	old, err := New(ctx, decryptReversed, "2023", dataKeys)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	oldHasher, _ := argon2.NewHasher(argon2.WithMemory(8*1024), argon2.WithIterations(1), argon2.WithSecretProvider(old))
+	hash, err := oldHasher.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	// a new data key becomes the current one, the previous one is kept
+	dataKeys["2024"] = []byte("4202-reppep")
+	rotated, err := New(ctx, decryptReversed, "2024", dataKeys)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h, _ := argon2.NewHasher(argon2.WithMemory(8*1024), argon2.WithIterations(1), argon2.WithSecretProvider(rotated))
+
+	newHash, err := h.CompareAndUpdate(hash, []byte("password"))
+	if err != nil || !bytes.HasPrefix(newHash, []byte("{PEPPER:2024}")) {
+		t.Fatalf("CompareAndUpdate() got = %s, error = %v, want a hash with the 2024 pepper", newHash, err)
+	}
+}
@@ -0,0 +1,50 @@
+package argon2
+
+import (
+	"strings"
+)
+
+// ldapScheme is the userPassword scheme of OpenLDAP's argon2 module.
+const ldapScheme = "ARGON2"
+
+// WrapLDAP returns the encoded hash as an LDAP userPassword value,
+// e.g. "{ARGON2}$argon2id$v=19$...", as understood by OpenLDAP's argon2
+// password module. The hash is re-encoded in the PHC format if needed.
+// Hashes with a key ID, associated data, a pre-hash function or a
+// normalization, which the module doesn't support, are rejected with
+// ErrUnsupportedParams.
+func WrapLDAP(encodedHash []byte) ([]byte, error) {
+	p, salt, key, err := decodeHash(encodedHash)
+	if err != nil {
+		return nil, err
+	}
+	if p.extended() {
+		return nil, ErrUnsupportedParams
+	}
+	p.Format, p.URLSafe = FormatPHC, false
+
+	dst := make([]byte, 0, len(ldapScheme)+2+len(encodedHash))
+	dst = append(dst, '{')
+	dst = append(dst, ldapScheme...)
+	dst = append(dst, '}')
+	return appendEncoded(dst, p.Format, p, salt, key), nil
+}
+
+// UnwrapLDAP returns the encoded hash of an LDAP userPassword value,
+// e.g. "{ARGON2}$argon2id$v=19$...", without the scheme. It returns
+// ErrUnknownAlgorithm if the value uses another scheme, e.g. "{SSHA}".
+func UnwrapLDAP(userPassword []byte) ([]byte, error) {
+	scheme, encodedHash, err := splitScheme(userPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(string(scheme), ldapScheme) {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	if _, _, _, err := decodeHashInto(nil, encodedHash, ParseStrict); err != nil {
+		return nil, err
+	}
+
+	return encodedHash, nil
+}
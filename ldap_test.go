@@ -0,0 +1,102 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWrapLDAP(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    []byte
+		want    string
+		wantErr error
+	}{
+		{
+			name: "phc argon2id",
+			hash: []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			want: "{ARGON2}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w",
+		},
+		{
+			name: "phc argon2d",
+			hash: []byte("$argon2d$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$lV5dWxY6G2C7o1/DbQSWR0+6T2tZrVNihmbwf7L5Pq8"),
+			want: "{ARGON2}$argon2d$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$lV5dWxY6G2C7o1/DbQSWR0+6T2tZrVNihmbwf7L5Pq8",
+		},
+		{
+			name: "legacy format",
+			hash: []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU"),
+			want: "{ARGON2}$argon2id$v=19$m=65536,t=3,p=2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		},
+		{
+			name:    "pre-hash",
+			hash:    []byte("$argon2id$v=19$m=65536,t=2,p=1,ph=sha512$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+			wantErr: ErrUnsupportedParams,
+		},
+		{
+			name:    "invalid hash",
+			hash:    []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg"),
+			wantErr: ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WrapLDAP(tt.hash)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WrapLDAP() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("WrapLDAP() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnwrapLDAP(t *testing.T) {
+	tests := []struct {
+		name         string
+		userPassword []byte
+		want         string
+		wantErr      error
+	}{
+		{
+			name:         "argon2 scheme",
+			userPassword: []byte("{ARGON2}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			want:         "$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w",
+		},
+		{
+			name:         "lower case scheme",
+			userPassword: []byte("{argon2}$argon2i$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$0tUVteCsZu/B+qo6QVg4mcJh7OvToMMQdYWcfXWkmaE"),
+			want:         "$argon2i$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$0tUVteCsZu/B+qo6QVg4mcJh7OvToMMQdYWcfXWkmaE",
+		},
+		{
+			name:         "other scheme",
+			userPassword: []byte("{SSHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="),
+			wantErr:      ErrUnknownAlgorithm,
+		},
+		{
+			name:         "missing scheme",
+			userPassword: []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			wantErr:      ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnwrapLDAP(tt.userPassword)
+			if err != tt.wantErr {
+				t.Errorf("UnwrapLDAP() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("UnwrapLDAP() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareLDAPPassword(t *testing.T) {
+	userPassword := []byte("{ARGON2}$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w")
+	if err := CompareHashAndPassword(userPassword, []byte("dotnet-password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package argon2
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrHashTooExpensive is returned when the parameters of a hash to verify
+// exceed the limits set with SetVerifyLimits.
+var ErrHashTooExpensive = errors.New("argon2: the hash exceeds the verification limits")
+
+// VerifyLimits caps the costs of the hashes that are verified. The costs
+// of verifying a hash are the ones it claims, so a tampered hash, e.g. with
+// m=4194304, would have the server allocate 4 GiB per attempt. A zero
+// field means no limit.
+type VerifyLimits struct {
+	MaxMemory      uint32 // The maximum amount of memory (kibibytes)
+	MaxIterations  uint32 // The maximum number of iterations
+	MaxParallelism uint8  // The maximum number of lanes
+}
+
+var verifyLimits atomic.Pointer[VerifyLimits]
+
+// SetVerifyLimits installs the limits every function verifying hashes
+// enforces, safely for concurrent use. Hashes exceeding them are rejected
+// with a HashError wrapping ErrHashTooExpensive, naming the field, before
+// any key is derived. Passing nil removes them. Generating hashes isn't
+// affected, see SetValidator instead.
+func SetVerifyLimits(l *VerifyLimits) {
+	if l == nil {
+		verifyLimits.Store(nil)
+		return
+	}
+	limits := *l
+	verifyLimits.Store(&limits)
+}
+
+// checkLimits returns an error if the decoded parameters p of a hash to
+// verify exceed the limits set with SetVerifyLimits. Costs the key
+// derivation can't run with, e.g. no iterations, are always rejected.
+func (p *Params) checkLimits() error {
+	switch {
+	case p.Iterations < 1:
+		return &HashError{Field: "iterations", Err: ErrInvalidHash, Cause: ErrIterationsTooSmall}
+	case p.Parallelism < 1:
+		return &HashError{Field: "parallelism", Err: ErrInvalidHash, Cause: ErrParallelismTooSmall}
+	case p.Memory < 8*uint32(p.Parallelism):
+		return &HashError{Field: "memory", Err: ErrInvalidHash, Cause: ErrMemoryTooSmall}
+	}
+
+	l := verifyLimits.Load()
+	if l == nil {
+		return nil
+	}
+
+	switch {
+	case l.MaxMemory > 0 && p.Memory > l.MaxMemory:
+		return limitError("memory", uint64(p.Memory), uint64(l.MaxMemory))
+	case l.MaxIterations > 0 && p.Iterations > l.MaxIterations:
+		return limitError("iterations", uint64(p.Iterations), uint64(l.MaxIterations))
+	case l.MaxParallelism > 0 && p.Parallelism > l.MaxParallelism:
+		return limitError("parallelism", uint64(p.Parallelism), uint64(l.MaxParallelism))
+	}
+	return nil
+}
+
+// The ceilings of the costs of the legacy hashes VerifyAndMigrate and
+// VerifyAnyScheme verify, which are taken from the stored hashes, well above
+// the costs their schemes are used with, so a planted hash can't have every
+// login spend minutes or gigabytes deriving its key.
+const (
+	maxLegacyBcryptCost       = 16       // 2^16 rounds, against a default of 10
+	maxLegacyCryptRounds      = 10000000 // SHA-crypt, against a default of 5000
+	maxLegacyPBKDF2Iterations = 10000000 // against 600000 recommended for HMAC-SHA256
+	maxLegacyScryptMemory     = 1 << 30  // 128*N*r bytes, against 16 MiB by default
+	maxLegacyScryptWork       = 4 << 30  // 128*N*r*p bytes processed
+)
+
+// checkScryptLimits returns an error if the cost parameters of a scrypt hash
+// to verify exceed the ceilings of the legacy hashes, or the memory limit set
+// with SetVerifyLimits.
+func checkScryptLimits(n, r, p int) error {
+	memory := 128 * uint64(n) * uint64(r)
+	if memory > maxLegacyScryptMemory {
+		return limitError("memory", memory, maxLegacyScryptMemory)
+	}
+	if work := memory * uint64(p); work > maxLegacyScryptWork {
+		return limitError("parallelism", work, maxLegacyScryptWork)
+	}
+	if l := verifyLimits.Load(); l != nil && l.MaxMemory > 0 && memory/1024 > uint64(l.MaxMemory) {
+		return limitError("memory", memory/1024, uint64(l.MaxMemory))
+	}
+	return nil
+}
+
+// limitError returns the error of a field exceeding its limit.
+func limitError(field string, value, limit uint64) error {
+	return &HashError{
+		Field: field,
+		Err:   ErrHashTooExpensive,
+		Cause: fmt.Errorf("%d exceeds the limit of %d", value, limit),
+	}
+}
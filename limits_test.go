@@ -0,0 +1,136 @@
+package argon2
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestSetVerifyLimits(t *testing.T) {
+	defer SetVerifyLimits(nil)
+
+	p := &Params{Memory: 16 * 1024, Iterations: 2, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+	hash := MustGenerateFromPassword([]byte("password"), p)
+
+	tests := []struct {
+		name      string
+		limits    *VerifyLimits
+		wantField string
+	}{
+		{name: "no limits"},
+		{name: "within the limits", limits: &VerifyLimits{MaxMemory: 16 * 1024, MaxIterations: 2, MaxParallelism: 2}},
+		{name: "zero limits", limits: &VerifyLimits{}},
+		{name: "memory", limits: &VerifyLimits{MaxMemory: 8 * 1024}, wantField: "memory"},
+		{name: "iterations", limits: &VerifyLimits{MaxIterations: 1}, wantField: "iterations"},
+		{name: "parallelism", limits: &VerifyLimits{MaxParallelism: 1}, wantField: "parallelism"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetVerifyLimits(tt.limits)
+
+			err := CompareHashAndPassword(hash, []byte("password"))
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("CompareHashAndPassword() error = %v", err)
+				}
+				return
+			}
+
+			var he *HashError
+			if !errors.Is(err, ErrHashTooExpensive) || !errors.As(err, &he) || he.Field != tt.wantField {
+				t.Errorf("CompareHashAndPassword() error = %v, want %v of field %q", err, ErrHashTooExpensive, tt.wantField)
+			}
+			if _, err := CompareAndUpdate(hash, []byte("password"), p); !errors.Is(err, ErrHashTooExpensive) {
+				t.Errorf("CompareAndUpdate() error = %v, want %v", err, ErrHashTooExpensive)
+			}
+
+			// inspecting the hash isn't affected
+			if _, err := ExtractParams(hash); err != nil {
+				t.Errorf("ExtractParams() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRaw_InvalidCosts(t *testing.T) {
+	salt, key := []byte("somesaltsomesalt"), make([]byte, 32)
+
+	tests := []struct {
+		name      string
+		params    *Params
+		wantErr   error
+		wantField string
+	}{
+		{name: "no iterations", params: &Params{Memory: 8 * 1024, Parallelism: 1, KeyLength: 32}, wantErr: ErrIterationsTooSmall, wantField: "iterations"},
+		{name: "no parallelism", params: &Params{Memory: 8 * 1024, Iterations: 1, KeyLength: 32}, wantErr: ErrParallelismTooSmall, wantField: "parallelism"},
+		{name: "too little memory per lane", params: &Params{Memory: 16, Iterations: 1, Parallelism: 4, KeyLength: 32}, wantErr: ErrMemoryTooSmall, wantField: "memory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyRaw([]byte("password"), salt, key, tt.params)
+			var he *HashError
+			if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrInvalidHash) || !errors.As(err, &he) || he.Field != tt.wantField {
+				t.Errorf("VerifyRaw() error = %v, want %v of field %q", err, tt.wantErr, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestVerifyAnyScheme_LegacyCeilings(t *testing.T) {
+	aspNet := make([]byte, 13+16+32)
+	aspNet[0], aspNet[4] = 0x01, 0x01
+	binary.BigEndian.PutUint32(aspNet[5:], 1<<30)
+	binary.BigEndian.PutUint32(aspNet[9:], 16)
+
+	tests := []struct {
+		name      string
+		hash      string
+		wantField string
+	}{
+		{name: "scrypt memory", hash: "1073741824$8$1$73616c7473616c74$6b65796b6579", wantField: "memory"},
+		{name: "scrypt work", hash: "1048576$8$64$73616c7473616c74$6b65796b6579", wantField: "parallelism"},
+		{name: "sha512-crypt rounds", hash: "$6$rounds=999999999$saltsalt$hash", wantField: "rounds"},
+		{name: "sha256-crypt rounds", hash: "$5$rounds=999999999$saltsalt$hash", wantField: "rounds"},
+		{name: "bcrypt cost", hash: "$2b$31$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", wantField: "cost"},
+		{name: "wrapped bcrypt cost", hash: "{WRAP:$2b$31$N9qo8uLOickgx2ZMRZoMye}$argon2id$v=19$m=65536,t=3,p=2$c29tZXNhbHQ$a2V5a2V5a2V5a2V5", wantField: "cost"},
+		{name: "django iterations", hash: "pbkdf2_sha256$999999999$saltsalt$a2V5a2V5", wantField: "iterations"},
+		{name: "asp.net identity iterations", hash: base64.StdEncoding.EncodeToString(aspNet), wantField: "iterations"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyAnyScheme([]byte(tt.hash), []byte("password"))
+			var hashErr *HashError
+			if !errors.Is(err, ErrHashTooExpensive) || !errors.As(err, &hashErr) || hashErr.Field != tt.wantField {
+				t.Errorf("VerifyAnyScheme() error = %v, want %v for %s", err, ErrHashTooExpensive, tt.wantField)
+			}
+			if _, err := VerifyAndMigrate([]byte(tt.hash), []byte("password"), nil); !errors.Is(err, ErrHashTooExpensive) {
+				t.Errorf("VerifyAndMigrate() error = %v, want %v", err, ErrHashTooExpensive)
+			}
+		})
+	}
+}
+
+func TestVerifyAnyScheme_ScryptVerifyLimits(t *testing.T) {
+	SetVerifyLimits(&VerifyLimits{MaxMemory: 8 * 1024})
+	defer SetVerifyLimits(nil)
+
+	// N=16384 and r=8 take 16 MiB
+	err := VerifyAnyScheme([]byte("16384$8$1$73616c7473616c74$6b65796b6579"), []byte("password"))
+	if !errors.Is(err, ErrHashTooExpensive) {
+		t.Errorf("VerifyAnyScheme() error = %v, want %v", err, ErrHashTooExpensive)
+	}
+}
+
+func TestVerifyAnyScheme_MaxPasswordLength(t *testing.T) {
+	SetMaxPasswordLength(8)
+	defer SetMaxPasswordLength(DefaultMaxPasswordLength)
+
+	hash := []byte("$2a$04$2zmA7ABHyQdNTcVkW8bCnOf3Wb3yb9RV0DuaL4zbRbhv1nQBhCTx.")
+	if err := VerifyAnyScheme(hash, []byte("a-long-password")); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("VerifyAnyScheme() error = %v, want %v", err, ErrPasswordTooLong)
+	}
+	if _, err := VerifyAndMigrate(hash, []byte("a-long-password"), nil); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("VerifyAndMigrate() error = %v, want %v", err, ErrPasswordTooLong)
+	}
+}
@@ -0,0 +1,106 @@
+package argon2
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// memoryBudgetShare is the share of the available memory AutoMemory
+// budgets for concurrent hashes, 1/4, leaving the rest to the application.
+const memoryBudgetShare = 4
+
+// ErrMemoryLimitUnknown is returned by AutoMemory when the available memory
+// can't be determined, e.g. on other systems than Linux.
+var ErrMemoryLimitUnknown = errors.New("argon2: the available memory is unknown")
+
+// AutoMemory proposes a memory parameter (in KiB) that won't run the host
+// or container out of memory with the given number of concurrent hashes,
+// e.g. the maximum number of concurrent logins. A quarter of the available
+// memory is budgeted for hashing: the memory limit of the cgroup the process
+// runs in, e.g. of a Kubernetes pod, or else the memory available on the
+// host. The proposal is capped to the Defaults' memory. ErrMemoryTooSmall is
+// returned if the budget doesn't afford the minimum memory of 8 MiB per hash,
+// so the concurrency should be limited instead.
+func AutoMemory(concurrency int) (uint32, error) {
+	limit, err := memoryLimit("/")
+	if err != nil {
+		return 0, err
+	}
+
+	return proposeMemory(limit, concurrency)
+}
+
+// proposeMemory proposes a memory parameter for the concurrency within
+// the share of the limit (in bytes).
+func proposeMemory(limit uint64, concurrency int) (uint32, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	memory := limit / memoryBudgetShare / uint64(concurrency) / 1024
+	if memory < minMemoryValue {
+		return 0, ErrMemoryTooSmall
+	}
+	if max := uint64(Defaults().Memory); memory > max {
+		memory = max
+	}
+
+	return uint32(memory), nil
+}
+
+// memoryLimit returns the memory (in bytes) available to the process, read
+// from the cgroup v2 or v1 memory limit, or /proc/meminfo if there is none,
+// under the root directory.
+func memoryLimit(root string) (uint64, error) {
+	available, err := memAvailable(filepath.Join(root, "proc/meminfo"))
+	if err != nil {
+		return 0, ErrMemoryLimitUnknown
+	}
+
+	for _, name := range []string{
+		"sys/fs/cgroup/memory.max",                   // cgroup v2
+		"sys/fs/cgroup/memory/memory.limit_in_bytes", // cgroup v1
+	} {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		// An unlimited cgroup reads "max" in v2, and a huge number in v1
+		limit, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err == nil && limit < available {
+			return limit, nil
+		}
+	}
+
+	return available, nil
+}
+
+// memAvailable returns the MemAvailable value (in bytes) of the named meminfo file.
+func memAvailable(name string) (uint64, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == "MemAvailable:" && fields[2] == "kB" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb * 1024, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, ErrMemoryLimitUnknown
+}
@@ -0,0 +1,122 @@
+package argon2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_memoryLimit(t *testing.T) {
+	const meminfo = "MemTotal:       16314128 kB\nMemFree:         1059612 kB\nMemAvailable:    8388608 kB\n"
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name:  "no cgroup limit",
+			files: map[string]string{"proc/meminfo": meminfo},
+			want:  8 << 30,
+		},
+		{
+			name:  "cgroup v2 limit",
+			files: map[string]string{"proc/meminfo": meminfo, "sys/fs/cgroup/memory.max": "536870912\n"},
+			want:  512 << 20,
+		},
+		{
+			name:  "unlimited cgroup v2",
+			files: map[string]string{"proc/meminfo": meminfo, "sys/fs/cgroup/memory.max": "max\n"},
+			want:  8 << 30,
+		},
+		{
+			name:  "cgroup v1 limit",
+			files: map[string]string{"proc/meminfo": meminfo, "sys/fs/cgroup/memory/memory.limit_in_bytes": "268435456\n"},
+			want:  256 << 20,
+		},
+		{
+			name:  "unlimited cgroup v1",
+			files: map[string]string{"proc/meminfo": meminfo, "sys/fs/cgroup/memory/memory.limit_in_bytes": "9223372036854771712\n"},
+			want:  8 << 30,
+		},
+		{
+			name:    "no meminfo",
+			files:   map[string]string{"sys/fs/cgroup/memory.max": "536870912\n"},
+			wantErr: true,
+		},
+		{
+			name:    "no MemAvailable",
+			files:   map[string]string{"proc/meminfo": "MemTotal:       16314128 kB\n"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, data := range tt.files {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := memoryLimit(root)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("memoryLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("memoryLimit() got = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_proposeMemory(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       uint64
+		concurrency int
+		want        uint32
+		wantErr     error
+	}{
+		{
+			name:        "large host",
+			limit:       16 << 30,
+			concurrency: 16,
+			want:        64 * 1024,
+		},
+		{
+			name:        "512 MiB pod",
+			limit:       512 << 20,
+			concurrency: 8,
+			want:        16 * 1024,
+		},
+		{
+			name:        "zero concurrency",
+			limit:       128 << 20,
+			concurrency: 0,
+			want:        32 * 1024,
+		},
+		{
+			name:        "too high concurrency",
+			limit:       256 << 20,
+			concurrency: 32,
+			wantErr:     ErrMemoryTooSmall,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := proposeMemory(tt.limit, tt.concurrency)
+			if err != tt.wantErr {
+				t.Fatalf("proposeMemory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("proposeMemory() got = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package argon2
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcryptPrefixes are the prefixes of the bcrypt hash versions.
+var bcryptPrefixes = [][]byte{[]byte("$2a$"), []byte("$2b$"), []byte("$2y$")}
+
+// VerifyAndMigrate compares a stored hash, either an argon2 or a legacy one,
+// with the possible cleartext equivalent. The supported legacy hashes are
+// bcrypt ones, scrypt ones in the format of github.com/elithrar/simple-scrypt,
+// the PBKDF2 ones of Django and ASP.NET Identity, MD5-crypt, SHA256-crypt and
+// SHA512-crypt ones, the ones wrapped with argon2 by WrapHash, and the ones
+// of the schemes a verifier is registered for with RegisterVerifier.
+// On success, it returns the hash to store from now on: nil for argon2
+// hashes and the records of GenerateDualHash, which can be kept as is, and
+// a fresh argon2 hash of the password, generated with the parameters
+// provided, for legacy ones. It allows to upgrade users to argon2 on their
+// next login:
+//
+//	newHash, err := argon2.VerifyAndMigrate(user.Hash, password, argon2.DefaultParams)
+//	if err != nil {
+//		return err
+//	}
+//	if newHash != nil {
+//		user.Hash = newHash
+//		// save the user
+//	}
+//
+// It returns ErrMismatchedHashAndPassword if the password doesn't match, and
+// the errors of CompareHashAndPassword for malformed hashes. Legacy hashes
+// whose costs are beyond any sensible configuration of their scheme, e.g.
+// an SHA512-crypt one with rounds=999999999, are rejected with a HashError
+// wrapping ErrHashTooExpensive before any key is derived.
+func VerifyAndMigrate(hash, password []byte, p *Params) ([]byte, error) {
+	defer padVerify(time.Now())
+
+	if err := checkPassword(password); err != nil {
+		return nil, err
+	}
+	hash = dualArgon2(hash)
+
+	v := legacyVerifier(hash)
+	if v == nil {
+		return nil, CompareHashAndPassword(hash, password)
+	}
+
+	if err := v.Verify(hash, password); err != nil {
+		return nil, err
+	}
+
+	return migrate(password, p)
+}
+
+// migrate returns a fresh argon2 hash of the verified password, or nil if
+// the password is rejected for new hashes, e.g. below the minimum length,
+// so it keeps its legacy hash rather than failing the login.
+func migrate(password []byte, p *Params) ([]byte, error) {
+	if checkNewPassword(password) != nil {
+		return nil, nil
+	}
+
+	return GenerateFromPassword(password, p)
+}
+
+// Verifier verifies legacy password hashes, e.g. the ones of custom schemes.
+type Verifier interface {
+	// Verify compares the hash with the password. It returns nil on success,
+	// and ErrMismatchedHashAndPassword if they don't match.
+	Verify(hash, password []byte) error
+}
+
+// VerifyAndMigrateWith is like VerifyAndMigrate, but verifies the legacy hash
+// with the given verifier, e.g. a PBKDF2Verifier for the hashes of a custom
+// scheme. It returns a fresh argon2 hash of the password on success, unless
+// the password is rejected for new hashes, e.g. below the minimum length.
+func VerifyAndMigrateWith(v Verifier, hash, password []byte, p *Params) ([]byte, error) {
+	defer padVerify(time.Now())
+
+	if err := checkPassword(password); err != nil {
+		return nil, err
+	}
+	if err := v.Verify(hash, password); err != nil {
+		return nil, err
+	}
+
+	return migrate(password, p)
+}
+
+// isBcrypt reports whether the hash is a bcrypt hash.
+func isBcrypt(hash []byte) bool {
+	for _, prefix := range bcryptPrefixes {
+		if bytes.HasPrefix(hash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBcryptCost returns an error if the cost recorded in the settings of
+// a bcrypt hash, e.g. "$2b$10$", exceeds the ceiling of the legacy hashes.
+func checkBcryptCost(settings []byte) error {
+	if len(settings) < 7 {
+		return ErrInvalidHash
+	}
+	cost, err := strconv.ParseUint(string(settings[4:6]), 10, 8)
+	if err != nil || cost > uint64(bcrypt.MaxCost) {
+		return ErrInvalidHash
+	}
+	if cost > maxLegacyBcryptCost {
+		return limitError("cost", cost, maxLegacyBcryptCost)
+	}
+	return nil
+}
+
+// compareBcrypt compares a bcrypt hash with the password, returning the
+// errors of the package instead of the ones of golang.org/x/crypto/bcrypt.
+func compareBcrypt(hash, password []byte) error {
+	if err := checkBcryptCost(hash); err != nil {
+		return err
+	}
+	switch err := bcrypt.CompareHashAndPassword(hash, password); err {
+	case nil:
+		return nil
+	case bcrypt.ErrMismatchedHashAndPassword:
+		return ErrMismatchedHashAndPassword
+	default:
+		return ErrInvalidHash
+	}
+}
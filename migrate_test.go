@@ -0,0 +1,128 @@
+package argon2
+
+import (
+	"crypto/sha512"
+	"errors"
+	"testing"
+)
+
+func TestVerifyAndMigrate(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+
+	tests := []struct {
+		name        string
+		hash        []byte
+		password    []byte
+		wantMigrate bool
+		wantErr     error
+	}{
+		{
+			name:     "argon2",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password"),
+		},
+		{
+			name:     "argon2 mismatch",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password1"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:        "bcrypt 2b",
+			hash:        []byte("$2b$05$abcdefghijklmnopqrstuuHIrMEWpUCQe2YqFR3sXwQ75u4od..9q"),
+			password:    []byte("pw"),
+			wantMigrate: true,
+		},
+		{
+			name:        "bcrypt 2y",
+			hash:        []byte("$2y$05$htpasswdsaltsaltsalt..UyPcWgfGd6J5xAEcY9Ua/rrqrEPU0ze"),
+			password:    []byte("htpasswd-secret"),
+			wantMigrate: true,
+		},
+		{
+			name:     "bcrypt mismatch",
+			hash:     []byte("$2y$05$htpasswdsaltsaltsalt..UyPcWgfGd6J5xAEcY9Ua/rrqrEPU0ze"),
+			password: []byte("pw"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "malformed bcrypt",
+			hash:     []byte("$2y$05$htpasswdsalt"),
+			password: []byte("pw"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:        "simple-scrypt",
+			hash:        []byte("16384$8$1$73696d706c652d7363727970742d3136$dea62b5899c96ae3f22eec8f08b540f07d1ca8dd0e2c503654ed32be01c8c7e2"),
+			password:    []byte("scrypt-password"),
+			wantMigrate: true,
+		},
+		{
+			name:     "simple-scrypt mismatch",
+			hash:     []byte("16384$8$1$73696d706c652d7363727970742d3136$dea62b5899c96ae3f22eec8f08b540f07d1ca8dd0e2c503654ed32be01c8c7e2"),
+			password: []byte("pw"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:        "django pbkdf2",
+			hash:        []byte("pbkdf2_sha256$1000$djangosalt123456$QSt1DbmULoHfp4LOVhZ+jIEQsbsweVrWOSj+Mr/RM7A="),
+			password:    []byte("pbkdf2-password"),
+			wantMigrate: true,
+		},
+		{
+			name:        "asp.net identity",
+			hash:        []byte("AQAAAAEAACcQAAAAEGFzcG5ldC1zYWx0LTAwMTa/rguuc7ASvOnm/m8zTWUEDyUiwvucM9J1/wrfStMYOQ=="),
+			password:    []byte("pbkdf2-password"),
+			wantMigrate: true,
+		},
+		{
+			name:        "sha512-crypt",
+			hash:        []byte("$6$shadowsalt$TnisS85/xIP2CrQ4q2tTzuYWga6aBhBGu2nFGm3bl6n56ZpqfZR.EAFWy8BXRDUhSHMvsFxqaK7QctsknbtJO0"),
+			password:    []byte("legacy-password"),
+			wantMigrate: true,
+		},
+		{
+			name:     "unknown scheme",
+			hash:     []byte("$3$saltsalt$hash"),
+			password: []byte("pw"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyAndMigrate(tt.hash, tt.password, p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyAndMigrate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if (got != nil) != tt.wantMigrate {
+				t.Errorf("VerifyAndMigrate() got = %s, wantMigrate %v", got, tt.wantMigrate)
+				return
+			}
+			if got == nil {
+				return
+			}
+			if err := CompareHashAndPassword(got, tt.password); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyAndMigrateWith(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+	v := &PBKDF2Verifier{PRF: sha512.New, Iterations: 5000}
+	hash := []byte("6173706e65742d73616c742d30303136$db71aa462f38b95bff85a4b06ba1ab94717ca0e038e9bbb5550bc73009910504b6d18e2add36ad02a53b93c6092a5c3e79a3857a72f11956a41822a118438699")
+
+	got, err := VerifyAndMigrateWith(v, hash, []byte("pbkdf2-password"), p)
+	if err != nil {
+		t.Fatalf("VerifyAndMigrateWith() error = %v", err)
+	}
+	if err := CompareHashAndPassword(got, []byte("pbkdf2-password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+
+	if _, err := VerifyAndMigrateWith(v, hash, []byte("pbkdf2-password1"), p); err != ErrMismatchedHashAndPassword {
+		t.Errorf("VerifyAndMigrateWith() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+}
@@ -0,0 +1,25 @@
+package argon2
+
+// MustGenerateFromPassword is like GenerateFromPassword, but panics if the
+// password can't be hashed. It is meant for fixtures, seed scripts and tests,
+// not for hashing user input.
+func MustGenerateFromPassword(password []byte, p *Params) []byte {
+	hash, err := GenerateFromPassword(password, p)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
+
+// MustParams is like ParseParams, but panics if the text form can't be
+// parsed or the parameters are invalid. It simplifies the initialization of
+// variables holding parameters, e.g.
+//
+//	var params = argon2.MustParams("m=65536,t=3,p=2")
+func MustParams(s string) *Params {
+	p, err := ParseParams(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
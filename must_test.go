@@ -0,0 +1,34 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMustGenerateFromPassword(t *testing.T) {
+	hash := MustGenerateFromPassword([]byte("password"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err := CompareHashAndPassword(hash, []byte("password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrMemoryTooSmall) {
+			t.Errorf("MustGenerateFromPassword() panic = %v, want %v", err, ErrMemoryTooSmall)
+		}
+	}()
+	MustGenerateFromPassword([]byte("password"), &Params{})
+}
+
+func TestMustParams(t *testing.T) {
+	p := MustParams("m=8192,t=1,p=1,sl=16,kl=32")
+	if p.Memory != 8*1024 || p.Iterations != 1 || p.Parallelism != 1 {
+		t.Errorf("MustParams() got = %v", p)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("MustParams() panic = %v, want %v", err, ErrInvalidParams)
+		}
+	}()
+	MustParams("m=8192,x=1")
+}
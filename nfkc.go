@@ -0,0 +1,127 @@
+package argon2
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// Normalization identifies the Unicode normalization form passwords are
+// normalized to before deriving their key, so the same passphrase typed on
+// different keyboards or operating systems, e.g. with composed or
+// decomposed code points, derives the same key. It is recorded in the PHC
+// formats as the "norm" parameter, e.g.
+// "$argon2id$v=19$m=65536,t=3,p=2,norm=nfkc$...", so the password is
+// normalized the same way when verifying.
+//
+// The normalization function isn't part of this package, see SetNFKC: a
+// process that doesn't install it can't verify the hashes recorded with
+// norm=nfkc, they are rejected with ErrUnsupportedParams. The form isn't
+// versioned either, so the installed function must normalize the passwords
+// the same way for as long as their hashes are stored.
+type Normalization int
+
+const (
+	// NoNormalization uses the password as is.
+	NoNormalization Normalization = iota
+
+	// NFKC normalizes the password to the Unicode Normalization Form KC,
+	// with the function installed with SetNFKC.
+	NFKC
+)
+
+// String returns the identifier of the normalization form.
+func (n Normalization) String() string {
+	switch n {
+	case NoNormalization:
+		return "none"
+	case NFKC:
+		return "nfkc"
+	default:
+		return "Normalization(" + strconv.Itoa(int(n)) + ")"
+	}
+}
+
+// nfkcFunc holds the function installed with SetNFKC.
+type nfkcFunc struct {
+	fn func(password []byte) []byte
+}
+
+var nfkc atomic.Pointer[nfkcFunc]
+
+// SetNFKC installs the function normalizing passwords to the NFKC form,
+// e.g. norm.NFKC.Bytes of golang.org/x/text/unicode/norm, which this
+// package doesn't depend on:
+//
+//	argon2.SetNFKC(norm.NFKC.Bytes)
+//
+// It must be installed at startup, before hashing or verifying passwords
+// with the NFKC normalization, in every process verifying them: without
+// it, the stored norm=nfkc hashes fail with ErrUnsupportedParams and their
+// users can't log in. The function must not modify its input.
+//
+// The "nfkc" recorded in the hashes doesn't name a Unicode version. NFKC is
+// stable across versions for assigned code points, but code points
+// unassigned in the Unicode version of the installed function may
+// normalize differently in a later one, and their passwords would no
+// longer verify after an upgrade. Applications upgrading the function
+// should reject passwords with unassigned code points, or rehash them. A
+// nil fn removes it.
+func SetNFKC(fn func(password []byte) []byte) {
+	if fn == nil {
+		nfkc.Store(nil)
+		return
+	}
+	nfkc.Store(&nfkcFunc{fn: fn})
+}
+
+// WithNormalization normalizes the passwords to the Unicode normalization
+// form n before deriving their key. The form is recorded in the hash, which
+// requires one of the PHC formats, e.g. WithFormat(FormatPHC). As the
+// peppered password isn't text anymore, it can't be combined with a pepper,
+// which is rejected with ErrInvalidParams.
+func WithNormalization(n Normalization) Option {
+	return func(o *options) { o.params.Normalization = n }
+}
+
+// checkNormalization returns ErrUnsupportedParams if the normalization form
+// of the parameters is unknown, or its function isn't installed.
+func (p *Params) checkNormalization() error {
+	switch p.Normalization {
+	case NoNormalization:
+		return nil
+	case NFKC:
+		if nfkc.Load() != nil {
+			return nil
+		}
+	}
+	return ErrUnsupportedParams
+}
+
+// checkPeppered returns ErrInvalidParams if p normalizes the passwords,
+// which can't be combined with a pepper: the HMAC digest of the password
+// would be normalized instead of the password itself.
+func checkPeppered(p *Params) error {
+	if p.Normalization != NoNormalization {
+		return ErrInvalidParams
+	}
+	return nil
+}
+
+// parseNormalization returns the normalization form of the given
+// identifier. It returns ErrUnsupportedParams if it is not supported.
+func parseNormalization(id []byte) (Normalization, error) {
+	if string(id) == NFKC.String() {
+		return NFKC, nil
+	}
+	return NoNormalization, ErrUnsupportedParams
+}
+
+// apply returns the password normalized to the form n.
+func (n Normalization) apply(password []byte) []byte {
+	if n == NFKC {
+		if f := nfkc.Load(); f != nil {
+			return f.fn(password)
+		}
+	}
+	return password
+}
@@ -0,0 +1,96 @@
+package argon2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// fakeNFKC composes "e" followed by U+0301 COMBINING ACUTE ACCENT into
+// U+00E9, standing in for norm.NFKC.Bytes.
+func fakeNFKC(password []byte) []byte {
+	return bytes.ReplaceAll(password, []byte("e\u0301"), []byte("\u00e9"))
+}
+
+func TestWithNormalization(t *testing.T) {
+	SetNFKC(fakeNFKC)
+	defer SetNFKC(nil)
+
+	composed := []byte("caf\u00e9 au lait")
+	decomposed := []byte("cafe\u0301 au lait")
+
+	hash, err := HashPassword(decomposed, WithMemory(8*1024), WithIterations(1), WithFormat(FormatPHC), WithNormalization(NFKC))
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if !bytes.Contains(hash, []byte(",norm=nfkc$")) {
+		t.Errorf("HashPassword() got = %s, want norm=nfkc", hash)
+	}
+
+	for _, password := range [][]byte{composed, decomposed} {
+		if err := CompareHashAndPassword(hash, password); err != nil {
+			t.Errorf("CompareHashAndPassword(%q) error = %v", password, err)
+		}
+	}
+	if err := CompareHashAndPassword(hash, []byte("cafe au lait")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareHashAndPassword() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+
+	// without the normalization, the forms derive different keys
+	plain, err := HashPassword(decomposed, WithMemory(8*1024), WithIterations(1), WithFormat(FormatPHC))
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if err := CompareHashAndPassword(plain, composed); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareHashAndPassword() without normalization error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+
+	SetNFKC(nil)
+	if err := CompareHashAndPassword(hash, composed); !errors.Is(err, ErrUnsupportedParams) {
+		t.Errorf("CompareHashAndPassword() without SetNFKC error = %v, want %v", err, ErrUnsupportedParams)
+	}
+}
+
+func TestWithNormalization_Invalid(t *testing.T) {
+	SetNFKC(fakeNFKC)
+	defer SetNFKC(nil)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "legacy format", opts: []Option{WithFormat(FormatLegacy), WithNormalization(NFKC)}, wantErr: ErrUnsupportedFormat},
+		{name: "unknown form", opts: []Option{WithFormat(FormatPHC), WithNormalization(Normalization(42))}, wantErr: ErrUnsupportedParams},
+		{name: "pepper", opts: []Option{WithFormat(FormatPHC), WithNormalization(NFKC), WithPepper([]byte("pepper"))}, wantErr: ErrInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := HashPassword([]byte("password"), tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("HashPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := NewHasher(tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewHasher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateFromPasswordWithPepper_Normalization(t *testing.T) {
+	SetNFKC(fakeNFKC)
+	defer SetNFKC(nil)
+
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC, Normalization: NFKC}
+	if _, err := GenerateFromPasswordWithPepper([]byte("password"), []byte("pepper"), p); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("GenerateFromPasswordWithPepper() error = %v, want %v", err, ErrInvalidParams)
+	}
+
+	// without a pepper, the password is normalized
+	hash, err := GenerateFromPasswordWithPepper([]byte("café"), nil, p)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithPepper() error = %v", err)
+	}
+	if err := CompareHashAndPassword(hash, []byte("café")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package argon2
+
+// NodeParams provides the defaults of the npm argon2 package's hash():
+// argon2id with memoryCost 65536 KiB, timeCost 3, parallelism 4, a 16 byte
+// salt and a 32 byte hash. Like the package, hashes generated with them are
+// encoded in the PHC format, with unpadded standard base64, so argon2.verify()
+// and argon2.needsRehash() accept them. Hashes from the package, including
+// the argon2i ones of its releases before 0.26, verify with
+// CompareHashAndPassword as is.
+//
+// NodeParams is shared by every importer of the package, so mutating it is
+// deprecated: use Node to get a copy instead.
+var NodeParams = Node()
+
+// Node returns a copy of the defaults of the npm argon2 package's hash(),
+// as described by NodeParams, which the caller is free to modify.
+func Node() *Params {
+	return &Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 4,
+		SaltLength:  16,
+		KeyLength:   32,
+		Format:      FormatPHC,
+	}
+}
@@ -0,0 +1,64 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestNodeCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  bool
+	}{
+		{
+			// argon2.hash() with default options
+			name:     "argon2id defaults",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$bm9kZS1hcmdvbjItc2FsdA$0bDTZ8p1uqVtTY1pFMy5aEx8+a81jFzw0qcIsU+ZkCw"),
+			password: []byte("npm-password"),
+		},
+		{
+			// argon2.hash() with default options of releases before 0.26
+			name:     "argon2i legacy defaults",
+			hash:     []byte("$argon2i$v=19$m=4096,t=3,p=1$bm9kZS1hcmdvbjItc2FsdA$ysuwVz7XtCHFlXqxIkiVpuhGWLCyA7FRJXYkVIeXF4I"),
+			password: []byte("npm-password"),
+		},
+		{
+			// argon2.hash() with { type: argon2.argon2id, memoryCost: 4096 }
+			name:     "argon2id custom memory cost",
+			hash:     []byte("$argon2id$v=19$m=4096,t=3,p=1$bm9kZS1hcmdvbjItc2FsdA$nLKoypSQE5J2wA4WDR7ojpvpuBMwKDEgOxE9B6nzais"),
+			password: []byte("npm-password"),
+		},
+		{
+			name:     "wrong password",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$bm9kZS1hcmdvbjItc2FsdA$0bDTZ8p1uqVtTY1pFMy5aEx8+a81jFzw0qcIsU+ZkCw"),
+			password: []byte("npm-password1"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPassword(tt.hash, tt.password); (err != nil) != tt.wantErr {
+				t.Errorf("CompareHashAndPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNodeParams(t *testing.T) {
+	hash, err := GenerateFromPassword([]byte("npm-password"), Node())
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	// argon2.needsRehash() compares the parameters with its defaults
+	const prefix = "$argon2id$v=19$m=65536,t=3,p=4$"
+	if string(hash[:len(prefix)]) != prefix {
+		t.Errorf("GenerateFromPassword() got = %s, want prefix %s", hash, prefix)
+	}
+
+	// @phc/format uses unpadded standard base64
+	if hash[len(hash)-1] == '=' {
+		t.Errorf("GenerateFromPassword() got = %s, want unpadded base64", hash)
+	}
+}
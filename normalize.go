@@ -0,0 +1,59 @@
+package argon2
+
+import (
+	"golang.org/x/crypto/argon2"
+)
+
+// Normalize fixes up the parameters in place, for config-driven deployments
+// preferring a warning to a hard failure for off-by-a-bit values. Zero cost,
+// salt and key length fields are filled from the Defaults, out-of-range ones
+// are clamped to the nearest valid bound, and an unsupported version is reset
+// to the current one. It returns the names of the changed fields, e.g. to log
+// them, or nil if none was changed. The variant and format are left as is.
+// Normalize a copy of the presets, e.g. from Defaults, not shared ones such
+// as DefaultParams.
+func (p *Params) Normalize() []string {
+	var changed []string
+	defaults := Defaults()
+
+	if v := normalize32(p.Memory, defaults.Memory, minMemoryValue, maxMemoryValue); v != p.Memory {
+		p.Memory = v
+		changed = append(changed, "Memory")
+	}
+	if p.Iterations == 0 {
+		p.Iterations = defaults.Iterations
+		changed = append(changed, "Iterations")
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = defaults.Parallelism
+		changed = append(changed, "Parallelism")
+	}
+	if v := normalize32(p.SaltLength, defaults.SaltLength, minSaltLength, maxSaltLength); v != p.SaltLength {
+		p.SaltLength = v
+		changed = append(changed, "SaltLength")
+	}
+	if v := normalize32(p.KeyLength, defaults.KeyLength, minKeyLength, maxKeyLength); v != p.KeyLength {
+		p.KeyLength = v
+		changed = append(changed, "KeyLength")
+	}
+	if p.Version != 0 && p.Version != argon2.Version {
+		p.Version = 0
+		changed = append(changed, "Version")
+	}
+
+	return changed
+}
+
+// normalize32 returns def for a zero v, or v clamped to the min and max bounds.
+func normalize32(v, def, min, max uint32) uint32 {
+	switch {
+	case v == 0:
+		return def
+	case v < min:
+		return min
+	case v > max:
+		return max
+	default:
+		return v
+	}
+}
@@ -0,0 +1,60 @@
+package argon2
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParams_Normalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      Params
+		want        Params
+		wantChanged []string
+	}{
+		{
+			name:   "valid params",
+			params: Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC},
+			want:   Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC},
+		},
+		{
+			name:        "zero params",
+			params:      Params{},
+			want:        *DefaultParams,
+			wantChanged: []string{"Memory", "Iterations", "Parallelism", "SaltLength", "KeyLength"},
+		},
+		{
+			name:        "too small values",
+			params:      Params{Memory: 4 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 4, KeyLength: 8},
+			want:        Params{Memory: 8 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 8, KeyLength: 16},
+			wantChanged: []string{"Memory", "SaltLength", "KeyLength"},
+		},
+		{
+			name:        "too large values",
+			params:      Params{Memory: 1 << 30, Iterations: 2, Parallelism: 1, SaltLength: 2048, KeyLength: 4096},
+			want:        Params{Memory: 4 * 1024 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 1024, KeyLength: 1024},
+			wantChanged: []string{"Memory", "SaltLength", "KeyLength"},
+		},
+		{
+			name:        "legacy version",
+			params:      Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Version: 0x10},
+			want:        Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			wantChanged: []string{"Version"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.params
+			changed := p.Normalize()
+			if !reflect.DeepEqual(changed, tt.wantChanged) {
+				t.Errorf("Normalize() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if p != tt.want {
+				t.Errorf("Normalize() got = %+v, want %+v", p, tt.want)
+			}
+			if err := p.Check(); err != nil {
+				t.Errorf("Check() error = %v after Normalize()", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package argon2
+
+// Option adjusts the configuration of HashPassword or NewHasher.
+type Option func(*options)
+
+// options is the configuration built by the Options of HashPassword.
+type options struct {
+	params  Params
+	salt    []byte
+	secret  []byte
+	pepper  []byte
+	peppers PepperMAC
+	policy  RehashPolicy
+
+	preHash          PreHash
+	preHashThreshold int
+
+	encryption     SecretProvider
+	passwordPolicy Policy
+}
+
+// WithParams replaces all the parameters with p. Options following it
+// adjust individual parameters.
+func WithParams(p *Params) Option {
+	return func(o *options) { o.params = *orDefault(p) }
+}
+
+// WithMemory sets the amount of memory used by the algorithm (kibibytes).
+func WithMemory(memory uint32) Option {
+	return func(o *options) { o.params.Memory = memory }
+}
+
+// WithIterations sets the number of iterations over the memory.
+func WithIterations(iterations uint32) Option {
+	return func(o *options) { o.params.Iterations = iterations }
+}
+
+// WithParallelism sets the number of threads (or lanes) used by the algorithm.
+func WithParallelism(parallelism uint8) Option {
+	return func(o *options) { o.params.Parallelism = parallelism }
+}
+
+// WithSaltLength sets the length of the random salt.
+func WithSaltLength(length uint32) Option {
+	return func(o *options) { o.params.SaltLength = length }
+}
+
+// WithKeyLength sets the length of the derived key.
+func WithKeyLength(length uint32) Option {
+	return func(o *options) { o.params.KeyLength = length }
+}
+
+// WithSalt uses the given salt instead of a random one, and sets the salt
+// length accordingly. Only use it to reproduce known hashes, e.g. in tests
+// or migrations: every password must get its own random salt.
+func WithSalt(salt []byte) Option {
+	return func(o *options) {
+		o.salt = salt
+		o.params.SaltLength = uint32(len(salt))
+	}
+}
+
+// WithFormat sets the encoding of the hash.
+func WithFormat(f Format) Option {
+	return func(o *options) { o.params.Format = f }
+}
+
+// WithVariant sets the argon2 variant.
+func WithVariant(v Variant) Option {
+	return func(o *options) { o.params.Variant = v }
+}
+
+// WithSecret mixes the secret key into the derivation, like
+// GenerateFromPasswordWithSecret.
+func WithSecret(secret []byte) Option {
+	return func(o *options) { o.secret = secret }
+}
+
+// WithAssociatedData binds the hash to the associated data, e.g. the ID of
+// the user or tenant it belongs to, as the associated data input (X) of
+// argon2. The data is recorded in the hash, which requires one of the PHC
+// formats, e.g. WithFormat(FormatPHC), and the hash is verified with
+// CompareHashAndPasswordWithData given the expected data.
+func WithAssociatedData(data []byte) Option {
+	return func(o *options) { o.params.Data = string(data) }
+}
+
+// WithPepper mixes the pepper into the password before hashing it, like
+// GenerateFromPasswordWithPepper.
+func WithPepper(pepper []byte) Option {
+	return func(o *options) { o.pepper = pepper }
+}
+
+// WithSecretProvider sources the peppers of a Hasher from the provider,
+// instead of a single pepper set by WithPepper. The hashes record the key ID
+// of their pepper, "{PEPPER:<key ID>}<argon2 hash>", and the ones of
+// another pepper than the current one need a rehash. It only applies to a
+// Hasher, HashPassword rejects it with ErrInvalidParams.
+func WithSecretProvider(secrets SecretProvider) Option {
+	return func(o *options) { o.peppers = providerMAC{secrets} }
+}
+
+// WithPepperMAC has the HMACs of the passwords with the peppers computed by
+// m, e.g. in an HSM, instead of a single pepper set by WithPepper. The
+// hashes record the key ID of their pepper, as with WithSecretProvider.
+// It only applies to a Hasher, HashPassword rejects it with ErrInvalidParams.
+func WithPepperMAC(m PepperMAC) Option {
+	return func(o *options) { o.peppers = m }
+}
+
+// WithRehashPolicy sets the policy deciding whether a hash needs a rehash,
+// instead of comparing its parameters with the configured ones. It only
+// applies to a Hasher, HashPassword rejects it with ErrInvalidParams.
+func WithRehashPolicy(policy RehashPolicy) Option {
+	return func(o *options) { o.policy = policy }
+}
+
+// checkNormalization returns ErrInvalidParams if the passwords are both
+// normalized and peppered.
+func (o *options) checkNormalization() error {
+	if len(o.pepper) > 0 || o.peppers != nil {
+		return checkPeppered(&o.params)
+	}
+	return nil
+}
+
+// HashPassword returns the encoded hash of the password, like
+// GenerateFromPassword, using the Defaults adjusted by the given options, e.g.
+//
+//	hash, err := argon2.HashPassword(password, argon2.WithMemory(64<<10), argon2.WithFormat(argon2.FormatPHC))
+//
+// The options that only apply to a Hasher, e.g. WithSecretProvider, are
+// rejected with ErrInvalidParams rather than ignored.
+func HashPassword(password []byte, opts ...Option) ([]byte, error) {
+	o := options{params: *Defaults()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.peppers != nil || o.policy != nil || o.encryption != nil {
+		return nil, ErrInvalidParams
+	}
+	if err := checkNewPasswordWith(password, o.passwordPolicy); err != nil {
+		return nil, err
+	}
+	if err := checkPreHash(&o.params, o.preHash); err != nil {
+		return nil, err
+	}
+	if err := o.checkNormalization(); err != nil {
+		return nil, err
+	}
+	peppered := applyPepper(password, o.pepper)
+	defer wipePeppered(peppered, password)
+	p := preHashParams(&o.params, o.preHash, o.preHashThreshold, peppered)
+	if o.salt == nil {
+		return appendDerived(nil, peppered, o.secret, p)
+	}
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+
+	key := p.Variant.deriveKey(peppered, o.salt, o.secret, p)
+	return appendEncoded(nil, p.Format, p, o.salt, key), nil
+}
@@ -0,0 +1,147 @@
+package argon2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	salt := []byte("somesaltsomesalt")
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantPrefix string
+		wantErr    error
+	}{
+		{
+			name:       "defaults",
+			wantPrefix: "argon2id$19$65536$3$2$",
+		},
+		{
+			name:       "adjusted params",
+			opts:       []Option{WithMemory(8 * 1024), WithIterations(1), WithParallelism(1), WithFormat(FormatPHC)},
+			wantPrefix: "$argon2id$v=19$m=8192,t=1,p=1$",
+		},
+		{
+			name:       "params and variant",
+			opts:       []Option{WithParams(p), WithVariant(Argon2i), WithKeyLength(16)},
+			wantPrefix: "$argon2i$v=19$m=8192,t=1,p=1$",
+		},
+		{
+			name:       "fixed salt",
+			opts:       []Option{WithParams(p), WithSalt(salt)},
+			wantPrefix: "$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$",
+		},
+		{
+			name:    "invalid memory",
+			opts:    []Option{WithMemory(1)},
+			wantErr: ErrMemoryTooSmall,
+		},
+		{
+			name:    "short salt",
+			opts:    []Option{WithSalt([]byte("salt"))},
+			wantErr: ErrSaltTooShort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := HashPassword([]byte("password"), tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HashPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !bytes.HasPrefix(hash, []byte(tt.wantPrefix)) {
+				t.Errorf("HashPassword() got = %s, want prefix %s", hash, tt.wantPrefix)
+			}
+			if err := CompareHashAndPassword(hash, []byte("password")); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestHashPasswordWithSecret(t *testing.T) {
+	secret := []byte("server-secret")
+	hash, err := HashPassword([]byte("password"), WithMemory(8*1024), WithIterations(1), WithSecret(secret))
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if err := CompareHashAndPasswordWithSecret(hash, []byte("password"), secret); err != nil {
+		t.Errorf("CompareHashAndPasswordWithSecret() error = %v", err)
+	}
+	if err := CompareHashAndPassword(hash, []byte("password")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareHashAndPassword() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+}
+
+func TestHashPasswordWithAssociatedData(t *testing.T) {
+	hash, err := HashPassword([]byte("password"), WithMemory(8*1024), WithIterations(1), WithFormat(FormatPHC), WithAssociatedData([]byte("user-1")))
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if !bytes.Contains(hash, []byte(",data=dXNlci0x$")) {
+		t.Errorf("HashPassword() got = %s, want data=dXNlci0x", hash)
+	}
+	if p, err := ExtractParams(hash); err != nil || p.Data != "user-1" {
+		t.Errorf("ExtractParams() got = %v, error = %v, want Data user-1", p, err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		data     []byte
+		wantErr  error
+	}{
+		{name: "bound user", password: "password", data: []byte("user-1")},
+		{name: "wrong password", password: "wrong", data: []byte("user-1"), wantErr: ErrMismatchedHashAndPassword},
+		{name: "other user", password: "password", data: []byte("user-2"), wantErr: ErrMismatchedHashAndPassword},
+		{name: "no data", password: "password", data: []byte{}, wantErr: ErrMismatchedHashAndPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPasswordWithData(hash, []byte(tt.password), tt.data); err != tt.wantErr {
+				t.Errorf("CompareHashAndPasswordWithData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := CompareHashAndPassword(hash, []byte("password")); !errors.Is(err, ErrUnsupportedParams) {
+		t.Errorf("CompareHashAndPassword() error = %v, want %v", err, ErrUnsupportedParams)
+	}
+
+	unbound := MustGenerateFromPassword([]byte("password"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err := CompareHashAndPasswordWithData(unbound, []byte("password"), []byte("user-1")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareHashAndPasswordWithData() of an unbound hash error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+
+	if _, err := HashPassword([]byte("password"), WithFormat(FormatLegacy), WithAssociatedData([]byte("user-1"))); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("HashPassword() in the legacy format error = %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
+
+func TestHashPassword_HasherOptions(t *testing.T) {
+	keys := &KeyRing{CurrentID: "k1", Keys: map[string][]byte{"k1": []byte("pepper")}}
+
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{name: "secret provider", opt: WithSecretProvider(keys)},
+		{name: "pepper mac", opt: WithPepperMAC(providerMAC{keys})},
+		{name: "rehash policy", opt: WithRehashPolicy(paramsPolicy(Defaults()))},
+		{name: "hash encryption", opt: WithHashEncryption(keys)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := HashPassword([]byte("password"), WithMemory(8*1024), WithIterations(1), tt.opt)
+			if !errors.Is(err, ErrInvalidParams) || hash != nil {
+				t.Errorf("HashPassword() = %s, %v, want %v", hash, err, ErrInvalidParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package argon2
+
+// The argon2id recommendations of the OWASP Password Storage Cheat Sheet,
+// by revision. Each revision is kept as is, so hashes can be compared to
+// the recommendation they were generated for.
+var (
+	// OWASP2021Params is the 2021 recommendation: 15 MiB of memory,
+	// 2 iterations and 1 degree of parallelism.
+	//
+	// OWASP2021Params is shared by every importer of the package, so
+	// mutating it is deprecated: use OWASP2021 to get a copy instead.
+	OWASP2021Params = OWASP2021()
+
+	// OWASP2023Params is the 2023 recommendation: 19 MiB of memory,
+	// 2 iterations and 1 degree of parallelism.
+	//
+	// OWASP2023Params is shared by every importer of the package, so
+	// mutating it is deprecated: use OWASP2023 to get a copy instead.
+	OWASP2023Params = OWASP2023()
+)
+
+// OWASP2021 returns a copy of the 2021 recommendation, which the caller is
+// free to modify: 15 MiB of memory, 2 iterations and 1 degree of parallelism.
+func OWASP2021() *Params {
+	return &Params{
+		Memory:      15 * 1024,
+		Iterations:  2,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// OWASP2023 returns a copy of the 2023 recommendation, which the caller is
+// free to modify: 19 MiB of memory, 2 iterations and 1 degree of parallelism.
+func OWASP2023() *Params {
+	return &Params{
+		Memory:      19 * 1024,
+		Iterations:  2,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// owaspConfigurations are the equivalent argon2id configurations of the
+// current OWASP recommendation, trading memory for iterations. The cheat
+// sheet lists them with 1 degree of parallelism, OWASP2023Params being one.
+var owaspConfigurations = []struct{ Memory, Iterations uint32 }{
+	{Memory: 46 * 1024, Iterations: 1},
+	{Memory: 19 * 1024, Iterations: 2},
+	{Memory: 12 * 1024, Iterations: 3},
+	{Memory: 9 * 1024, Iterations: 4},
+	{Memory: 7 * 1024, Iterations: 5},
+}
+
+// meetsOWASP reports whether the parameters use at least the memory and
+// iterations of one of the configurations of the OWASP recommendation.
+func (p *Params) meetsOWASP() bool {
+	for _, c := range owaspConfigurations {
+		if p.Memory >= c.Memory && p.Iterations >= c.Iterations {
+			return true
+		}
+	}
+	return false
+}
+
+// OWASPRevision is the revision of the OWASP Password Storage Cheat Sheet
+// OWASP tracks.
+const OWASPRevision = "2023"
+
+// OWASPParams tracks the latest argon2id recommendation of the OWASP Password
+// Storage Cheat Sheet, currently equal to OWASP2023Params. It changes with
+// the recommendation in new releases of the package; pin a revision, e.g.
+// OWASP2023Params, to keep the parameters of a release.
+//
+// OWASPParams is shared by every importer of the package, so mutating it is
+// deprecated: use OWASP to get a copy instead.
+var OWASPParams = OWASP()
+
+// OWASP returns a copy of the latest argon2id recommendation of the OWASP
+// Password Storage Cheat Sheet, currently the one of OWASP2023, which the
+// caller is free to modify. It changes with the recommendation in new
+// releases of the package; pin a revision, e.g. OWASP2023, to keep the
+// parameters of a release.
+func OWASP() *Params {
+	return OWASP2023()
+}
@@ -0,0 +1,83 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestOWASPParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *Params
+		prefix string
+	}{
+		{
+			name:   "2021",
+			params: OWASP2021(),
+			prefix: "argon2id$19$15360$2$1$",
+		},
+		{
+			name:   "2023",
+			params: OWASP2023(),
+			prefix: "argon2id$19$19456$2$1$",
+		},
+		{
+			name:   "latest",
+			params: OWASP(),
+			prefix: "argon2id$19$19456$2$1$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := GenerateFromPassword([]byte("owasp-password"), tt.params)
+			if err != nil {
+				t.Fatalf("GenerateFromPassword() error = %v", err)
+			}
+			if string(hash[:len(tt.prefix)]) != tt.prefix {
+				t.Errorf("GenerateFromPassword() got = %s, want prefix %s", hash, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestPresets(t *testing.T) {
+	tests := []struct {
+		name   string
+		preset func() *Params
+		shared *Params
+	}{
+		{"owasp 2021", OWASP2021, OWASP2021Params},
+		{"owasp 2023", OWASP2023, OWASP2023Params},
+		{"owasp", OWASP, OWASPParams},
+		{"interactive", Interactive, InteractiveParams},
+		{"moderate", Moderate, ModerateParams},
+		{"sensitive", Sensitive, SensitiveParams},
+		{"php", PHP, PHPParams},
+		{"node", Node, NodeParams},
+		{"ruby", Ruby, RubyParams},
+		{"low memory", LowMemory, LowMemoryParams},
+	}
+	if OWASPParams == OWASP2023Params {
+		t.Errorf("OWASPParams and OWASP2023Params share a pointer")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.preset()
+			if *p != *tt.shared {
+				t.Errorf("preset got = %v, want %v", p, tt.shared)
+			}
+			want := *p
+
+			p.Memory = 1
+			if got := tt.preset(); *got != want {
+				t.Errorf("preset got = %v after modifying a copy, want %v", got, want)
+			}
+
+			saved := *tt.shared
+			defer func() { *tt.shared = saved }()
+			tt.shared.Iterations = 100
+			if got := tt.preset(); *got != want {
+				t.Errorf("preset got = %v after modifying the shared preset, want %v", got, want)
+			}
+		})
+	}
+}
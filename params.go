@@ -0,0 +1,324 @@
+package argon2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// paramsJSON is the JSON representation of Params.
+type paramsJSON struct {
+	Memory      uint32 `json:"memory"`
+	Iterations  uint32 `json:"iterations"`
+	Parallelism uint8  `json:"parallelism"`
+	SaltLength  uint32 `json:"salt_length"`
+	KeyLength   uint32 `json:"key_length"`
+	Format      string `json:"format"`
+	Variant     string `json:"variant"`
+	Version     uint32 `json:"version"`
+	URLSafe     bool   `json:"url_safe,omitempty"`
+
+	KeyID         string `json:"key_id,omitempty"`
+	Data          []byte `json:"data,omitempty"`
+	PreHash       string `json:"prehash,omitempty"`
+	Normalization string `json:"normalization,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The parameters are
+// represented as an object with the "memory" (in KiB), "iterations",
+// "parallelism", "salt_length", "key_length", "format", "variant",
+// "version" and, if set, "url_safe", "key_id", "data" (in Base64),
+// "prehash" and "normalization" fields, e.g.
+// {"memory":65536,"iterations":3,...,"format":"phc","variant":"argon2id","version":19}.
+func (p Params) MarshalJSON() ([]byte, error) {
+	v := paramsJSON{
+		Memory:      p.Memory,
+		Iterations:  p.Iterations,
+		Parallelism: p.Parallelism,
+		SaltLength:  p.SaltLength,
+		KeyLength:   p.KeyLength,
+		Format:      p.Format.String(),
+		Variant:     p.Variant.String(),
+		Version:     p.version(),
+		URLSafe:     p.URLSafe,
+		KeyID:       p.KeyID,
+	}
+	if p.Data != "" {
+		v.Data = []byte(p.Data)
+	}
+	if p.PreHash != NoPreHash {
+		v.PreHash = p.PreHash.String()
+	}
+	if p.Normalization != NoNormalization {
+		v.Normalization = p.Normalization.String()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, e.g. to load
+// the parameters from a configuration file. Omitted fields are taken from
+// the Defaults, and the decoded parameters are validated with Check, so
+// invalid ones are rejected with its errors. An unknown format or variant
+// is rejected with ErrUnsupportedFormat or ErrUnknownAlgorithm.
+func (p *Params) UnmarshalJSON(data []byte) error {
+	defaults := Defaults()
+	v := paramsJSON{
+		Memory:      defaults.Memory,
+		Iterations:  defaults.Iterations,
+		Parallelism: defaults.Parallelism,
+		SaltLength:  defaults.SaltLength,
+		KeyLength:   defaults.KeyLength,
+		Format:      defaults.Format.String(),
+		Variant:     defaults.Variant.String(),
+		Version:     argon2.Version,
+		URLSafe:     defaults.URLSafe,
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	params := Params{
+		Memory:      v.Memory,
+		Iterations:  v.Iterations,
+		Parallelism: v.Parallelism,
+		SaltLength:  v.SaltLength,
+		KeyLength:   v.KeyLength,
+		URLSafe:     v.URLSafe,
+		KeyID:       v.KeyID,
+		Data:        string(v.Data),
+	}
+	var err error
+	if params.Format, err = parseFormat(v.Format); err != nil {
+		return err
+	}
+	if v.PreHash != "" && v.PreHash != NoPreHash.String() {
+		if params.PreHash, err = parsePreHash([]byte(v.PreHash)); err != nil {
+			return err
+		}
+	}
+	if v.Normalization != "" && v.Normalization != NoNormalization.String() {
+		if params.Normalization, err = parseNormalization([]byte(v.Normalization)); err != nil {
+			return err
+		}
+	}
+	if params.Variant, err = parseVariant([]byte(v.Variant)); err != nil {
+		return err
+	}
+	if params.Version, err = checkVersion(uint64(v.Version)); err != nil {
+		return err
+	}
+	if err := params.Check(); err != nil {
+		return err
+	}
+
+	*p = params
+
+	return nil
+}
+
+// String returns the canonical single-line text form of the parameters,
+// e.g. "m=65536,t=3,p=2,sl=16,kl=32": the memory (in KiB), iterations,
+// parallelism, salt and key lengths, followed by the variant ("alg"),
+// format ("fmt"), version ("v"), URL-safe encoding ("url"), key ID
+// ("keyid"), associated data ("data"), pre-hash function ("ph") and
+// normalization ("norm") if they aren't the zero values, the key ID and
+// associated data in Base64 as in the PHC format, e.g.
+// "m=19456,t=2,p=1,sl=16,kl=32,alg=argon2i,fmt=phc".
+func (p Params) String() string {
+	b := make([]byte, 0, 64)
+	b = append(b, "m="...)
+	b = strconv.AppendUint(b, uint64(p.Memory), 10)
+	b = append(b, ",t="...)
+	b = strconv.AppendUint(b, uint64(p.Iterations), 10)
+	b = append(b, ",p="...)
+	b = strconv.AppendUint(b, uint64(p.Parallelism), 10)
+	b = append(b, ",sl="...)
+	b = strconv.AppendUint(b, uint64(p.SaltLength), 10)
+	b = append(b, ",kl="...)
+	b = strconv.AppendUint(b, uint64(p.KeyLength), 10)
+	if p.Variant != Argon2id {
+		b = append(b, ",alg="...)
+		b = append(b, p.Variant.String()...)
+	}
+	if p.Format != FormatLegacy {
+		b = append(b, ",fmt="...)
+		b = append(b, p.Format.String()...)
+	}
+	if p.Version != 0 {
+		b = append(b, ",v="...)
+		b = strconv.AppendUint(b, uint64(p.Version), 10)
+	}
+	if p.URLSafe {
+		b = append(b, ",url=true"...)
+	}
+	if p.KeyID != "" {
+		b = append(b, ",keyid="...)
+		b = appendBase64(b, base64.RawStdEncoding, []byte(p.KeyID))
+	}
+	if p.Data != "" {
+		b = append(b, ",data="...)
+		b = appendBase64(b, base64.RawStdEncoding, []byte(p.Data))
+	}
+	if p.PreHash != NoPreHash {
+		b = append(b, ",ph="...)
+		b = append(b, p.PreHash.String()...)
+	}
+	if p.Normalization != NoNormalization {
+		b = append(b, ",norm="...)
+		b = append(b, p.Normalization.String()...)
+	}
+
+	return string(b)
+}
+
+// ParseParams parses the text form of the parameters returned by String,
+// e.g. from a flag or an environment variable. The fields may come in any
+// order, and omitted ones are taken from the Defaults. The parsed
+// parameters are validated with Check. It returns ErrInvalidParams if the
+// text is malformed or has unknown or repeated fields.
+func ParseParams(s string) (*Params, error) {
+	p := Defaults()
+	seen := make(map[string]bool)
+
+	for _, field := range strings.Split(s, ",") {
+		i := strings.IndexByte(field, '=')
+		if i < 0 || seen[field[:i]] {
+			return nil, ErrInvalidParams
+		}
+		key, value := field[:i], field[i+1:]
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "m":
+			p.Memory, err = parseUint32(value)
+		case "t":
+			p.Iterations, err = parseUint32(value)
+		case "p":
+			var v uint64
+			v, err = strconv.ParseUint(value, 10, 8)
+			p.Parallelism = uint8(v)
+		case "sl":
+			p.SaltLength, err = parseUint32(value)
+		case "kl":
+			p.KeyLength, err = parseUint32(value)
+		case "alg":
+			p.Variant, err = parseVariant([]byte(value))
+		case "fmt":
+			p.Format, err = parseFormat(value)
+		case "v":
+			var v uint32
+			if v, err = parseUint32(value); err == nil {
+				p.Version, err = checkVersion(uint64(v))
+			}
+		case "url":
+			p.URLSafe, err = strconv.ParseBool(value)
+		case "keyid":
+			p.KeyID, err = parseBase64Param(value)
+		case "data":
+			p.Data, err = parseBase64Param(value)
+		case "ph":
+			p.PreHash, err = parsePreHash([]byte(value))
+		case "norm":
+			p.Normalization, err = parseNormalization([]byte(value))
+		default:
+			return nil, ErrInvalidParams
+		}
+		if _, ok := err.(*strconv.NumError); ok {
+			return nil, ErrInvalidParams
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// parseUint32 parses a decimal unsigned 32-bit integer.
+func parseUint32(s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	return uint32(v), err
+}
+
+// parseBase64Param decodes a non-empty unpadded standard Base64 value.
+// It returns ErrInvalidParams if the value is malformed or empty.
+func parseBase64Param(s string) (string, error) {
+	v, err := base64.RawStdEncoding.DecodeString(s)
+	if err != nil || len(v) == 0 {
+		return "", ErrInvalidParams
+	}
+	return string(v), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface,
+// encoding the parameters in the text form returned by String.
+func (p Params) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface,
+// decoding the parameters as ParseParams does.
+func (p *Params) UnmarshalText(text []byte) error {
+	params, err := ParseParams(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = *params
+
+	return nil
+}
+
+// Equal reports whether the parameters derive the same keys as other: the
+// memory, iterations, parallelism, salt and key lengths, variant, version,
+// key ID, associated data, pre-hash function and normalization are the
+// same, the zero version being the current one. The encoding of the hashes,
+// i.e. the format and the Base64 alphabet, isn't compared.
+func (p *Params) Equal(other *Params) bool {
+	return p.Memory == other.Memory &&
+		p.Iterations == other.Iterations &&
+		p.Parallelism == other.Parallelism &&
+		p.SaltLength == other.SaltLength &&
+		p.KeyLength == other.KeyLength &&
+		p.Variant == other.Variant &&
+		p.version() == other.version() &&
+		p.KeyID == other.KeyID &&
+		p.Data == other.Data &&
+		p.PreHash == other.PreHash &&
+		p.Normalization == other.Normalization
+}
+
+// WeakerThan reports whether the parameters are weaker than other: they
+// use less memory or iterations, a shorter salt or key, or the legacy argon2
+// version while other uses the current one. The parallelism and variant
+// aren't ordered by strength, so they aren't compared: the cost of cracking
+// a hash depends on its memory and iterations, not on its number of lanes.
+func (p *Params) WeakerThan(other *Params) bool {
+	return p.Memory < other.Memory ||
+		p.Iterations < other.Iterations ||
+		p.SaltLength < other.SaltLength ||
+		p.KeyLength < other.KeyLength ||
+		p.version() < other.version()
+}
+
+// version returns the argon2 version of the parameters, resolving the zero
+// value to the current version.
+func (p *Params) version() uint32 {
+	if p.Version == 0 {
+		return argon2.Version
+	}
+	return p.Version
+}
+
+// cost returns the memory times the number of iterations.
+func (p *Params) cost() uint64 {
+	return uint64(p.Memory) * uint64(p.Iterations)
+}
@@ -0,0 +1,298 @@
+package argon2
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParams_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		want   string
+	}{
+		{
+			name:   "default params",
+			params: *DefaultParams,
+			want:   `{"memory":65536,"iterations":3,"parallelism":2,"salt_length":16,"key_length":32,"format":"legacy","variant":"argon2id","version":19}`,
+		},
+		{
+			name:   "phc argon2i url-safe",
+			params: Params{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC, Variant: Argon2i, URLSafe: true},
+			want:   `{"memory":19456,"iterations":2,"parallelism":1,"salt_length":16,"key_length":32,"format":"phc","variant":"argon2i","version":19,"url_safe":true}`,
+		},
+		{
+			name:   "phc extensions",
+			params: Params{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC, KeyID: "k1", Data: "user", PreHash: PreHashSHA512},
+			want:   `{"memory":19456,"iterations":2,"parallelism":1,"salt_length":16,"key_length":32,"format":"phc","variant":"argon2id","version":19,"key_id":"k1","data":"dXNlcg==","prehash":"sha512"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(&tt.params)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() got = %s, want %s", got, tt.want)
+			}
+
+			var p Params
+			if err := json.Unmarshal(got, &p); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if p != tt.params {
+				t.Errorf("UnmarshalJSON() got = %+v, want %+v", p, tt.params)
+			}
+		})
+	}
+}
+
+func TestParams_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Params
+		wantErr error
+	}{
+		{
+			name: "omitted fields",
+			data: `{"memory":131072,"format":"phc"}`,
+			want: Params{Memory: 131072, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32, Format: FormatPHC},
+		},
+		{
+			name:    "invalid memory",
+			data:    `{"memory":1024}`,
+			wantErr: ErrMemoryTooSmall,
+		},
+		{
+			name:    "unknown variant",
+			data:    `{"variant":"argon3"}`,
+			wantErr: ErrUnknownAlgorithm,
+		},
+		{
+			name:    "unknown format",
+			data:    `{"format":"bcrypt"}`,
+			wantErr: ErrUnsupportedFormat,
+		},
+		{
+			name:    "legacy version",
+			data:    `{"version":16}`,
+			wantErr: ErrIncompatibleVersion,
+		},
+		{
+			name:    "unsupported version",
+			data:    `{"version":20}`,
+			wantErr: ErrIncompatibleVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Params
+			err := json.Unmarshal([]byte(tt.data), &p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if p != tt.want {
+				t.Errorf("UnmarshalJSON() got = %+v, want %+v", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestParams_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		want   string
+	}{
+		{
+			name:   "default params",
+			params: *DefaultParams,
+			want:   "m=65536,t=3,p=2,sl=16,kl=32",
+		},
+		{
+			name:   "all fields",
+			params: Params{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC, Variant: Argon2i, Version: 0x10, URLSafe: true},
+			want:   "m=19456,t=2,p=1,sl=16,kl=32,alg=argon2i,fmt=phc,v=16,url=true",
+		},
+		{
+			name:   "phc extensions",
+			params: Params{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC, KeyID: "k1", Data: "user", PreHash: PreHashSHA512, Normalization: NFKC},
+			want:   "m=19456,t=2,p=1,sl=16,kl=32,fmt=phc,keyid=azE,data=dXNlcg,ph=sha512,norm=nfkc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.params.String(); got != tt.want {
+				t.Errorf("String() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    *Params
+		wantErr error
+	}{
+		{
+			name: "canonical",
+			s:    "m=19456,t=2,p=1,sl=16,kl=32,alg=argon2i,fmt=phc,url=true",
+			want: &Params{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC, Variant: Argon2i, URLSafe: true},
+		},
+		{
+			name: "any order with omitted fields",
+			s:    "t=4,m=131072",
+			want: &Params{Memory: 131072, Iterations: 4, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+		},
+		{
+			name: "current version",
+			s:    "m=65536,v=19",
+			want: &Params{Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+		},
+		{
+			name:    "legacy version",
+			s:       "m=65536,v=16",
+			wantErr: ErrIncompatibleVersion,
+		},
+		{
+			name:    "too small memory",
+			s:       "m=1024",
+			wantErr: ErrMemoryTooSmall,
+		},
+		{
+			name:    "malformed number",
+			s:       "m=64MiB",
+			wantErr: ErrInvalidParams,
+		},
+		{
+			name:    "too large parallelism",
+			s:       "p=256",
+			wantErr: ErrInvalidParams,
+		},
+		{
+			name:    "unknown field",
+			s:       "m=65536,n=1",
+			wantErr: ErrInvalidParams,
+		},
+		{
+			name:    "repeated field",
+			s:       "m=65536,m=131072",
+			wantErr: ErrInvalidParams,
+		},
+		{
+			name:    "missing value",
+			s:       "m",
+			wantErr: ErrInvalidParams,
+		},
+		{
+			name:    "unknown variant",
+			s:       "alg=argon3",
+			wantErr: ErrUnknownAlgorithm,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseParams(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != nil && (got == nil || *got != *tt.want) {
+				t.Errorf("ParseParams() got = %v, want %v", got, tt.want)
+			}
+			if tt.want == nil && got != nil {
+				t.Errorf("ParseParams() got = %v, want nil", got)
+			}
+		})
+	}
+}
+
+func TestParams_MarshalText(t *testing.T) {
+	p := Params{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC, KeyID: "k1", Data: "user", PreHash: PreHashBLAKE2b}
+	text, err := p.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+
+	var got Params
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText() error = %v", err)
+	}
+	if got != p {
+		t.Errorf("UnmarshalText() got = %v, want %v", got, p)
+	}
+	if err := got.UnmarshalText([]byte("m=1")); err == nil {
+		t.Errorf("UnmarshalText() error = nil, want an error")
+	}
+}
+
+func TestParams_Equal(t *testing.T) {
+	base := Params{Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+
+	tests := []struct {
+		name   string
+		modify func(p *Params)
+		want   bool
+	}{
+		{name: "same", modify: func(p *Params) {}, want: true},
+		{name: "explicit current version", modify: func(p *Params) { p.Version = 0x13 }, want: true},
+		{name: "other format", modify: func(p *Params) { p.Format, p.URLSafe = FormatPHC, true }, want: true},
+		{name: "other memory", modify: func(p *Params) { p.Memory = 131072 }, want: false},
+		{name: "other iterations", modify: func(p *Params) { p.Iterations = 4 }, want: false},
+		{name: "other parallelism", modify: func(p *Params) { p.Parallelism = 4 }, want: false},
+		{name: "other salt length", modify: func(p *Params) { p.SaltLength = 32 }, want: false},
+		{name: "other key length", modify: func(p *Params) { p.KeyLength = 64 }, want: false},
+		{name: "other variant", modify: func(p *Params) { p.Variant = Argon2i }, want: false},
+		{name: "legacy version", modify: func(p *Params) { p.Version = 0x10 }, want: false},
+		{name: "other key id", modify: func(p *Params) { p.KeyID = "k2" }, want: false},
+		{name: "other data", modify: func(p *Params) { p.Data = "user" }, want: false},
+		{name: "other pre-hash", modify: func(p *Params) { p.PreHash = PreHashSHA512 }, want: false},
+		{name: "other normalization", modify: func(p *Params) { p.Normalization = NFKC }, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			tt.modify(&other)
+			if got := base.Equal(&other); got != tt.want {
+				t.Errorf("Equal() got = %v, want %v", got, tt.want)
+			}
+			if got := other.Equal(&base); got != tt.want {
+				t.Errorf("Equal() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParams_WeakerThan(t *testing.T) {
+	base := Params{Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+
+	tests := []struct {
+		name   string
+		modify func(p *Params)
+		want   bool
+	}{
+		{name: "same", modify: func(p *Params) {}, want: false},
+		{name: "less memory", modify: func(p *Params) { p.Memory = 32768 }, want: true},
+		{name: "fewer iterations", modify: func(p *Params) { p.Iterations = 2 }, want: true},
+		{name: "shorter salt", modify: func(p *Params) { p.SaltLength = 8 }, want: true},
+		{name: "shorter key", modify: func(p *Params) { p.KeyLength = 16 }, want: true},
+		{name: "legacy version", modify: func(p *Params) { p.Version = 0x10 }, want: true},
+		{name: "stronger", modify: func(p *Params) { p.Memory, p.Iterations = 131072, 4 }, want: false},
+		{name: "other parallelism", modify: func(p *Params) { p.Parallelism = 1 }, want: false},
+		{name: "other variant", modify: func(p *Params) { p.Variant = Argon2i }, want: false},
+		{name: "less memory, more iterations", modify: func(p *Params) { p.Memory, p.Iterations = 32768, 6 }, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := base
+			tt.modify(&p)
+			if got := p.WeakerThan(&base); got != tt.want {
+				t.Errorf("WeakerThan() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package argon2
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+)
+
+// ErrPasswordTooShort is returned when a password to hash is shorter than
+// the minimum length.
+var ErrPasswordTooShort = errors.New("argon2: the password is too short")
+
+// DefaultMaxPasswordLength is the default maximum length of the passwords,
+// in bytes, well above the longest passphrases while bounding the work an
+// attacker can cause with oversized inputs.
+const DefaultMaxPasswordLength = 4096
+
+// maxPasswordLength and minPasswordLength are the limits set with
+// SetMaxPasswordLength and SetMinPasswordLength.
+var maxPasswordLength, minPasswordLength atomic.Int64
+
+func init() {
+	maxPasswordLength.Store(DefaultMaxPasswordLength)
+}
+
+// PasswordLengthError is returned when a password is rejected by its
+// length, with the limit it violates. It wraps ErrPasswordTooLong or
+// ErrPasswordTooShort.
+type PasswordLengthError struct {
+	Length int   // The length of the password, in bytes
+	Limit  int   // The limit it violates, in bytes
+	Err    error // ErrPasswordTooLong or ErrPasswordTooShort
+}
+
+// Error implements the error interface.
+func (e *PasswordLengthError) Error() string {
+	return e.Err.Error() + ": " + strconv.Itoa(e.Length) + " bytes, the limit is " + strconv.Itoa(e.Limit)
+}
+
+// Unwrap returns the underlying error, so it can be matched with errors.Is.
+func (e *PasswordLengthError) Unwrap() error {
+	return e.Err
+}
+
+// SetMaxPasswordLength sets the maximum length of the passwords, in bytes,
+// that are hashed or verified, safely for concurrent use. Longer passwords
+// are rejected with a PasswordLengthError wrapping ErrPasswordTooLong before
+// any key is derived. The limit defaults to DefaultMaxPasswordLength, and
+// n <= 0 removes it.
+func SetMaxPasswordLength(n int) {
+	maxPasswordLength.Store(int64(n))
+}
+
+// SetMinPasswordLength sets the minimum length of the passwords, in bytes,
+// that are hashed, safely for concurrent use, so a misbehaving frontend
+// can't store hashes of empty or too short passwords. Shorter passwords are
+// rejected with a PasswordLengthError wrapping ErrPasswordTooShort, e.g.
+// with n = 1 for the empty ones. Verifying passwords isn't affected, so
+// the existing hashes keep working. There is no minimum by default, and
+// n <= 0 removes it.
+func SetMinPasswordLength(n int) {
+	minPasswordLength.Store(int64(n))
+}
+
+// checkNewPassword returns an error if the password to hash violates the
+// length limits, including the minimum one, or the installed policy.
+func checkNewPassword(password []byte) error {
+	if min := minPasswordLength.Load(); int64(len(password)) < min {
+		return &PasswordLengthError{Length: len(password), Limit: int(min), Err: ErrPasswordTooShort}
+	}
+	if err := checkPassword(password); err != nil {
+		return err
+	}
+	return checkPolicy(password)
+}
+
+// checkPassword returns an error if the password violates the length limits.
+func checkPassword(password []byte) error {
+	if max := maxPasswordLength.Load(); max > 0 && int64(len(password)) > max {
+		return &PasswordLengthError{Length: len(password), Limit: int(max), Err: ErrPasswordTooLong}
+	}
+	return nil
+}
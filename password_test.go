@@ -0,0 +1,127 @@
+package argon2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSetMaxPasswordLength(t *testing.T) {
+	defer SetMaxPasswordLength(DefaultMaxPasswordLength)
+
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hash := MustGenerateFromPassword([]byte("password"), p)
+
+	tests := []struct {
+		name     string
+		max      int
+		password []byte
+		wantErr  bool
+	}{
+		{name: "default limit", max: DefaultMaxPasswordLength, password: bytes.Repeat([]byte("a"), DefaultMaxPasswordLength)},
+		{name: "over the default limit", max: DefaultMaxPasswordLength, password: bytes.Repeat([]byte("a"), DefaultMaxPasswordLength+1), wantErr: true},
+		{name: "custom limit", max: 8, password: []byte("password")},
+		{name: "over the custom limit", max: 7, password: []byte("password"), wantErr: true},
+		{name: "no limit", max: 0, password: bytes.Repeat([]byte("a"), 2*DefaultMaxPasswordLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMaxPasswordLength(tt.max)
+
+			_, err := GenerateFromPassword(tt.password, p)
+			checkPasswordLengthError(t, "GenerateFromPassword()", err, tt.wantErr, len(tt.password), tt.max)
+
+			err = CompareHashAndPassword(hash, tt.password)
+			if !tt.wantErr && err == ErrMismatchedHashAndPassword {
+				err = nil
+			}
+			checkPasswordLengthError(t, "CompareHashAndPassword()", err, tt.wantErr, len(tt.password), tt.max)
+		})
+	}
+}
+
+func checkPasswordLengthError(t *testing.T, name string, err error, wantErr bool, length, limit int) {
+	t.Helper()
+
+	if !wantErr {
+		if err != nil {
+			t.Errorf("%s error = %v, want nil", name, err)
+		}
+		return
+	}
+
+	var le *PasswordLengthError
+	if !errors.As(err, &le) || !errors.Is(err, ErrPasswordTooLong) {
+		t.Fatalf("%s error = %v, want a PasswordLengthError", name, err)
+	}
+	if le.Length != length || le.Limit != limit {
+		t.Errorf("%s error = %+v, want length %d and limit %d", name, le, length, limit)
+	}
+}
+
+func TestSetMinPasswordLength(t *testing.T) {
+	defer SetMinPasswordLength(0)
+
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	emptyHash := MustGenerateFromPassword(nil, p)
+
+	tests := []struct {
+		name     string
+		min      int
+		password []byte
+		wantErr  bool
+	}{
+		{name: "no minimum", min: 0, password: nil},
+		{name: "empty password", min: 1, password: nil, wantErr: true},
+		{name: "short password", min: 12, password: []byte("password"), wantErr: true},
+		{name: "long enough password", min: 8, password: []byte("password")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMinPasswordLength(tt.min)
+
+			_, err := GenerateFromPassword(tt.password, p)
+			if tt.wantErr {
+				var le *PasswordLengthError
+				if !errors.As(err, &le) || !errors.Is(err, ErrPasswordTooShort) || le.Limit != tt.min {
+					t.Errorf("GenerateFromPassword() error = %v, want a PasswordLengthError with limit %d", err, tt.min)
+				}
+			} else if err != nil {
+				t.Errorf("GenerateFromPassword() error = %v", err)
+			}
+		})
+	}
+
+	// verifying and rehashing existing hashes isn't affected
+	SetMinPasswordLength(1)
+	if err := CompareHashAndPassword(emptyHash, nil); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+	stronger := *p
+	stronger.Iterations = 2
+	if newHash, err := CompareAndUpdate(emptyHash, nil, &stronger); err != nil || newHash != nil {
+		t.Errorf("CompareAndUpdate() got = %s, error = %v, want nil, nil", newHash, err)
+	}
+}
+
+func TestSetMinPasswordLength_Derived(t *testing.T) {
+	defer SetMinPasswordLength(0)
+	SetMinPasswordLength(64)
+
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	// the minimum applies to the password, not to its 32 bytes HMAC
+	hash, err := GenerateFromPasswordWithPepper(bytes.Repeat([]byte("a"), 64), []byte("pepper"), p)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithPepper() error = %v", err)
+	}
+	if err := CompareHashAndPasswordWithPepper(hash, bytes.Repeat([]byte("a"), 64), []byte("pepper")); err != nil {
+		t.Errorf("CompareHashAndPasswordWithPepper() error = %v", err)
+	}
+
+	// legacy passwords below the minimum keep their hash on migration
+	v := VerifierFunc(func(hash, password []byte) error { return nil })
+	if newHash, err := VerifyAndMigrateWith(v, []byte("legacy"), []byte("short"), p); err != nil || newHash != nil {
+		t.Errorf("VerifyAndMigrateWith() got = %s, error = %v, want nil, nil", newHash, err)
+	}
+}
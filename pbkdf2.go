@@ -0,0 +1,177 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"strconv"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// djangoPBKDF2PRFs maps the algorithm identifiers of Django's PBKDF2
+// hashers to their pseudorandom functions.
+var djangoPBKDF2PRFs = map[string]func() hash.Hash{
+	"pbkdf2_sha256": sha256.New,
+	"pbkdf2_sha1":   sha1.New,
+}
+
+// aspNetPBKDF2PRFs are the pseudorandom functions of ASP.NET Core Identity's
+// KeyDerivationPrf enumeration.
+var aspNetPBKDF2PRFs = []func() hash.Hash{sha1.New, sha256.New, sha512.New}
+
+// ASP.NET Identity's version 2 hashes use PBKDF2 with HMAC-SHA1, 1000
+// iterations, a 16 byte salt and a 32 byte key.
+const (
+	aspNetV2Iterations = 1000
+	aspNetV2SaltLength = 16
+	aspNetV2KeyLength  = 32
+)
+
+// PBKDF2Verifier verifies legacy PBKDF2 hashes of custom schemes, which
+// VerifyAndMigrate doesn't recognize, stored as "salt$key". It can be passed
+// to VerifyAndMigrateWith to migrate them to argon2.
+type PBKDF2Verifier struct {
+	Prefix     string           // Stripped from the hash before decoding it, e.g. "{PBKDF2}"
+	PRF        func() hash.Hash // The hash function of the HMAC pseudorandom function, e.g. sha256.New
+	Iterations int              // The number of iterations
+	Encoding   *base64.Encoding // Encoding of the salt and key. Hex is used if nil
+}
+
+// Verify compares the hash with the password. It returns nil on success,
+// ErrMismatchedHashAndPassword if they don't match, and ErrInvalidHash if
+// the hash is malformed.
+func (v *PBKDF2Verifier) Verify(hash, password []byte) error {
+	if v.PRF == nil || v.Iterations < 1 {
+		return ErrInvalidParams
+	}
+	if !bytes.HasPrefix(hash, []byte(v.Prefix)) {
+		return ErrInvalidHash
+	}
+
+	i := bytes.IndexByte(hash[len(v.Prefix):], '$')
+	if i < 0 {
+		return ErrInvalidHash
+	}
+	encSalt, encKey := hash[len(v.Prefix):len(v.Prefix)+i], hash[len(v.Prefix)+i+1:]
+
+	decode := hex.DecodeString
+	if v.Encoding != nil {
+		decode = v.Encoding.DecodeString
+	}
+	salt, err := decode(string(encSalt))
+	if err != nil {
+		return ErrInvalidHash
+	}
+	key, err := decode(string(encKey))
+	if err != nil || len(key) == 0 {
+		return ErrInvalidHash
+	}
+
+	return comparePBKDF2(password, salt, key, v.Iterations, v.PRF)
+}
+
+// isDjangoPBKDF2 reports whether the hash is one of Django's PBKDF2 hashers,
+// e.g. "pbkdf2_sha256$iterations$salt$key".
+func isDjangoPBKDF2(hash []byte) bool {
+	i := bytes.IndexByte(hash, '$')
+	if i < 0 {
+		return false
+	}
+	_, ok := djangoPBKDF2PRFs[string(hash[:i])]
+	return ok
+}
+
+// compareDjangoPBKDF2 compares a hash of Django's PBKDF2 hashers with the
+// password. The salt is used as is, and the key is base64 encoded.
+func compareDjangoPBKDF2(hash, password []byte) error {
+	vals := bytes.Split(hash, []byte("$"))
+	if len(vals) != 4 {
+		return ErrInvalidHash
+	}
+
+	prf, ok := djangoPBKDF2PRFs[string(vals[0])]
+	if !ok {
+		return ErrUnknownAlgorithm
+	}
+	iterations, err := strconv.ParseUint(string(vals[1]), 10, 31)
+	if err != nil || iterations < 1 {
+		return ErrInvalidHash
+	}
+	if iterations > maxLegacyPBKDF2Iterations {
+		return limitError("iterations", iterations, maxLegacyPBKDF2Iterations)
+	}
+	key, err := base64.StdEncoding.DecodeString(string(vals[3]))
+	if err != nil || len(key) == 0 {
+		return ErrInvalidHash
+	}
+
+	return comparePBKDF2(password, vals[2], key, int(iterations), prf)
+}
+
+// isASPNetIdentity reports whether the hash looks like an ASP.NET Identity
+// password hash: the base64 encoding of a format marker, 0x00 for the
+// version 2 format and 0x01 for the version 3 one, followed by the salt and
+// key, and for version 3 by the PRF, iterations and salt length first.
+func isASPNetIdentity(hash []byte) bool {
+	// 0x01 followed by the big endian PRF, always lower than 256
+	if bytes.HasPrefix(hash, []byte("AQAAAA")) {
+		return true
+	}
+	return len(hash) == base64.StdEncoding.EncodedLen(1+aspNetV2SaltLength+aspNetV2KeyLength) && hash[0] == 'A' && hash[1] < 'Q'
+}
+
+// compareASPNetIdentity compares an ASP.NET Identity password hash, of the
+// version 2 or 3 format, with the password.
+func compareASPNetIdentity(hash, password []byte) error {
+	raw, err := base64.StdEncoding.DecodeString(string(hash))
+	if err != nil || len(raw) == 0 {
+		return ErrInvalidHash
+	}
+
+	switch raw[0] {
+	case 0x00:
+		if len(raw) != 1+aspNetV2SaltLength+aspNetV2KeyLength {
+			return ErrInvalidHash
+		}
+		salt, key := raw[1:1+aspNetV2SaltLength], raw[1+aspNetV2SaltLength:]
+		return comparePBKDF2(password, salt, key, aspNetV2Iterations, sha1.New)
+	case 0x01:
+		if len(raw) < 13 {
+			return ErrInvalidHash
+		}
+		prf := binary.BigEndian.Uint32(raw[1:])
+		iterations := binary.BigEndian.Uint32(raw[5:])
+		saltLength := binary.BigEndian.Uint32(raw[9:])
+		if prf >= uint32(len(aspNetPBKDF2PRFs)) {
+			return ErrUnknownAlgorithm
+		}
+		if iterations < 1 || uint64(saltLength) >= uint64(len(raw)-13) {
+			return ErrInvalidHash
+		}
+		if iterations > maxLegacyPBKDF2Iterations {
+			return limitError("iterations", uint64(iterations), maxLegacyPBKDF2Iterations)
+		}
+		salt, key := raw[13:13+saltLength], raw[13+saltLength:]
+		return comparePBKDF2(password, salt, key, int(iterations), aspNetPBKDF2PRFs[prf])
+	default:
+		return ErrIncompatibleVersion
+	}
+}
+
+// comparePBKDF2 derives a key of the same length as key from the password
+// and salt, and compares it with key in constant time.
+func comparePBKDF2(password, salt, key []byte, iterations int, prf func() hash.Hash) error {
+	otherKey := pbkdf2.Key(password, salt, iterations, len(key), prf)
+	if subtle.ConstantTimeCompare(key, otherKey) == 1 {
+		return nil
+	}
+
+	return ErrMismatchedHashAndPassword
+}
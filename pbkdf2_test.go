@@ -0,0 +1,161 @@
+package argon2
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"testing"
+)
+
+func TestPBKDF2Verifier_Verify(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier *PBKDF2Verifier
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			name:     "hex",
+			verifier: &PBKDF2Verifier{PRF: sha512.New, Iterations: 5000},
+			hash:     []byte("6173706e65742d73616c742d30303136$db71aa462f38b95bff85a4b06ba1ab94717ca0e038e9bbb5550bc73009910504b6d18e2add36ad02a53b93c6092a5c3e79a3857a72f11956a41822a118438699"),
+			password: []byte("pbkdf2-password"),
+		},
+		{
+			name:     "prefixed url safe base64",
+			verifier: &PBKDF2Verifier{Prefix: "{PBKDF2-SHA512}", PRF: sha512.New, Iterations: 5000, Encoding: base64.RawURLEncoding},
+			hash:     []byte("{PBKDF2-SHA512}YXNwbmV0LXNhbHQtMDAxNg$23GqRi84uVv_haSwa6GrlHF8oOA46bu1VQvHMAmRBQS20Y4q3TatAqU7k8YJKlw-eaOFenLxGVakGCKhGEOGmQ"),
+			password: []byte("pbkdf2-password"),
+		},
+		{
+			name:     "wrong prf",
+			verifier: &PBKDF2Verifier{PRF: sha256.New, Iterations: 5000},
+			hash:     []byte("6173706e65742d73616c742d30303136$db71aa462f38b95bff85a4b06ba1ab94717ca0e038e9bbb5550bc73009910504b6d18e2add36ad02a53b93c6092a5c3e79a3857a72f11956a41822a118438699"),
+			password: []byte("pbkdf2-password"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "missing prefix",
+			verifier: &PBKDF2Verifier{Prefix: "{PBKDF2-SHA512}", PRF: sha512.New, Iterations: 5000, Encoding: base64.RawURLEncoding},
+			hash:     []byte("YXNwbmV0LXNhbHQtMDAxNg$23GqRi84uVv_haSwa6GrlHF8oOA46bu1VQvHMAmRBQS20Y4q3TatAqU7k8YJKlw-eaOFenLxGVakGCKhGEOGmQ"),
+			password: []byte("pbkdf2-password"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "missing key",
+			verifier: &PBKDF2Verifier{PRF: sha512.New, Iterations: 5000},
+			hash:     []byte("6173706e65742d73616c742d30303136"),
+			password: []byte("pbkdf2-password"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "missing iterations",
+			verifier: &PBKDF2Verifier{PRF: sha512.New},
+			hash:     []byte("6173706e65742d73616c742d30303136$db71aa462f38b95bff85a4b06ba1ab94717ca0e038e9bbb5550bc73009910504b6d18e2add36ad02a53b93c6092a5c3e79a3857a72f11956a41822a118438699"),
+			password: []byte("pbkdf2-password"),
+			wantErr:  ErrInvalidParams,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.verifier.Verify(tt.hash, tt.password); err != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompareDjangoPBKDF2(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			name:     "pbkdf2_sha256",
+			hash:     []byte("pbkdf2_sha256$1000$djangosalt123456$QSt1DbmULoHfp4LOVhZ+jIEQsbsweVrWOSj+Mr/RM7A="),
+			password: []byte("pbkdf2-password"),
+		},
+		{
+			name:     "pbkdf2_sha1",
+			hash:     []byte("pbkdf2_sha1$1000$djangosalt123456$ukIgpvZlJKklGa4i05F4GJM3b3A="),
+			password: []byte("pbkdf2-password"),
+		},
+		{
+			name:     "mismatched password",
+			hash:     []byte("pbkdf2_sha256$1000$djangosalt123456$QSt1DbmULoHfp4LOVhZ+jIEQsbsweVrWOSj+Mr/RM7A="),
+			password: []byte("pbkdf2-password1"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "invalid iterations",
+			hash:     []byte("pbkdf2_sha256$0$djangosalt123456$QSt1DbmULoHfp4LOVhZ+jIEQsbsweVrWOSj+Mr/RM7A="),
+			password: []byte("pbkdf2-password"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !isDjangoPBKDF2(tt.hash) {
+				t.Fatalf("isDjangoPBKDF2() = false, want true")
+			}
+			if err := compareDjangoPBKDF2(tt.hash, tt.password); err != tt.wantErr {
+				t.Errorf("compareDjangoPBKDF2() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompareASPNetIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			name:     "v3 sha256",
+			hash:     []byte("AQAAAAEAACcQAAAAEGFzcG5ldC1zYWx0LTAwMTa/rguuc7ASvOnm/m8zTWUEDyUiwvucM9J1/wrfStMYOQ=="),
+			password: []byte("pbkdf2-password"),
+		},
+		{
+			name:     "v3 sha512",
+			hash:     []byte("AQAAAAIAAAPoAAAAEGFzcG5ldC1zYWx0LTAwMTZt1lfnT8q7X6XjlumRVhlkinirC0/bScdAsanI1xISBQ=="),
+			password: []byte("pbkdf2-password"),
+		},
+		{
+			name:     "v2",
+			hash:     []byte("AGFzcG5ldC1zYWx0LTAwMTY9rS6Ta98O+kc3dFAYUxCSi4o7cfv4DV8woeISyZsnjg=="),
+			password: []byte("pbkdf2-password"),
+		},
+		{
+			name:     "mismatched password",
+			hash:     []byte("AQAAAAEAACcQAAAAEGFzcG5ldC1zYWx0LTAwMTa/rguuc7ASvOnm/m8zTWUEDyUiwvucM9J1/wrfStMYOQ=="),
+			password: []byte("pbkdf2-password1"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "unknown prf",
+			hash:     []byte("AQAAAAMAAAPoAAAAEGFzcG5ldC1zYWx0LTAwMTZt1lfnT8q7X6XjlumRVhlkinirC0/bScdAsanI1xISBQ=="),
+			password: []byte("pbkdf2-password"),
+			wantErr:  ErrUnknownAlgorithm,
+		},
+		{
+			name:     "salt longer than hash",
+			hash:     []byte("AQAAAAIAAAPoAAABEGFzcG5ldC1zYWx0LTAwMTZt1lfnT8q7X6XjlumRVhlkinirC0/bScdAsanI1xISBQ=="),
+			password: []byte("pbkdf2-password"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !isASPNetIdentity(tt.hash) {
+				t.Fatalf("isASPNetIdentity() = false, want true")
+			}
+			if err := compareASPNetIdentity(tt.hash, tt.password); err != tt.wantErr {
+				t.Errorf("compareASPNetIdentity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package argon2
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"time"
+)
+
+// GenerateFromPasswordWithPepper is like GenerateFromPassword, but hashes
+// the HMAC-SHA256 of the password keyed with the pepper, an application
+// secret held outside of the database, e.g. in a secret manager. The pepper
+// isn't part of the encoded hash, and the same pepper must be passed to
+// CompareHashAndPasswordWithPepper to verify it. An empty pepper hashes the
+// password as is. A pepper can't be combined with a Normalization, which is
+// rejected with ErrInvalidParams.
+func GenerateFromPasswordWithPepper(password, pepper []byte, p *Params) ([]byte, error) {
+	if len(pepper) > 0 {
+		if err := checkPeppered(orDefault(p)); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkNewPassword(password); err != nil {
+		return nil, err
+	}
+	peppered := applyPepper(password, pepper)
+	defer wipePeppered(peppered, password)
+
+	return appendDerived(nil, peppered, nil, p)
+}
+
+// CompareHashAndPasswordWithPepper is like CompareHashAndPassword, but for
+// hashes generated with a pepper by GenerateFromPasswordWithPepper.
+func CompareHashAndPasswordWithPepper(hash, password, pepper []byte) error {
+	defer padVerify(time.Now())
+
+	if err := checkPassword(password); err != nil {
+		return err
+	}
+	peppered := applyPepper(password, pepper)
+	defer wipePeppered(peppered, password)
+
+	return CompareHashAndPassword(hash, peppered)
+}
+
+// applyPepper returns the HMAC-SHA256 of the password keyed with the
+// pepper, or the password itself if the pepper is empty.
+func applyPepper(password, pepper []byte) []byte {
+	if len(pepper) == 0 {
+		return password
+	}
+
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write(password)
+	return mac.Sum(nil)
+}
+
+// wipePeppered clears the peppered password, unless it is the password
+// itself, which belongs to the caller.
+func wipePeppered(peppered, password []byte) {
+	if len(peppered) > 0 && len(password) > 0 && &peppered[0] == &password[0] {
+		return
+	}
+	wipe(peppered)
+}
@@ -0,0 +1,78 @@
+package argon2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGenerateFromPasswordWithPepper(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	pepper := []byte("application-pepper")
+	password := []byte("password")
+
+	hash, err := GenerateFromPasswordWithPepper(password, pepper, p)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithPepper() error = %v", err)
+	}
+	if !bytes.Equal(password, []byte("password")) {
+		t.Errorf("GenerateFromPasswordWithPepper() modified the password: %q", password)
+	}
+
+	tests := []struct {
+		name     string
+		password []byte
+		pepper   []byte
+		wantErr  error
+	}{
+		{name: "same pepper", password: password, pepper: pepper},
+		{name: "other pepper", password: password, pepper: []byte("other-pepper"), wantErr: ErrMismatchedHashAndPassword},
+		{name: "no pepper", password: password, wantErr: ErrMismatchedHashAndPassword},
+		{name: "wrong password", password: []byte("wrong"), pepper: pepper, wantErr: ErrMismatchedHashAndPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPasswordWithPepper(hash, tt.password, tt.pepper); err != tt.wantErr {
+				t.Errorf("CompareHashAndPasswordWithPepper() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// an empty pepper hashes the password as is, and leaves it untouched
+	plain, err := GenerateFromPasswordWithPepper(password, nil, p)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithPepper() error = %v", err)
+	}
+	if err := CompareHashAndPassword(plain, []byte("password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+	if !bytes.Equal(password, []byte("password")) {
+		t.Errorf("GenerateFromPasswordWithPepper() modified the password: %q", password)
+	}
+}
+
+func TestHasher_WithPepper(t *testing.T) {
+	pepper := []byte("application-pepper")
+	h, err := NewHasher(WithMemory(8*1024), WithIterations(1), WithPepper(pepper))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+
+	hash, err := h.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if err := h.Verify(hash, []byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := CompareHashAndPasswordWithPepper(hash, []byte("password"), pepper); err != nil {
+		t.Errorf("CompareHashAndPasswordWithPepper() error = %v", err)
+	}
+
+	optHash, err := HashPassword([]byte("password"), WithMemory(8*1024), WithIterations(1), WithPepper(pepper))
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if err := h.Verify(optHash, []byte("password")); err != nil {
+		t.Errorf("Verify() of HashPassword() error = %v", err)
+	}
+}
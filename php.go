@@ -0,0 +1,25 @@
+package argon2
+
+// PHPParams provides the defaults of PHP's password_hash() with
+// PASSWORD_ARGON2ID: PASSWORD_ARGON2_DEFAULT_MEMORY_COST, _TIME_COST and
+// _THREADS. Hashes generated with them are encoded in the PHC format, so
+// password_verify() accepts them, and password_needs_rehash() doesn't
+// flag them as long as PHP uses its default options.
+//
+// PHPParams is shared by every importer of the package, so mutating it is
+// deprecated: use PHP to get a copy instead.
+var PHPParams = PHP()
+
+// PHP returns a copy of the defaults of PHP's password_hash() with
+// PASSWORD_ARGON2ID, as described by PHPParams, which the caller is free
+// to modify.
+func PHP() *Params {
+	return &Params{
+		Memory:      64 * 1024,
+		Iterations:  4,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+		Format:      FormatPHC,
+	}
+}
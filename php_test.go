@@ -0,0 +1,59 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestPHPCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  bool
+	}{
+		{
+			// password_hash() with PASSWORD_ARGON2ID and default options
+			name:     "argon2id defaults",
+			hash:     []byte("$argon2id$v=19$m=65536,t=4,p=1$MDEyMzQ1Njc4OWFiY2RlZg$Fty/tRKyGd+tLe9h9oXC1cf/ITu5EqrZixTIyxkf+cc"),
+			password: []byte("rasmuslerdorf"),
+		},
+		{
+			// password_hash() with PASSWORD_ARGON2ID and PHP 7.2 default options
+			name:     "argon2id php 7.2 defaults",
+			hash:     []byte("$argon2id$v=19$m=1024,t=2,p=2$MDEyMzQ1Njc4OWFiY2RlZg$qW8mv1OwFRYyNKFTEEYdzKvWluI2frkwFUKK5rV0rG0"),
+			password: []byte("rasmuslerdorf"),
+		},
+		{
+			// password_hash() with PASSWORD_ARGON2I and default options
+			name:     "argon2i defaults",
+			hash:     []byte("$argon2i$v=19$m=65536,t=4,p=1$MDEyMzQ1Njc4OWFiY2RlZg$Rd3vKXCvKA+pOFPwn2ts2HqYGqruMpI2k6NdnoMpGp4"),
+			password: []byte("rasmuslerdorf"),
+		},
+		{
+			name:     "wrong password",
+			hash:     []byte("$argon2id$v=19$m=65536,t=4,p=1$MDEyMzQ1Njc4OWFiY2RlZg$Fty/tRKyGd+tLe9h9oXC1cf/ITu5EqrZixTIyxkf+cc"),
+			password: []byte("rasmuslerdorf1"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPassword(tt.hash, tt.password); (err != nil) != tt.wantErr {
+				t.Errorf("CompareHashAndPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPHPParams(t *testing.T) {
+	hash, err := GenerateFromPassword([]byte("rasmuslerdorf"), PHP())
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	// password_verify() requires the PHC format with the parameters in the "m,t,p" order
+	const prefix = "$argon2id$v=19$m=65536,t=4,p=1$"
+	if string(hash[:len(prefix)]) != prefix {
+		t.Errorf("GenerateFromPassword() got = %s, want prefix %s", hash, prefix)
+	}
+}
@@ -0,0 +1,188 @@
+package argon2
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrPolicyViolation is returned when a password to hash violates the
+// password policy.
+var ErrPolicyViolation = errors.New("argon2: the password violates the policy")
+
+// PolicyError is returned by PasswordRules for a password violating one of
+// its rules. It wraps ErrPolicyViolation.
+type PolicyError struct {
+	Rule string // The violated rule, e.g. "min length" or "denylist"
+}
+
+// Error implements the error interface.
+func (e *PolicyError) Error() string {
+	return ErrPolicyViolation.Error() + ": " + e.Rule
+}
+
+// Unwrap returns ErrPolicyViolation, so it can be matched with errors.Is.
+func (e *PolicyError) Unwrap() error {
+	return ErrPolicyViolation
+}
+
+// Policy decides whether a password may be hashed, e.g. enforcing the
+// password rules of an organization at the same place the passwords are
+// hashed. It is invoked before hashing, not when verifying, so the existing
+// hashes keep working.
+type Policy interface {
+	// Check returns an error if the password violates the policy.
+	Check(password []byte) error
+}
+
+// PolicyFunc is an adapter to allow the use of ordinary functions as
+// a Policy, e.g. for custom rules.
+type PolicyFunc func(password []byte) error
+
+// Check calls f(password).
+func (f PolicyFunc) Check(password []byte) error {
+	return f(password)
+}
+
+// Policies is a Policy composed of several ones, e.g. PasswordRules and
+// a PolicyFunc with custom rules. The violations of all of them are
+// reported, joined as Params.Check does.
+type Policies []Policy
+
+// Check implements the Policy interface.
+func (ps Policies) Check(password []byte) error {
+	var errs []error
+	for _, p := range ps {
+		if err := p.Check(password); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// PasswordRules is the default Policy, enforcing the length, character
+// classes and denylist of the passwords. Every violation is reported as a
+// PolicyError, joined as Params.Check does. Zero fields aren't enforced.
+type PasswordRules struct {
+	MinLength     int      // The minimum number of characters
+	MaxLength     int      // The maximum number of characters
+	RequireLower  bool     // Require a lowercase letter
+	RequireUpper  bool     // Require an uppercase letter
+	RequireDigit  bool     // Require a digit
+	RequireSymbol bool     // Require a character other than a letter or a digit
+	MinClasses    int      // The minimum number of classes among lowercase, uppercase, digits and symbols
+	Denylist      []string // Rejected passwords, e.g. common ones, compared case-insensitively
+}
+
+// Check implements the Policy interface.
+func (r *PasswordRules) Check(password []byte) error {
+	var errs []error
+
+	n := utf8.RuneCount(password)
+	if n < r.MinLength {
+		errs = append(errs, &PolicyError{Rule: "min length"})
+	}
+	if r.MaxLength > 0 && n > r.MaxLength {
+		errs = append(errs, &PolicyError{Rule: "max length"})
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, c := range string(password) {
+		switch {
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsDigit(c):
+			digit = true
+		case !unicode.IsLetter(c):
+			symbol = true
+		}
+	}
+	for _, class := range []struct {
+		required, present bool
+		rule              string
+	}{
+		{r.RequireLower, lower, "lowercase"},
+		{r.RequireUpper, upper, "uppercase"},
+		{r.RequireDigit, digit, "digit"},
+		{r.RequireSymbol, symbol, "symbol"},
+	} {
+		if class.required && !class.present {
+			errs = append(errs, &PolicyError{Rule: class.rule})
+		}
+	}
+	if classes := count(lower, upper, digit, symbol); classes < r.MinClasses {
+		errs = append(errs, &PolicyError{Rule: "character classes"})
+	}
+
+	for _, denied := range r.Denylist {
+		if strings.EqualFold(string(password), denied) {
+			errs = append(errs, &PolicyError{Rule: "denylist"})
+			break
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// count returns the number of true values.
+func count(values ...bool) int {
+	n := 0
+	for _, v := range values {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+// policyHolder holds the policy installed with SetPolicy.
+type policyHolder struct {
+	policy Policy
+}
+
+var passwordPolicy atomic.Pointer[policyHolder]
+
+// SetPolicy installs the policy every function hashing passwords enforces,
+// safely for concurrent use, rejecting the passwords violating it with its
+// errors. Verifying existing hashes isn't affected, and the passwords of
+// hashes needing a rehash that violate it keep their hash. Passing nil
+// removes it.
+func SetPolicy(p Policy) {
+	if p == nil {
+		passwordPolicy.Store(nil)
+		return
+	}
+	passwordPolicy.Store(&policyHolder{policy: p})
+}
+
+// WithPolicy has the password checked against the policy before hashing
+// it, in addition to the one installed with SetPolicy, if any.
+func WithPolicy(p Policy) Option {
+	return func(o *options) { o.passwordPolicy = p }
+}
+
+// checkNewPasswordWith is like checkNewPassword, but also checks the
+// password against the policy p set by WithPolicy, if any.
+func checkNewPasswordWith(password []byte, p Policy) error {
+	if err := checkNewPassword(password); err != nil {
+		return err
+	}
+	if p != nil {
+		return p.Check(password)
+	}
+	return nil
+}
+
+// checkPolicy returns the errors of the installed policy, if any, for the
+// password.
+func checkPolicy(password []byte) error {
+	if h := passwordPolicy.Load(); h != nil {
+		return h.policy.Check(password)
+	}
+	return nil
+}
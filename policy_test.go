@@ -0,0 +1,130 @@
+package argon2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPasswordRules_Check(t *testing.T) {
+	rules := &PasswordRules{
+		MinLength:    8,
+		MaxLength:    64,
+		RequireLower: true,
+		RequireDigit: true,
+		MinClasses:   3,
+		Denylist:     []string{"Passw0rd!"},
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		wantRules []string
+	}{
+		{name: "valid", password: "correct-horse-7"},
+		{name: "valid unicode", password: "größer-als-7"},
+		{name: "too short", password: "ab1-", wantRules: []string{"min length"}},
+		{name: "too long", password: string(bytes.Repeat([]byte("a1-"), 22)), wantRules: []string{"max length"}},
+		{name: "too short in characters", password: "äöü1-", wantRules: []string{"min length"}},
+		{name: "missing digit", password: "correct-horse", wantRules: []string{"digit", "character classes"}},
+		{name: "missing lowercase and classes", password: "12345678", wantRules: []string{"lowercase", "character classes"}},
+		{name: "denylisted", password: "passw0rd!", wantRules: []string{"denylist"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rules.Check([]byte(tt.password))
+			if len(tt.wantRules) == 0 {
+				if err != nil {
+					t.Errorf("Check() error = %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrPolicyViolation) {
+				t.Fatalf("Check() error = %v, want %v", err, ErrPolicyViolation)
+			}
+			for _, rule := range tt.wantRules {
+				if !containsRule(err, rule) {
+					t.Errorf("Check() error = %v, want rule %q", err, rule)
+				}
+			}
+		})
+	}
+}
+
+// containsRule reports whether err is or joins a PolicyError of the rule.
+func containsRule(err error, rule string) bool {
+	if pe, ok := err.(*PolicyError); ok {
+		return pe.Rule == rule
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			if containsRule(err, rule) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestSetPolicy(t *testing.T) {
+	defer SetPolicy(nil)
+
+	errNoPassword := errors.New("the password contains the word password")
+	SetPolicy(Policies{
+		&PasswordRules{MinLength: 8},
+		PolicyFunc(func(password []byte) error {
+			if bytes.Contains(bytes.ToLower(password), []byte("password")) {
+				return errNoPassword
+			}
+			return nil
+		}),
+	})
+
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	if _, err := GenerateFromPassword([]byte("Password"), p); !errors.Is(err, errNoPassword) {
+		t.Errorf("GenerateFromPassword() error = %v, want %v", err, errNoPassword)
+	}
+	if _, err := GenerateFromPassword([]byte("short"), p); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("GenerateFromPassword() error = %v, want %v", err, ErrPolicyViolation)
+	}
+	hash, err := GenerateFromPasswordWithPepper([]byte("correct horse"), []byte("pepper"), p)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithPepper() error = %v", err)
+	}
+	if _, err := GenerateFromPasswordWithPepper([]byte("short"), []byte("pepper"), p); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("GenerateFromPasswordWithPepper() error = %v, want %v", err, ErrPolicyViolation)
+	}
+
+	// verifying existing hashes isn't affected
+	SetPolicy(&PasswordRules{MinLength: 32})
+	if err := CompareHashAndPasswordWithPepper(hash, []byte("correct horse"), []byte("pepper")); err != nil {
+		t.Errorf("CompareHashAndPasswordWithPepper() error = %v", err)
+	}
+}
+
+func TestWithPolicy(t *testing.T) {
+	rules := &PasswordRules{RequireUpper: true}
+	opts := []Option{WithMemory(8 * 1024), WithIterations(1), WithPolicy(rules)}
+
+	if _, err := HashPassword([]byte("lowercase"), opts...); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("HashPassword() error = %v, want %v", err, ErrPolicyViolation)
+	}
+
+	h, err := NewHasher(opts...)
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	if _, err := h.Hash([]byte("lowercase")); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("Hash() error = %v, want %v", err, ErrPolicyViolation)
+	}
+	if _, err := h.Hash([]byte("Uppercase")); err != nil {
+		t.Errorf("Hash() error = %v", err)
+	}
+
+	// a password violating the policy keeps its hash rather than failing
+	weak := MustGenerateFromPassword([]byte("lowercase"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 16})
+	if newHash, err := h.CompareAndUpdate(weak, []byte("lowercase")); err != nil || newHash != nil {
+		t.Errorf("CompareAndUpdate() got = %s, error = %v, want nil, nil", newHash, err)
+	}
+}
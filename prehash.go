@@ -0,0 +1,122 @@
+package argon2
+
+import (
+	"crypto/sha512"
+	"strconv"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// PreHash identifies the function a password is pre-hashed with before
+// deriving its argon2 key, bounding the input of very long passphrases. It
+// is recorded in the PHC formats as the "ph" parameter, e.g.
+// "$argon2id$v=19$m=65536,t=3,p=2,ph=sha512$...", so the password is
+// pre-hashed the same way when verifying. The raw digest of the password is
+// used as the argon2 password, so other implementations can verify the
+// hashes by pre-hashing it themselves.
+type PreHash int
+
+const (
+	// NoPreHash uses the password as is.
+	NoPreHash PreHash = iota
+
+	// PreHashSHA512 pre-hashes the password with SHA-512.
+	PreHashSHA512
+
+	// PreHashBLAKE2b pre-hashes the password with BLAKE2b-512.
+	PreHashBLAKE2b
+)
+
+// preHashNames maps the supported pre-hash functions to their identifiers.
+var preHashNames = map[PreHash]string{
+	PreHashSHA512:  "sha512",
+	PreHashBLAKE2b: "blake2b",
+}
+
+// String returns the identifier of the pre-hash function.
+func (h PreHash) String() string {
+	if h == NoPreHash {
+		return "none"
+	}
+	if name, ok := preHashNames[h]; ok {
+		return name
+	}
+	return "PreHash(" + strconv.Itoa(int(h)) + ")"
+}
+
+// parsePreHash returns the pre-hash function of the given identifier.
+// It returns ErrUnsupportedParams if the identifier is not supported.
+func parsePreHash(id []byte) (PreHash, error) {
+	for h, name := range preHashNames {
+		if name == string(id) {
+			return h, nil
+		}
+	}
+	return NoPreHash, ErrUnsupportedParams
+}
+
+// apply returns the digest of the password, or the password itself
+// without a pre-hash function.
+func (h PreHash) apply(password []byte) []byte {
+	switch h {
+	case PreHashSHA512:
+		sum := sha512.Sum512(password)
+		return sum[:]
+	case PreHashBLAKE2b:
+		sum := blake2b.Sum512(password)
+		return sum[:]
+	default:
+		return password
+	}
+}
+
+// WithPreHash pre-hashes the passwords longer than threshold bytes with h
+// before deriving their key, so very long passphrases, e.g. diceware ones,
+// are bounded before argon2. The pre-hash function is recorded in the hash,
+// which requires one of the PHC formats, e.g. WithFormat(FormatPHC). With a
+// pepper, the threshold applies to the peppered password.
+func WithPreHash(h PreHash, threshold int) Option {
+	return func(o *options) {
+		o.preHash = h
+		o.preHashThreshold = threshold
+	}
+}
+
+// preHashParams returns the parameters of the hash of the password, with
+// the pre-hash function h set if the password exceeds the threshold.
+func preHashParams(p *Params, h PreHash, threshold int, password []byte) *Params {
+	if h == NoPreHash || len(password) <= threshold {
+		return p
+	}
+	params := *p
+	params.PreHash = h
+	return &params
+}
+
+// preHashPolicy returns policy, also accepting the hashes pre-hashed with
+// h: whether a password is pre-hashed depends on its length, which isn't
+// known when deciding on a rehash.
+func preHashPolicy(policy RehashPolicy, h PreHash) RehashPolicy {
+	if h == NoPreHash {
+		return policy
+	}
+	return RehashPolicyFunc(func(hp *Params) bool {
+		if hp.PreHash == h {
+			params := *hp
+			params.PreHash = NoPreHash
+			hp = &params
+		}
+		return policy.NeedsRehash(hp)
+	})
+}
+
+// checkPreHash returns an error if the pre-hash function h is unsupported,
+// or can't be recorded in the format of the parameters.
+func checkPreHash(p *Params, h PreHash) error {
+	if h == NoPreHash {
+		return nil
+	}
+	params := *p
+	params.PreHash = h
+	return params.Check()
+}
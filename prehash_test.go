@@ -0,0 +1,142 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestWithPreHash(t *testing.T) {
+	passphrase := bytes.Repeat([]byte("correct horse battery staple "), 10)
+	sha := sha512.Sum512(passphrase)
+	b2 := blake2b.Sum512(passphrase)
+
+	tests := []struct {
+		name       string
+		preHash    PreHash
+		password   []byte
+		wantParam  string
+		wantDigest []byte
+	}{
+		{name: "sha512", preHash: PreHashSHA512, password: passphrase, wantParam: ",ph=sha512$", wantDigest: sha[:]},
+		{name: "blake2b", preHash: PreHashBLAKE2b, password: passphrase, wantParam: ",ph=blake2b$", wantDigest: b2[:]},
+		{name: "below threshold", preHash: PreHashSHA512, password: []byte("password")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := HashPassword(tt.password, WithMemory(8*1024), WithIterations(1), WithFormat(FormatPHC), WithPreHash(tt.preHash, 64))
+			if err != nil {
+				t.Fatalf("HashPassword() error = %v", err)
+			}
+			if tt.wantParam != "" && !bytes.Contains(hash, []byte(tt.wantParam)) {
+				t.Errorf("HashPassword() got = %s, want %s", hash, tt.wantParam)
+			}
+			if tt.wantParam == "" && bytes.Contains(hash, []byte(",ph=")) {
+				t.Errorf("HashPassword() got = %s, want no pre-hash", hash)
+			}
+
+			if err := CompareHashAndPassword(hash, tt.password); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+			if err := CompareHashAndPassword(hash, []byte("wrong")); err != ErrMismatchedHashAndPassword {
+				t.Errorf("CompareHashAndPassword() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+			}
+
+			// the pre-hashed key is the argon2 key of the raw digest
+			if tt.wantDigest != nil {
+				p, salt, key, err := DecodeHash(hash)
+				if err != nil {
+					t.Fatalf("DecodeHash() error = %v", err)
+				}
+				p.PreHash = NoPreHash
+				if err := VerifyRaw(tt.wantDigest, salt, key, p); err != nil {
+					t.Errorf("VerifyRaw() of the digest error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestWithPreHash_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "legacy format", opts: []Option{WithFormat(FormatLegacy), WithPreHash(PreHashSHA512, 64)}, wantErr: ErrUnsupportedFormat},
+		{name: "unknown function", opts: []Option{WithFormat(FormatPHC), WithPreHash(PreHash(42), 64)}, wantErr: ErrUnsupportedParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := HashPassword([]byte("password"), tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("HashPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := NewHasher(tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewHasher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeHash_PreHash(t *testing.T) {
+	tests := []struct {
+		name      string
+		hash      string
+		want      PreHash
+		wantField string
+	}{
+		{name: "sha512", hash: "$argon2id$v=19$m=65536,t=3,p=2,ph=sha512$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck", want: PreHashSHA512},
+		{name: "unknown function", hash: "$argon2id$v=19$m=65536,t=3,p=2,ph=md5$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck", wantField: "ph"},
+		{name: "repeated", hash: "$argon2id$v=19$m=65536,t=3,p=2,ph=sha512,ph=sha512$y9Mjl5CpHgKbRjloFZ5Agg$OuEhb6CmIeCMC3Jx3RgJFoeUSwo7S9OTrq20pFW/Fck", wantField: "ph"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _, _, err := DecodeHash([]byte(tt.hash))
+			if tt.wantField != "" {
+				var he *HashError
+				if !errors.As(err, &he) || he.Field != tt.wantField {
+					t.Errorf("DecodeHash() error = %v, want a HashError of field %q", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeHash() error = %v", err)
+			}
+			if p.PreHash != tt.want {
+				t.Errorf("DecodeHash() PreHash = %v, want %v", p.PreHash, tt.want)
+			}
+			if got := EncodeHash(p, nil, nil); !bytes.Contains(got, []byte(",ph=sha512$")) {
+				t.Errorf("EncodeHash() got = %s, want ph=sha512", got)
+			}
+		})
+	}
+}
+
+func TestHasher_NeedsRehash_PreHash(t *testing.T) {
+	h, err := NewHasher(WithMemory(8*1024), WithIterations(1), WithFormat(FormatPHC), WithPreHash(PreHashSHA512, 64))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+
+	for _, password := range [][]byte{[]byte("password"), bytes.Repeat([]byte("correct horse battery staple "), 10)} {
+		hash, err := h.Hash(password)
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+		if rehash, err := h.NeedsRehash(hash); err != nil || rehash {
+			t.Errorf("NeedsRehash(%s) = %v, %v, want false", hash, rehash, err)
+		}
+	}
+
+	// other pre-hash functions are migrated
+	hash, err := HashPassword(bytes.Repeat([]byte("x"), 100), WithMemory(8*1024), WithIterations(1), WithFormat(FormatPHC), WithPreHash(PreHashBLAKE2b, 64))
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if rehash, err := h.NeedsRehash(hash); err != nil || !rehash {
+		t.Errorf("NeedsRehash(%s) = %v, %v, want true", hash, rehash, err)
+	}
+}
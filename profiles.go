@@ -0,0 +1,119 @@
+package argon2
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrUnknownTenant is returned when a tenant has no profile, and there is
+// no default profile either.
+var ErrUnknownTenant = errors.New("argon2: unknown tenant")
+
+// Profile is the hashing profile of a tenant: the parameters of its new
+// hashes, and the secret key mixed into them, if any.
+type Profile struct {
+	Params *Params // The parameters of new hashes
+	Secret []byte  // The argon2 secret key, as with GenerateFromPasswordWithSecret, if any
+}
+
+// Profiles selects the profile of a tenant, or realm, at hash and verify
+// time, e.g. for SaaS platforms where enterprise tenants demand higher costs
+// than the free tier. The profile of the empty tenant "" is the default one,
+// used for the tenants without a profile of their own. The zero value has no
+// profiles, and it is safe for concurrent use.
+//
+// Hashes record their parameters, so changing the parameters of a profile
+// doesn't affect the verification of existing hashes, while changing its
+// secret does: rotate secrets by verifying with the old one, and then
+// rehashing.
+type Profiles struct {
+	mu       sync.RWMutex
+	profiles map[string]Profile
+}
+
+// Set sets the profile of the tenant, or the default profile for the empty
+// tenant, after validating its parameters with Check. It returns
+// ErrInvalidParams if the profile has no parameters.
+func (m *Profiles) Set(tenant string, p Profile) error {
+	if p.Params == nil {
+		return ErrInvalidParams
+	}
+	if err := p.Params.Check(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.profiles == nil {
+		m.profiles = make(map[string]Profile)
+	}
+	m.profiles[tenant] = Profile{
+		Params: p.Params.Clone(),
+		Secret: append([]byte(nil), p.Secret...),
+	}
+
+	return nil
+}
+
+// Delete removes the profile of the tenant, if any.
+func (m *Profiles) Delete(tenant string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.profiles, tenant)
+}
+
+// Profile returns the profile of the tenant, or the default one if the
+// tenant has none. It returns ErrUnknownTenant if there is neither.
+func (m *Profiles) Profile(tenant string) (Profile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.profiles[tenant]
+	if !ok {
+		if p, ok = m.profiles[""]; !ok {
+			return Profile{}, ErrUnknownTenant
+		}
+	}
+
+	return Profile{Params: p.Params.Clone(), Secret: append([]byte(nil), p.Secret...)}, nil
+}
+
+// GenerateFromPassword returns the hash of the password generated with the
+// profile of the tenant.
+func (m *Profiles) GenerateFromPassword(tenant string, password []byte) ([]byte, error) {
+	p, err := m.Profile(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendHash(nil, password, p.Secret, p.Params)
+}
+
+// CompareHashAndPassword compares a hash of the tenant with the password,
+// using the secret of the tenant's profile.
+func (m *Profiles) CompareHashAndPassword(tenant string, hash, password []byte) error {
+	defer padVerify(time.Now())
+
+	p, err := m.Profile(tenant)
+	if err != nil {
+		return err
+	}
+
+	_, err = compareHashAndPassword(nil, hash, password, p.Secret)
+	return err
+}
+
+// CompareAndUpdate is like the package's CompareAndUpdate, with the
+// parameters and the secret of the tenant's profile.
+func (m *Profiles) CompareAndUpdate(tenant string, hash, password []byte) ([]byte, error) {
+	defer padVerify(time.Now())
+
+	p, err := m.Profile(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareAndUpdate(hash, password, p.Secret, p.Params, paramsPolicy(p.Params))
+}
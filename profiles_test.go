@@ -0,0 +1,87 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	var m Profiles
+	if _, err := m.GenerateFromPassword("acme", []byte("password")); err != ErrUnknownTenant {
+		t.Errorf("GenerateFromPassword() error = %v, wantErr %v", err, ErrUnknownTenant)
+	}
+
+	free := Profile{Params: &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}}
+	enterprise := Profile{
+		Params: &Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC},
+		Secret: []byte("acme-secret"),
+	}
+	if err := m.Set("", free); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := m.Set("acme", enterprise); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := m.Set("broken", Profile{Params: &Params{Memory: 1024}}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Set() error = %v, wantErr %v", err, ErrInvalidParams)
+	}
+	if err := m.Set("broken", Profile{}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Set() error = %v, wantErr %v", err, ErrInvalidParams)
+	}
+
+	tests := []struct {
+		name   string
+		tenant string
+		prefix string
+	}{
+		{name: "enterprise tenant", tenant: "acme", prefix: "$argon2id$v=19$m=32768,t=2,p=1$"},
+		{name: "default profile", tenant: "startup", prefix: "argon2id$19$8192$1$1$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := m.GenerateFromPassword(tt.tenant, []byte("password"))
+			if err != nil {
+				t.Fatalf("GenerateFromPassword() error = %v", err)
+			}
+			if string(hash[:len(tt.prefix)]) != tt.prefix {
+				t.Errorf("GenerateFromPassword() got = %s, want prefix %s", hash, tt.prefix)
+			}
+			if err := m.CompareHashAndPassword(tt.tenant, hash, []byte("password")); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+			if err := m.CompareHashAndPassword(tt.tenant, hash, []byte("passw0rd")); err != ErrMismatchedHashAndPassword {
+				t.Errorf("CompareHashAndPassword() error = %v, wantErr %v", err, ErrMismatchedHashAndPassword)
+			}
+		})
+	}
+
+	// A hash generated with the default profile is upgraded once the
+	// tenant gets its own profile.
+	hash, err := m.GenerateFromPassword("globex", []byte("password"))
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	if err := m.Set("globex", enterprise); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := m.CompareHashAndPassword("globex", hash, []byte("password")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareHashAndPassword() error = %v, want the pepper of the new profile", err)
+	}
+	m.Delete("globex")
+	newHash, err := m.CompareAndUpdate("globex", hash, []byte("password"))
+	if err != nil || newHash != nil {
+		t.Errorf("CompareAndUpdate() got = %s, error = %v, want no update", newHash, err)
+	}
+
+	weak, err := GenerateFromPasswordWithSecret([]byte("password"), enterprise.Secret, free.Params)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithSecret() error = %v", err)
+	}
+	newHash, err = m.CompareAndUpdate("acme", weak, []byte("password"))
+	if err != nil || newHash == nil {
+		t.Fatalf("CompareAndUpdate() got = %s, error = %v, want an update", newHash, err)
+	}
+	if err := m.CompareHashAndPassword("acme", newHash, []byte("password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+}
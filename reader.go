@@ -0,0 +1,62 @@
+package argon2
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrPasswordTooLong is returned when a password exceeds the length limit.
+var ErrPasswordTooLong = errors.New("argon2: the password is too long")
+
+// GenerateFromReader is like GenerateFromPassword, but reads the password
+// from r until EOF, e.g. from stdin, a socket or a decrypted file. It reads
+// at most limit bytes, and returns ErrPasswordTooLong if there are more.
+// The password is used as read, trailing newlines included, and the buffer
+// holding it is cleared before returning.
+func GenerateFromReader(r io.Reader, limit int, p *Params) ([]byte, error) {
+	password, err := readPassword(r, limit)
+	defer wipe(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return GenerateFromPassword(password, p)
+}
+
+// CompareHashAndReader is like CompareHashAndPassword, but reads the password
+// from r, like GenerateFromReader.
+func CompareHashAndReader(hash []byte, r io.Reader, limit int) error {
+	password, err := readPassword(r, limit)
+	defer wipe(password)
+	if err != nil {
+		return err
+	}
+
+	return CompareHashAndPassword(hash, password)
+}
+
+// readPassword reads at most limit bytes from r until EOF, returning
+// ErrPasswordTooLong if there are more.
+func readPassword(r io.Reader, limit int) ([]byte, error) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	buf := make([]byte, limit+1)
+	n, err := io.ReadFull(r, buf)
+	switch err {
+	case nil:
+		return buf, ErrPasswordTooLong
+	case io.EOF, io.ErrUnexpectedEOF:
+		return buf[:n], nil
+	default:
+		return buf, err
+	}
+}
+
+// wipe overwrites b with zeros.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,48 @@
+package argon2
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestGenerateFromReader(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	errRead := errors.New("read failure")
+
+	tests := []struct {
+		name    string
+		r       io.Reader
+		limit   int
+		wantErr error
+	}{
+		{name: "shorter than the limit", r: strings.NewReader("password"), limit: 64},
+		{name: "exactly the limit", r: strings.NewReader("password"), limit: 8},
+		{name: "one byte at a time", r: iotest.OneByteReader(strings.NewReader("password")), limit: 64},
+		{name: "longer than the limit", r: strings.NewReader("password"), limit: 7, wantErr: ErrPasswordTooLong},
+		{name: "read failure", r: iotest.ErrReader(errRead), limit: 64, wantErr: errRead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := GenerateFromReader(tt.r, tt.limit, p)
+			if err != tt.wantErr {
+				t.Fatalf("GenerateFromReader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if err := CompareHashAndPassword(hash, []byte("password")); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+			if err := CompareHashAndReader(hash, strings.NewReader("password"), 64); err != nil {
+				t.Errorf("CompareHashAndReader() error = %v", err)
+			}
+			if err := CompareHashAndReader(hash, strings.NewReader("password\n"), 64); err != ErrMismatchedHashAndPassword {
+				t.Errorf("CompareHashAndReader() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+			}
+		})
+	}
+}
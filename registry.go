@@ -0,0 +1,102 @@
+package argon2
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// VerifierFunc is an adapter to allow the use of ordinary functions as
+// verifiers.
+type VerifierFunc func(hash, password []byte) error
+
+// Verify calls f(hash, password).
+func (f VerifierFunc) Verify(hash, password []byte) error {
+	return f(hash, password)
+}
+
+var (
+	verifiersMu sync.RWMutex
+	verifiers   = make(map[string]Verifier)
+)
+
+// RegisterVerifier registers the verifier of the legacy hashes starting with
+// the given prefix, e.g. "{CORP-SHA256}", for VerifyAnyScheme and
+// VerifyAndMigrate. Registered verifiers take precedence over the built-in
+// ones, and the longest matching prefix wins. Registering a prefix again
+// replaces its verifier. It panics if the prefix is empty or v is nil.
+func RegisterVerifier(prefix string, v Verifier) {
+	if prefix == "" {
+		panic("argon2: RegisterVerifier prefix is empty")
+	}
+	if v == nil {
+		panic("argon2: RegisterVerifier verifier is nil")
+	}
+
+	verifiersMu.Lock()
+	defer verifiersMu.Unlock()
+	verifiers[prefix] = v
+}
+
+// UnregisterVerifier removes the verifier registered for the prefix, if any.
+func UnregisterVerifier(prefix string) {
+	verifiersMu.Lock()
+	defer verifiersMu.Unlock()
+	delete(verifiers, prefix)
+}
+
+// VerifyAnyScheme compares a stored hash, either an argon2 one or a legacy
+// one of any scheme VerifyAndMigrate recognizes or a verifier is registered
+// for, with the possible cleartext equivalent. The argon2 hash of the records
+// of GenerateDualHash is verified. It returns nil on success,
+// ErrMismatchedHashAndPassword if the password doesn't match, and the errors
+// of CompareHashAndPassword for hashes of unknown schemes. Like
+// VerifyAndMigrate, it rejects legacy hashes with excessive costs.
+func VerifyAnyScheme(hash, password []byte) error {
+	defer padVerify(time.Now())
+
+	if err := checkPassword(password); err != nil {
+		return err
+	}
+	hash = dualArgon2(hash)
+
+	if v := legacyVerifier(hash); v != nil {
+		return v.Verify(hash, password)
+	}
+
+	return CompareHashAndPassword(hash, password)
+}
+
+// legacyVerifier returns the verifier of the hash if it is a legacy hash
+// a verifier is registered for or of a built-in scheme, or nil otherwise.
+func legacyVerifier(hash []byte) Verifier {
+	verifiersMu.RLock()
+	var match string
+	var v Verifier
+	for prefix, registered := range verifiers {
+		if len(prefix) > len(match) && bytes.HasPrefix(hash, []byte(prefix)) {
+			match, v = prefix, registered
+		}
+	}
+	verifiersMu.RUnlock()
+	if v != nil {
+		return v
+	}
+
+	switch {
+	case isWrapped(hash):
+		return VerifierFunc(compareWrapped)
+	case isBcrypt(hash):
+		return VerifierFunc(compareBcrypt)
+	case isScrypt(hash):
+		return VerifierFunc(compareScrypt)
+	case isDjangoPBKDF2(hash):
+		return VerifierFunc(compareDjangoPBKDF2)
+	case isASPNetIdentity(hash):
+		return VerifierFunc(compareASPNetIdentity)
+	case isUnixCrypt(hash):
+		return VerifierFunc(compareUnixCrypt)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestVerifyAnyScheme(t *testing.T) {
+	RegisterVerifier("{PBKDF2-SHA512}", &PBKDF2Verifier{Prefix: "{PBKDF2-SHA512}", PRF: sha512.New, Iterations: 5000, Encoding: base64.RawURLEncoding})
+	defer UnregisterVerifier("{PBKDF2-SHA512}")
+
+	// overrides the built-in bcrypt verifier for "$2y$" hashes only
+	RegisterVerifier("$2y$", VerifierFunc(func(hash, password []byte) error {
+		if bytes.Equal(password, []byte("corporate-override")) {
+			return nil
+		}
+		return ErrMismatchedHashAndPassword
+	}))
+	defer UnregisterVerifier("$2y$")
+
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			name:     "argon2",
+			hash:     []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			password: []byte("dotnet-password"),
+		},
+		{
+			name:     "built-in bcrypt",
+			hash:     []byte("$2b$05$abcdefghijklmnopqrstuuHIrMEWpUCQe2YqFR3sXwQ75u4od..9q"),
+			password: []byte("pw"),
+		},
+		{
+			name:     "built-in scrypt",
+			hash:     []byte("16384$8$1$73696d706c652d7363727970742d3136$dea62b5899c96ae3f22eec8f08b540f07d1ca8dd0e2c503654ed32be01c8c7e2"),
+			password: []byte("scrypt-password"),
+		},
+		{
+			name:     "registered custom scheme",
+			hash:     []byte("{PBKDF2-SHA512}YXNwbmV0LXNhbHQtMDAxNg$23GqRi84uVv_haSwa6GrlHF8oOA46bu1VQvHMAmRBQS20Y4q3TatAqU7k8YJKlw-eaOFenLxGVakGCKhGEOGmQ"),
+			password: []byte("pbkdf2-password"),
+		},
+		{
+			name:     "registered custom scheme mismatch",
+			hash:     []byte("{PBKDF2-SHA512}YXNwbmV0LXNhbHQtMDAxNg$23GqRi84uVv_haSwa6GrlHF8oOA46bu1VQvHMAmRBQS20Y4q3TatAqU7k8YJKlw-eaOFenLxGVakGCKhGEOGmQ"),
+			password: []byte("pbkdf2-password1"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "registered override of built-in scheme",
+			hash:     []byte("$2y$05$htpasswdsaltsaltsalt..UyPcWgfGd6J5xAEcY9Ua/rrqrEPU0ze"),
+			password: []byte("corporate-override"),
+		},
+		{
+			name:     "unknown scheme",
+			hash:     []byte("{SSHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="),
+			password: []byte("pw"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyAnyScheme(tt.hash, tt.password); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyAnyScheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterVerifierLongestPrefix(t *testing.T) {
+	RegisterVerifier("{CORP", VerifierFunc(func(hash, password []byte) error { return ErrMismatchedHashAndPassword }))
+	defer UnregisterVerifier("{CORP")
+	RegisterVerifier("{CORP-V2}", VerifierFunc(func(hash, password []byte) error { return nil }))
+	defer UnregisterVerifier("{CORP-V2}")
+
+	if err := VerifyAnyScheme([]byte("{CORP-V2}hash"), []byte("pw")); err != nil {
+		t.Errorf("VerifyAnyScheme() error = %v, want nil", err)
+	}
+	if err := VerifyAnyScheme([]byte("{CORP-V1}hash"), []byte("pw")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("VerifyAnyScheme() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+}
+
+func TestRegisterVerifierPanics(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		v      Verifier
+	}{
+		{name: "empty prefix", prefix: "", v: VerifierFunc(compareBcrypt)},
+		{name: "nil verifier", prefix: "{CORP}", v: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterVerifier() didn't panic")
+				}
+			}()
+			RegisterVerifier(tt.prefix, tt.v)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package argon2
+
+import (
+	"crypto/subtle"
+	"time"
+)
+
+// RehashPolicy decides whether a hash should be regenerated, so
+// organizations can encode their own rules, e.g. a minimum memory,
+// a required variant or disallowed versions.
+type RehashPolicy interface {
+	// NeedsRehash reports whether the hash with the decoded parameters hp
+	// should be regenerated.
+	NeedsRehash(hp *Params) bool
+}
+
+// RehashPolicyFunc is an adapter to allow the use of ordinary functions as
+// rehash policies.
+type RehashPolicyFunc func(hp *Params) bool
+
+// NeedsRehash calls f(hp).
+func (f RehashPolicyFunc) NeedsRehash(hp *Params) bool {
+	return f(hp)
+}
+
+// ThresholdPolicy is a RehashPolicy requiring a rehash of the hashes whose
+// parameters are below the thresholds, or which use a variant or version
+// that isn't allowed. Zero thresholds aren't enforced.
+type ThresholdPolicy struct {
+	MinMemory          uint32    // The minimum amount of memory (kibibytes)
+	MinIterations      uint32    // The minimum number of iterations
+	MinSaltLength      uint32    // The minimum salt length in bytes
+	MinKeyLength       uint32    // The minimum key length in bytes
+	Variants           []Variant // The allowed variants. Empty means any
+	AllowLegacyVersion bool      // Don't require a rehash of hashes of argon2 version 0x10
+}
+
+// NeedsRehash implements the RehashPolicy interface.
+func (tp *ThresholdPolicy) NeedsRehash(hp *Params) bool {
+	if hp.Memory < tp.MinMemory ||
+		hp.Iterations < tp.MinIterations ||
+		hp.SaltLength < tp.MinSaltLength ||
+		hp.KeyLength < tp.MinKeyLength {
+		return true
+	}
+
+	if hp.Version == legacyVersion && !tp.AllowLegacyVersion {
+		return true
+	}
+
+	if len(tp.Variants) == 0 {
+		return false
+	}
+	for _, v := range tp.Variants {
+		if hp.Variant == v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NeedsRehash reports whether the provided hash was derived with weaker
+// parameters than p, so it should be regenerated with p, e.g. after a
+// successful login. It is the case if the hash uses less memory or
+// iterations, a shorter salt or key, a different parallelism, another
+// variant, pre-hash function or normalization, or the legacy argon2
+// version. The encoding format isn't compared.
+// A nil p stands for the Defaults.
+func NeedsRehash(encodedHash []byte, p *Params) (bool, error) {
+	p = orDefault(p)
+	if err := p.Check(); err != nil {
+		return false, err
+	}
+
+	return NeedsRehashWithPolicy(encodedHash, paramsPolicy(p))
+}
+
+// NeedsRehashWithPolicy is like NeedsRehash, but leaves the decision to
+// the given policy.
+func NeedsRehashWithPolicy(encodedHash []byte, policy RehashPolicy) (bool, error) {
+	hp, _, _, err := decodeHash(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	return policy.NeedsRehash(hp), nil
+}
+
+// CompareAndUpdate compares the provided hash with the password, like
+// CompareHashAndPassword, and on success returns a fresh hash of the password
+// generated with p if the hash needs a rehash, as reported by NeedsRehash,
+// or nil otherwise. The new hash should be persisted in place of the old one.
+func CompareAndUpdate(hash, password []byte, p *Params) (newHash []byte, err error) {
+	p = orDefault(p)
+	return CompareAndUpdateWithPolicy(hash, password, p, paramsPolicy(p))
+}
+
+// CompareAndUpdateWithPolicy is like CompareAndUpdate, but leaves the
+// decision whether the hash needs a rehash to the given policy.
+func CompareAndUpdateWithPolicy(hash, password []byte, p *Params, policy RehashPolicy) (newHash []byte, err error) {
+	defer padVerify(time.Now())
+
+	return compareAndUpdate(hash, password, nil, orDefault(p), policy)
+}
+
+func compareAndUpdate(hash, password, secret []byte, p *Params, policy RehashPolicy) ([]byte, error) {
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+	if err := checkPassword(password); err != nil {
+		return nil, err
+	}
+
+	hp, salt, key, err := decodeHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	if err := hp.checkInputs(secret, nil); err != nil {
+		return nil, err
+	}
+
+	otherKey := hp.Variant.deriveKey(password, salt, secret, hp)
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return nil, ErrMismatchedHashAndPassword
+	}
+	notifyWeakHash(hp)
+
+	// A password below the minimum length keeps its hash rather than
+	// failing the login
+	if !policy.NeedsRehash(hp) || checkNewPassword(password) != nil {
+		return nil, nil
+	}
+
+	return appendHash(nil, password, secret, p)
+}
+
+// paramsPolicy returns the policy of NeedsRehash, requiring a rehash of
+// the hashes derived with weaker parameters than p.
+func paramsPolicy(p *Params) RehashPolicy {
+	return RehashPolicyFunc(func(hp *Params) bool {
+		return hp.WeakerThan(p) ||
+			hp.Parallelism != p.Parallelism ||
+			hp.Variant != p.Variant ||
+			hp.PreHash != p.PreHash ||
+			hp.Normalization != p.Normalization
+	})
+}
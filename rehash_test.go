@@ -0,0 +1,300 @@
+package argon2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestNeedsRehash(t *testing.T) {
+	target := &Params{Memory: 16 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	tests := []struct {
+		name    string
+		hash    string
+		params  *Params
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "same params",
+			hash:   "argon2id$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   false,
+		},
+		{
+			name:   "same params in phc format",
+			hash:   "$argon2id$v=19$m=16384,t=2,p=1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   false,
+		},
+		{
+			name:   "stronger params",
+			hash:   "argon2id$19$65536$3$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   false,
+		},
+		{
+			name:   "pre-hashed toward no pre-hash",
+			hash:   "$argon2id$v=19$m=16384,t=2,p=1,ph=sha512$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   true,
+		},
+		{
+			name:   "normalized toward no normalization",
+			hash:   "$argon2id$v=19$m=16384,t=2,p=1,norm=nfkc$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   true,
+		},
+		{
+			name:   "less memory",
+			hash:   "argon2id$19$8192$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   true,
+		},
+		{
+			name:   "fewer iterations",
+			hash:   "argon2id$19$16384$1$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   true,
+		},
+		{
+			name:   "different parallelism",
+			hash:   "argon2id$19$16384$2$4$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   true,
+		},
+		{
+			name:   "shorter salt",
+			hash:   "argon2id$19$16384$2$1$YWJjZGVmZ2g$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   true,
+		},
+		{
+			name:   "shorter key",
+			hash:   "argon2id$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbQ",
+			params: target,
+			want:   true,
+		},
+		{
+			name:   "other variant",
+			hash:   "argon2i$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   true,
+		},
+		{
+			name:   "legacy version",
+			hash:   "$argon2id$v=16$m=16384,t=2,p=1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params: target,
+			want:   true,
+		},
+		{
+			name:    "invalid hash",
+			hash:    "argon2id$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA",
+			params:  target,
+			wantErr: true,
+		},
+		{
+			name:    "invalid params",
+			hash:    "argon2id$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			params:  &Params{Memory: 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NeedsRehash([]byte(tt.hash), tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NeedsRehash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NeedsRehash() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareAndUpdate(t *testing.T) {
+	target := &Params{Memory: 16 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	current, err := GenerateFromPassword([]byte("password"), target)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	weak, err := GenerateFromPassword([]byte("password"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		hash       []byte
+		password   string
+		params     *Params
+		wantUpdate bool
+		wantErr    error
+	}{
+		{
+			name:     "up to date",
+			hash:     current,
+			password: "password",
+			params:   target,
+		},
+		{
+			name:       "weak hash",
+			hash:       weak,
+			password:   "password",
+			params:     target,
+			wantUpdate: true,
+		},
+		{
+			name:     "wrong password",
+			hash:     weak,
+			password: "passw0rd",
+			params:   target,
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "invalid hash",
+			hash:     []byte("argon2id$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA"),
+			password: "password",
+			params:   target,
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "invalid params",
+			hash:     current,
+			password: "password",
+			params:   &Params{Memory: 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			wantErr:  ErrMemoryTooSmall,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareAndUpdate(tt.hash, []byte(tt.password), tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CompareAndUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got != nil) != tt.wantUpdate {
+				t.Fatalf("CompareAndUpdate() got = %s, wantUpdate %v", got, tt.wantUpdate)
+			}
+			if got == nil {
+				return
+			}
+			if err := CompareHashAndPassword(got, []byte(tt.password)); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+			if needs, _ := NeedsRehash(got, tt.params); needs {
+				t.Errorf("NeedsRehash() got = %v for the updated hash", needs)
+			}
+		})
+	}
+}
+
+func TestThresholdPolicy_NeedsRehash(t *testing.T) {
+	policy := &ThresholdPolicy{
+		MinMemory:     16 * 1024,
+		MinIterations: 2,
+		MinSaltLength: 16,
+		MinKeyLength:  32,
+		Variants:      []Variant{Argon2id, Argon2i},
+	}
+
+	tests := []struct {
+		name   string
+		policy *ThresholdPolicy
+		hash   string
+		want   bool
+	}{
+		{
+			name:   "above thresholds",
+			policy: policy,
+			hash:   "argon2id$19$65536$3$4$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:   false,
+		},
+		{
+			name:   "allowed variant",
+			policy: policy,
+			hash:   "argon2i$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:   false,
+		},
+		{
+			name:   "disallowed variant",
+			policy: policy,
+			hash:   "argon2d$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:   true,
+		},
+		{
+			name:   "less memory",
+			policy: policy,
+			hash:   "argon2id$19$8192$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:   true,
+		},
+		{
+			name:   "shorter key",
+			policy: policy,
+			hash:   "argon2id$19$16384$2$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbQ",
+			want:   true,
+		},
+		{
+			name:   "legacy version",
+			policy: policy,
+			hash:   "$argon2id$v=16$m=16384,t=2,p=1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:   true,
+		},
+		{
+			name:   "allowed legacy version",
+			policy: &ThresholdPolicy{AllowLegacyVersion: true},
+			hash:   "$argon2d$v=16$m=8192,t=1,p=1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbQ",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NeedsRehashWithPolicy([]byte(tt.hash), tt.policy)
+			if err != nil {
+				t.Fatalf("NeedsRehashWithPolicy() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NeedsRehashWithPolicy() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareAndUpdateWithPolicy(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Variant: Argon2i}
+	hash, err := GenerateFromPassword([]byte("password"), p)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	never := RehashPolicyFunc(func(*Params) bool { return false })
+	got, err := CompareAndUpdateWithPolicy(hash, []byte("password"), DefaultParams, never)
+	if err != nil || got != nil {
+		t.Errorf("CompareAndUpdateWithPolicy() got = %s, error = %v, want no update", got, err)
+	}
+
+	idOnly := &ThresholdPolicy{Variants: []Variant{Argon2id}}
+	got, err = CompareAndUpdateWithPolicy(hash, []byte("password"), DefaultParams, idOnly)
+	if err != nil || got == nil {
+		t.Errorf("CompareAndUpdateWithPolicy() got = %s, error = %v, want an update", got, err)
+	}
+}
+
+func TestCompareAndUpdateNilParams(t *testing.T) {
+	hash, err := GenerateFromPassword([]byte("password"), nil)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	if !bytes.HasPrefix(hash, []byte("argon2id$19$65536$3$2$")) {
+		t.Errorf("GenerateFromPassword() got = %s, want a hash with DefaultParams", hash)
+	}
+
+	if needs, err := NeedsRehash(hash, nil); err != nil || needs {
+		t.Errorf("NeedsRehash() got = %v, error = %v, want false", needs, err)
+	}
+	if newHash, err := CompareAndUpdate(hash, []byte("password"), nil); err != nil || newHash != nil {
+		t.Errorf("CompareAndUpdate() got = %s, error = %v, want no update", newHash, err)
+	}
+}
@@ -0,0 +1,53 @@
+package argon2
+
+import (
+	"math/rand"
+)
+
+// Rollout hashes a percentage of new passwords with candidate parameters and
+// the rest with the current ones, so operators can canary a cost increase
+// before rolling it out to every new hash.
+type Rollout struct {
+	Current   *Params // The parameters used for most new passwords
+	Candidate *Params // The parameters being rolled out
+	Percent   float64 // The percentage of new passwords hashed with Candidate, from 0 to 100
+
+	// random returns a number in [0, 100), rand.Float64 scaled if nil
+	random func() float64
+}
+
+// GenerateFromPassword is like GenerateFromPassword, using either the
+// candidate or the current parameters. It reports whether the candidate
+// parameters were used, e.g. to tag the hash in metrics or logs.
+func (r *Rollout) GenerateFromPassword(password []byte) (hash []byte, canary bool, err error) {
+	canary = r.pick()
+
+	p := r.Current
+	if canary {
+		p = r.Candidate
+	}
+
+	hash, err = GenerateFromPassword(password, p)
+	if err != nil {
+		return nil, canary, err
+	}
+
+	return hash, canary, nil
+}
+
+// pick reports whether the next password is hashed with the candidate
+// parameters.
+func (r *Rollout) pick() bool {
+	if r.Percent <= 0 {
+		return false
+	}
+	if r.Percent >= 100 {
+		return true
+	}
+
+	random := r.random
+	if random == nil {
+		random = func() float64 { return rand.Float64() * 100 }
+	}
+	return random() < r.Percent
+}
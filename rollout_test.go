@@ -0,0 +1,83 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestRollout_GenerateFromPassword(t *testing.T) {
+	current := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+	candidate := &Params{Memory: 16 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+
+	tests := []struct {
+		name       string
+		percent    float64
+		random     float64
+		wantCanary bool
+		wantPrefix string
+	}{
+		{
+			name:       "no rollout",
+			percent:    0,
+			random:     0,
+			wantPrefix: "$argon2id$v=19$m=8192,t=1,p=1$",
+		},
+		{
+			name:       "full rollout",
+			percent:    100,
+			random:     99.9,
+			wantCanary: true,
+			wantPrefix: "$argon2id$v=19$m=16384,t=2,p=1$",
+		},
+		{
+			name:       "canary picked",
+			percent:    10,
+			random:     9.9,
+			wantCanary: true,
+			wantPrefix: "$argon2id$v=19$m=16384,t=2,p=1$",
+		},
+		{
+			name:       "canary not picked",
+			percent:    10,
+			random:     10,
+			wantPrefix: "$argon2id$v=19$m=8192,t=1,p=1$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Rollout{
+				Current:   current,
+				Candidate: candidate,
+				Percent:   tt.percent,
+				random:    func() float64 { return tt.random },
+			}
+
+			hash, canary, err := r.GenerateFromPassword([]byte("canary-password"))
+			if err != nil {
+				t.Fatalf("GenerateFromPassword() error = %v", err)
+			}
+			if canary != tt.wantCanary {
+				t.Errorf("GenerateFromPassword() canary = %v, want %v", canary, tt.wantCanary)
+			}
+			if string(hash[:len(tt.wantPrefix)]) != tt.wantPrefix {
+				t.Errorf("GenerateFromPassword() got = %s, want prefix %s", hash, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestRollout_pick(t *testing.T) {
+	r := &Rollout{Percent: 25}
+
+	const n = 10000
+	picked := 0
+	for i := 0; i < n; i++ {
+		if r.pick() {
+			picked++
+		}
+	}
+
+	// loose bounds, the expected value is 2500
+	if picked < 2000 || picked > 3000 {
+		t.Errorf("pick() picked %d of %d, want about 25%%", picked, n)
+	}
+}
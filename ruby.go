@@ -0,0 +1,25 @@
+package argon2
+
+// RubyParams provides the defaults of the Ruby argon2 gem's
+// Argon2::Password.create: t_cost 2, m_cost 16 (2^16 KiB) and p_cost 1.
+// The gem encodes its hashes in the PHC format, so they can be verified
+// with CompareHashAndPassword, or with CompareHashAndPasswordWithSecret
+// if the gem was configured with a secret.
+//
+// RubyParams is shared by every importer of the package, so mutating it is
+// deprecated: use Ruby to get a copy instead.
+var RubyParams = Ruby()
+
+// Ruby returns a copy of the defaults of the Ruby argon2 gem's
+// Argon2::Password.create, as described by RubyParams, which the caller is
+// free to modify.
+func Ruby() *Params {
+	return &Params{
+		Memory:      64 * 1024,
+		Iterations:  2,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+		Format:      FormatPHC,
+	}
+}
@@ -0,0 +1,71 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestRubyCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		secret   []byte
+		wantErr  bool
+	}{
+		{
+			// Argon2::Password.create with default options
+			name:     "argon2id defaults",
+			hash:     []byte("$argon2id$v=19$m=65536,t=2,p=1$cnVieWdlbS1zYWx0LTAxNg$a8m/YV+4Knpur26ckRNXIiWhQxycwcd2XZiOFKbdJJc"),
+			password: []byte("rails-password"),
+		},
+		{
+			// Argon2::Password.new(m_cost: 12, secret: ...).create
+			name:     "argon2id with secret",
+			hash:     []byte("$argon2id$v=19$m=4096,t=2,p=1$cnVieWdlbS1zYWx0LTAxNg$LoMCoTNPnKDIdHLV2BfMBrBGcMS/zomQq+CQTICCGU4"),
+			password: []byte("rails-password"),
+			secret:   []byte("pepper-from-credentials"),
+		},
+		{
+			// Argon2::Password.new(type: :argon2i, m_cost: 12, secret: ...).create
+			name:     "argon2i with secret",
+			hash:     []byte("$argon2i$v=19$m=4096,t=2,p=1$cnVieWdlbS1zYWx0LTAxNg$NWjzk+pJcaqIrAsGQMKgxd3pcbbREI2WRxSJjkdPTwg"),
+			password: []byte("rails-password"),
+			secret:   []byte("pepper-from-credentials"),
+		},
+		{
+			name:     "missing secret",
+			hash:     []byte("$argon2id$v=19$m=4096,t=2,p=1$cnVieWdlbS1zYWx0LTAxNg$LoMCoTNPnKDIdHLV2BfMBrBGcMS/zomQq+CQTICCGU4"),
+			password: []byte("rails-password"),
+			wantErr:  true,
+		},
+		{
+			name:     "wrong secret",
+			hash:     []byte("$argon2id$v=19$m=4096,t=2,p=1$cnVieWdlbS1zYWx0LTAxNg$LoMCoTNPnKDIdHLV2BfMBrBGcMS/zomQq+CQTICCGU4"),
+			password: []byte("rails-password"),
+			secret:   []byte("another-pepper"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPasswordWithSecret(tt.hash, tt.password, tt.secret); (err != nil) != tt.wantErr {
+				t.Errorf("CompareHashAndPasswordWithSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateFromPasswordWithSecret(t *testing.T) {
+	password, secret := []byte("rails-password"), []byte("pepper-from-credentials")
+	hash, err := GenerateFromPasswordWithSecret(password, secret, Ruby())
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithSecret() error = %v", err)
+	}
+
+	if err := CompareHashAndPasswordWithSecret(hash, password, secret); err != nil {
+		t.Errorf("CompareHashAndPasswordWithSecret() error = %v", err)
+	}
+	if err := CompareHashAndPassword(hash, password); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareHashAndPassword() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+}
@@ -0,0 +1,81 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"strconv"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scryptPrefix is the optional prefix of scrypt hashes in the format of
+// github.com/elithrar/simple-scrypt.
+const scryptPrefix = "scrypt$"
+
+// scryptHash is a decoded scrypt hash.
+type scryptHash struct {
+	n, r, p   int
+	salt, key []byte
+}
+
+// isScrypt reports whether the hash looks like a scrypt hash in the
+// "N$r$p$salt$dk" format of github.com/elithrar/simple-scrypt, optionally
+// prefixed with "scrypt$". The cost parameters are decimal, and the salt and
+// derived key hex encoded.
+func isScrypt(hash []byte) bool {
+	hash = bytes.TrimPrefix(hash, []byte(scryptPrefix))
+	return len(hash) > 0 && hash[0] >= '0' && hash[0] <= '9' && bytes.Count(hash, []byte("$")) == 4
+}
+
+// decodeScrypt decodes a scrypt hash in the format of
+// github.com/elithrar/simple-scrypt.
+func decodeScrypt(hash []byte) (*scryptHash, error) {
+	vals := bytes.Split(bytes.TrimPrefix(hash, []byte(scryptPrefix)), []byte("$"))
+	if len(vals) != 5 {
+		return nil, ErrInvalidHash
+	}
+
+	var params [3]int
+	for i := range params {
+		v, err := strconv.ParseUint(string(vals[i]), 10, 31)
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		params[i] = int(v)
+	}
+
+	h := &scryptHash{n: params[0], r: params[1], p: params[2]}
+	var err error
+	if h.salt, err = hex.DecodeString(string(vals[3])); err != nil {
+		return nil, ErrInvalidHash
+	}
+	if h.key, err = hex.DecodeString(string(vals[4])); err != nil || len(h.key) == 0 {
+		return nil, ErrInvalidHash
+	}
+
+	return h, nil
+}
+
+// compareScrypt compares a scrypt hash in the format of
+// github.com/elithrar/simple-scrypt with the password.
+func compareScrypt(hash, password []byte) error {
+	h, err := decodeScrypt(hash)
+	if err != nil {
+		return err
+	}
+	if err := checkScryptLimits(h.n, h.r, h.p); err != nil {
+		return err
+	}
+
+	otherKey, err := scrypt.Key(password, h.salt, h.n, h.r, h.p, len(h.key))
+	if err != nil {
+		return ErrInvalidParams
+	}
+
+	if subtle.ConstantTimeCompare(h.key, otherKey) == 1 {
+		return nil
+	}
+
+	return ErrMismatchedHashAndPassword
+}
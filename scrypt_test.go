@@ -0,0 +1,56 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestCompareScrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			name:     "simple-scrypt defaults",
+			hash:     []byte("16384$8$1$73696d706c652d7363727970742d3136$dea62b5899c96ae3f22eec8f08b540f07d1ca8dd0e2c503654ed32be01c8c7e2"),
+			password: []byte("scrypt-password"),
+		},
+		{
+			name:     "scrypt prefix and 64 bytes key",
+			hash:     []byte("scrypt$1024$8$2$73696d706c652d7363727970742d3136$530b3985f9cb31a5559a0353f660649fed00d082e2c73cde3d3ff8d519c33d1fa57ca21e7622c5209c1c10012a8e90ac518985319f549a54d2b0a6fa52a72035"),
+			password: []byte("scrypt-password"),
+		},
+		{
+			name:     "mismatched password",
+			hash:     []byte("scrypt$1024$8$2$73696d706c652d7363727970742d3136$530b3985f9cb31a5559a0353f660649fed00d082e2c73cde3d3ff8d519c33d1fa57ca21e7622c5209c1c10012a8e90ac518985319f549a54d2b0a6fa52a72035"),
+			password: []byte("scrypt-password1"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "N not a power of 2",
+			hash:     []byte("1000$8$2$73696d706c652d7363727970742d3136$530b3985f9cb31a5559a0353f660649fed00d082e2c73cde3d3ff8d519c33d1f"),
+			password: []byte("scrypt-password"),
+			wantErr:  ErrInvalidParams,
+		},
+		{
+			name:     "invalid hex",
+			hash:     []byte("1024$8$2$73696d706c652d7363727970742d3136$not-hex"),
+			password: []byte("scrypt-password"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "missing key",
+			hash:     []byte("1024$8$2$73696d706c652d7363727970742d3136"),
+			password: []byte("scrypt-password"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := compareScrypt(tt.hash, tt.password); err != tt.wantErr {
+				t.Errorf("compareScrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
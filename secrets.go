@@ -0,0 +1,170 @@
+package argon2
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrUnknownKeyID is returned when the pepper a hash was generated with
+// can't be found by its key ID.
+var ErrUnknownKeyID = errors.New("argon2: unknown pepper key ID")
+
+// pepperPrefix starts the hashes generated by a Hasher with a SecretProvider,
+// "{PEPPER:<key ID>}<argon2 hash>".
+const pepperPrefix = "{PEPPER:"
+
+// SecretProvider supplies the peppers of a Hasher by key ID, so they can be
+// sourced from the environment, files or external key managers. New hashes
+// are generated with the current pepper and record its key ID, which is
+// looked up with Get to verify them, so peppers can be rotated.
+type SecretProvider interface {
+	// Get returns the pepper of the key ID, or an error, e.g.
+	// ErrUnknownKeyID, if there is none.
+	Get(keyID string) ([]byte, error)
+	// Current returns the key ID and the pepper of new hashes.
+	Current() (keyID string, secret []byte)
+}
+
+// PepperMAC computes the HMAC-SHA256 of passwords keyed with peppers it
+// holds, e.g. in an HSM, so the peppers never enter the memory of the
+// process. Its results must match the ones of a SecretProvider handing out
+// the same peppers.
+type PepperMAC interface {
+	// MAC returns the HMAC-SHA256 of the password keyed with the pepper of
+	// the key ID, or an error, e.g. ErrUnknownKeyID, if there is none.
+	MAC(keyID string, password []byte) ([]byte, error)
+	// CurrentID returns the key ID of the pepper of new hashes.
+	CurrentID() string
+}
+
+// providerMAC is the PepperMAC of the peppers of a SecretProvider.
+type providerMAC struct {
+	secrets SecretProvider
+}
+
+// MAC implements the PepperMAC interface.
+func (p providerMAC) MAC(keyID string, password []byte) ([]byte, error) {
+	secret, err := p.secrets.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) == 0 {
+		return nil, ErrUnknownKeyID
+	}
+	return applyPepper(password, secret), nil
+}
+
+// CurrentID implements the PepperMAC interface.
+func (p providerMAC) CurrentID() string {
+	keyID, _ := p.secrets.Current()
+	return keyID
+}
+
+// KeyRing is a SecretProvider holding the peppers in memory.
+type KeyRing struct {
+	CurrentID string            // The key ID of the pepper of new hashes
+	Keys      map[string][]byte // The peppers by key ID
+}
+
+// Get implements the SecretProvider interface.
+func (k *KeyRing) Get(keyID string) ([]byte, error) {
+	secret, ok := k.Keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return secret, nil
+}
+
+// Current implements the SecretProvider interface.
+func (k *KeyRing) Current() (keyID string, secret []byte) {
+	return k.CurrentID, k.Keys[k.CurrentID]
+}
+
+// appendPepperID appends the prefix recording the key ID to dst.
+// It returns ErrUnknownKeyID if the key ID can't be recorded.
+func appendPepperID(dst []byte, keyID string) ([]byte, error) {
+	return appendKeyID(dst, pepperPrefix, keyID)
+}
+
+// splitPepperID splits a hash generated with a SecretProvider into the key
+// ID of its pepper and the argon2 hash. ok is false for other hashes.
+func splitPepperID(hash []byte) (keyID string, rest []byte, ok bool) {
+	return splitKeyID(hash, pepperPrefix)
+}
+
+// appendKeyID appends the prefix recording the key ID, "<prefix><key ID>}",
+// to dst. It returns ErrUnknownKeyID if the key ID can't be recorded.
+func appendKeyID(dst []byte, prefix, keyID string) ([]byte, error) {
+	if keyID == "" || strings.ContainsAny(keyID, "{}$") {
+		return nil, ErrUnknownKeyID
+	}
+
+	dst = append(dst, prefix...)
+	dst = append(dst, keyID...)
+	return append(dst, '}'), nil
+}
+
+// splitKeyID splits a hash starting with the prefix recording a key ID into
+// the key ID and the rest of the hash. ok is false for other hashes.
+func splitKeyID(hash []byte, prefix string) (keyID string, rest []byte, ok bool) {
+	if !bytes.HasPrefix(hash, []byte(prefix)) {
+		return "", hash, false
+	}
+	hash = hash[len(prefix):]
+
+	i := bytes.IndexByte(hash, '}')
+	if i < 1 {
+		return "", hash, false
+	}
+	return string(hash[:i]), hash[i+1:], true
+}
+
+// GenerateFromPasswordWithProvider is like GenerateFromPasswordWithSecret,
+// but mixes the current secret of the provider into the derivation as the
+// secret input (K) of argon2, and records its key ID in the "keyid"
+// parameter of the hash. Unlike the peppers of a Hasher, the secret isn't
+// applied as an HMAC of the password, so the hashes can be verified by other
+// argon2 implementations given the same secret. As the legacy format can't
+// record the key ID, the hash is encoded in FormatPHC instead.
+func GenerateFromPasswordWithProvider(password []byte, secrets SecretProvider, p *Params) ([]byte, error) {
+	keyID, secret := secrets.Current()
+	if keyID == "" || len(secret) == 0 {
+		return nil, ErrUnknownKeyID
+	}
+
+	params := *orDefault(p)
+	params.KeyID = keyID
+	if params.Format == FormatLegacy {
+		params.Format = FormatPHC
+	}
+
+	return appendHash(nil, password, secret, &params)
+}
+
+// CompareHashAndPasswordWithProvider is like CompareHashAndPasswordWithSecret,
+// but looks the secret up by the key ID recorded in the hash, e.g. by
+// GenerateFromPasswordWithProvider, so secrets can be rotated. Hashes
+// without a key ID are compared without a secret.
+func CompareHashAndPasswordWithProvider(hash, password []byte, secrets SecretProvider) error {
+	defer padVerify(time.Now())
+
+	p, err := ExtractParams(hash)
+	if err != nil {
+		return err
+	}
+	if p.KeyID == "" {
+		return CompareHashAndPassword(hash, password)
+	}
+
+	secret, err := secrets.Get(p.KeyID)
+	if err != nil {
+		return err
+	}
+	if len(secret) == 0 {
+		return ErrUnknownKeyID
+	}
+
+	return CompareHashAndPasswordWithSecret(hash, password, secret)
+}
@@ -0,0 +1,144 @@
+package argon2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestHasher_WithSecretProvider(t *testing.T) {
+	keys := &KeyRing{
+		CurrentID: "2023",
+		Keys:      map[string][]byte{"2023": []byte("old-pepper")},
+	}
+	h, err := NewHasher(WithMemory(8*1024), WithIterations(1), WithSecretProvider(keys))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+
+	oldHash, err := h.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if !bytes.HasPrefix(oldHash, []byte("{PEPPER:2023}argon2id$")) {
+		t.Errorf("Hash() got = %s, want prefix {PEPPER:2023}argon2id$", oldHash)
+	}
+	if err := h.Verify(oldHash, []byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if rehash, err := h.NeedsRehash(oldHash); err != nil || rehash {
+		t.Errorf("NeedsRehash() got = %v, error = %v, want false, nil", rehash, err)
+	}
+
+	// rotate the pepper
+	keys.Keys["2024"] = []byte("new-pepper")
+	keys.CurrentID = "2024"
+
+	if err := h.Verify(oldHash, []byte("password")); err != nil {
+		t.Errorf("Verify() of the old hash error = %v", err)
+	}
+	if rehash, err := h.NeedsRehash(oldHash); err != nil || !rehash {
+		t.Errorf("NeedsRehash() got = %v, error = %v, want true, nil", rehash, err)
+	}
+	newHash, err := h.CompareAndUpdate(oldHash, []byte("password"))
+	if err != nil || !bytes.HasPrefix(newHash, []byte("{PEPPER:2024}")) {
+		t.Fatalf("CompareAndUpdate() got = %s, error = %v, want a hash with the new pepper", newHash, err)
+	}
+	if err := h.Verify(newHash, []byte("password")); err != nil {
+		t.Errorf("Verify() of the new hash error = %v", err)
+	}
+
+	// hashes without a key ID need a rehash to get the current pepper
+	plain := MustGenerateFromPassword([]byte("password"), h.Params())
+	if err := h.Verify(plain, []byte("password")); err != nil {
+		t.Errorf("Verify() of an unpeppered hash error = %v", err)
+	}
+	if rehash, err := h.NeedsRehash(plain); err != nil || !rehash {
+		t.Errorf("NeedsRehash() of an unpeppered hash got = %v, error = %v, want true, nil", rehash, err)
+	}
+
+	delete(keys.Keys, "2023")
+	if err := h.Verify(oldHash, []byte("password")); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrUnknownKeyID)
+	}
+
+	withoutProvider, _ := NewHasher(WithMemory(8*1024), WithIterations(1))
+	if err := withoutProvider.Verify(newHash, []byte("password")); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("Verify() without a provider error = %v, want %v", err, ErrUnknownKeyID)
+	}
+
+	keys.CurrentID = "bad}id"
+	if _, err := h.Hash([]byte("password")); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("Hash() with an invalid key ID error = %v, want %v", err, ErrUnknownKeyID)
+	}
+}
+
+func TestHasher_EmptyCurrentPepper(t *testing.T) {
+	h, err := NewHasher(WithMemory(8*1024), WithIterations(1), WithSecretProvider(&KeyRing{CurrentID: "missing"}))
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	if _, err := h.Hash([]byte("password")); err != ErrUnknownKeyID {
+		t.Errorf("Hash() error = %v, want %v", err, ErrUnknownKeyID)
+	}
+}
+
+func TestGenerateFromPasswordWithProvider(t *testing.T) {
+	keys := &KeyRing{
+		CurrentID: "2023",
+		Keys:      map[string][]byte{"2023": []byte("old-secret")},
+	}
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	oldHash, err := GenerateFromPasswordWithProvider([]byte("password"), keys, p)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithProvider() error = %v", err)
+	}
+	if !bytes.HasPrefix(oldHash, []byte("$argon2id$v=19$m=8192,t=1,p=1,keyid=MjAyMw$")) {
+		t.Errorf("GenerateFromPasswordWithProvider() got = %s, want keyid=MjAyMw", oldHash)
+	}
+	hp, err := ExtractParams(oldHash)
+	if err != nil || hp.KeyID != "2023" {
+		t.Errorf("ExtractParams() got = %v, error = %v, want KeyID 2023", hp, err)
+	}
+
+	// rotate the secret
+	keys.Keys["2024"] = []byte("new-secret")
+	keys.CurrentID = "2024"
+
+	newHash, err := GenerateFromPasswordWithProvider([]byte("password"), keys, p)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithProvider() error = %v", err)
+	}
+
+	for _, hash := range [][]byte{oldHash, newHash} {
+		if err := CompareHashAndPasswordWithProvider(hash, []byte("password"), keys); err != nil {
+			t.Errorf("CompareHashAndPasswordWithProvider(%s) error = %v", hash, err)
+		}
+		if err := CompareHashAndPasswordWithProvider(hash, []byte("wrong"), keys); err != ErrMismatchedHashAndPassword {
+			t.Errorf("CompareHashAndPasswordWithProvider(%s) error = %v, want %v", hash, err, ErrMismatchedHashAndPassword)
+		}
+		if err := CompareHashAndPassword(hash, []byte("password")); !errors.Is(err, ErrUnsupportedParams) {
+			t.Errorf("CompareHashAndPassword(%s) error = %v, want %v", hash, err, ErrUnsupportedParams)
+		}
+	}
+
+	// the secret is the native argon2 K input, not an HMAC of the password
+	if err := CompareHashAndPasswordWithSecret(oldHash, []byte("password"), []byte("old-secret")); err != nil {
+		t.Errorf("CompareHashAndPasswordWithSecret() error = %v", err)
+	}
+
+	delete(keys.Keys, "2023")
+	if err := CompareHashAndPasswordWithProvider(oldHash, []byte("password"), keys); err != ErrUnknownKeyID {
+		t.Errorf("CompareHashAndPasswordWithProvider() error = %v, want %v", err, ErrUnknownKeyID)
+	}
+}
+
+func TestCompareHashAndPasswordWithProvider_NoKeyID(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hash := MustGenerateFromPassword([]byte("password"), p)
+
+	if err := CompareHashAndPasswordWithProvider(hash, []byte("password"), &KeyRing{}); err != nil {
+		t.Errorf("CompareHashAndPasswordWithProvider() error = %v", err)
+	}
+}
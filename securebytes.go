@@ -0,0 +1,51 @@
+package argon2
+
+import "fmt"
+
+// redacted is printed in place of the contents of SecureBytes.
+const redacted = "[REDACTED]"
+
+// SecureBytes holds a secret, such as a password or a pepper. As a []byte,
+// it is accepted as is by the hashing and verifying functions, e.g.
+// GenerateFromPassword(password, p) with a SecureBytes password, and it can
+// be wiped explicitly once it isn't needed anymore. It redacts itself when
+// formatted with the fmt package or marshaled as text, e.g. by loggers, so
+// it doesn't leak into logs.
+type SecureBytes []byte
+
+// NewSecureBytes returns a copy of the secret as SecureBytes. Strings can't
+// be wiped, so secrets should rather be read into SecureBytes directly.
+func NewSecureBytes(secret string) SecureBytes {
+	return SecureBytes(secret)
+}
+
+// Wipe overwrites the secret with zeros.
+func (s SecureBytes) Wipe() {
+	wipe(s)
+}
+
+// String implements the fmt.Stringer interface, redacting the secret.
+func (s SecureBytes) String() string {
+	return redacted
+}
+
+// GoString implements the fmt.GoStringer interface, redacting the secret.
+func (s SecureBytes) GoString() string {
+	return "argon2.SecureBytes(" + redacted + ")"
+}
+
+// Format implements the fmt.Formatter interface, redacting the secret with
+// every verb, including the ones printing bytes such as %x and %v.
+func (s SecureBytes) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, s.GoString())
+		return
+	}
+	fmt.Fprint(f, redacted)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, redacting
+// the secret, e.g. in JSON or structured logs.
+func (s SecureBytes) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
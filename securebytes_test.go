@@ -0,0 +1,53 @@
+package argon2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecureBytes_Redacted(t *testing.T) {
+	s := NewSecureBytes("hunter2")
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%x", "%X", "%q", "%d"} {
+		if got := fmt.Sprintf(format, s); strings.Contains(got, "hunter2") || !strings.Contains(got, redacted) {
+			t.Errorf("Sprintf(%q) got = %s, want redacted", format, got)
+		}
+	}
+	if got := fmt.Sprint(struct{ Password SecureBytes }{s}); !strings.Contains(got, redacted) {
+		t.Errorf("Sprint() of a struct got = %s, want redacted", got)
+	}
+
+	got, err := json.Marshal(map[string]SecureBytes{"password": s})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(got) != `{"password":"[REDACTED]"}` {
+		t.Errorf("json.Marshal() got = %s, want redacted", got)
+	}
+}
+
+func TestSecureBytes_Hashing(t *testing.T) {
+	password := NewSecureBytes("password")
+	pepper := NewSecureBytes("pepper")
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	hash, err := GenerateFromPasswordWithPepper(password, pepper, p)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithPepper() error = %v", err)
+	}
+	if err := CompareHashAndPasswordWithPepper(hash, password, pepper); err != nil {
+		t.Errorf("CompareHashAndPasswordWithPepper() error = %v", err)
+	}
+
+	password.Wipe()
+	for i, b := range password {
+		if b != 0 {
+			t.Fatalf("Wipe() left byte %d = %#x", i, b)
+		}
+	}
+	if err := CompareHashAndPasswordWithPepper(hash, password, pepper); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareHashAndPasswordWithPepper() after Wipe() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+	}
+}
@@ -0,0 +1,65 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+
+	"github.com/andskur/argon2-hashing/internal/core"
+)
+
+// ErrSelfTestFailed is returned by SelfTest when a derived key doesn't match
+// its known answer.
+var ErrSelfTestFailed = errors.New("argon2: self-test failed")
+
+// knownAnswer is a known-answer test of the argon2 key derivation function.
+// The inputs are the ones of the RFC 9106 test vectors: a password of 32
+// 0x01 bytes, a salt of 16 0x02 bytes and, for the vectors of the RFC
+// itself, a secret of 8 0x03 bytes and associated data of 12 0x04 bytes,
+// with 3 iterations over 32 KiB and 4 lanes.
+type knownAnswer struct {
+	variant Variant
+	rfc     bool // whether the secret and associated data are used
+	want    string
+}
+
+var knownAnswers = []knownAnswer{
+	// RFC 9106, section 5
+	{variant: Argon2d, rfc: true, want: "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb"},
+	{variant: Argon2i, rfc: true, want: "c814d9d1dc7f37aa13f0d77f2494bda1c8de6b016dd388d29952a4c4672b6ce8"},
+	{variant: Argon2id, rfc: true, want: "0d640df58d78766c08c037a34a8b53c9d01ef0452d75b65eb52520e96b01e659"},
+
+	// the same inputs without secret and associated data, which are derived
+	// by golang.org/x/crypto/argon2
+	{variant: Argon2i, want: "a9a7510e6db4d588ba3414cd0e094d480d683f97b9ccb612a544fe8ef65ba8e0"},
+	{variant: Argon2id, want: "03aab965c12001c9d7d0d2de33192c0494b684bb148196d73c1df1acaf6d0c2e"},
+}
+
+// SelfTest runs the RFC 9106 known-answer tests against the argon2
+// implementations used by the package, and returns ErrSelfTestFailed if any
+// derived key doesn't match. It takes a few milliseconds, and is meant for
+// startup health checks, e.g. in regulated environments requiring them.
+func SelfTest() error {
+	password := bytes.Repeat([]byte{0x01}, 32)
+	salt := bytes.Repeat([]byte{0x02}, 16)
+	secret := bytes.Repeat([]byte{0x03}, 8)
+	data := bytes.Repeat([]byte{0x04}, 12)
+	p := &Params{Memory: 32, Iterations: 3, Parallelism: 4, KeyLength: 32}
+
+	for _, ka := range knownAnswers {
+		var got []byte
+		if ka.rfc {
+			got = core.DeriveKey(ka.variant.mode(), core.Version13, password, salt, secret, data, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+		} else {
+			got = ka.variant.deriveKey(password, salt, nil, p)
+		}
+
+		want, _ := hex.DecodeString(ka.want)
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			return ErrSelfTestFailed
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Errorf("SelfTest() error = %v", err)
+	}
+}
+
+func TestSelfTestFailure(t *testing.T) {
+	saved := knownAnswers[len(knownAnswers)-1].want
+	defer func() { knownAnswers[len(knownAnswers)-1].want = saved }()
+
+	knownAnswers[len(knownAnswers)-1].want = "00" + saved[2:]
+	if err := SelfTest(); err != ErrSelfTestFailed {
+		t.Errorf("SelfTest() error = %v, want %v", err, ErrSelfTestFailed)
+	}
+}
@@ -0,0 +1,24 @@
+package argon2
+
+// LowMemoryParams is a preset for constrained environments, such as
+// serverless functions (AWS Lambda, Cloud Run) and single-board computers
+// with 256-512 MB of RAM, where every concurrent hash allocating 64 MiB
+// quickly runs out of memory. It uses 12 MiB of memory and compensates with
+// 3 iterations, matching the strength of the OWASP recommendation for argon2id.
+// A single lane is used, as such environments seldom have more than one CPU.
+//
+// LowMemoryParams is shared by every importer of the package, so mutating
+// it is deprecated: use LowMemory to get a copy instead.
+var LowMemoryParams = LowMemory()
+
+// LowMemory returns a copy of the preset for constrained environments, as
+// described by LowMemoryParams, which the caller is free to modify.
+func LowMemory() *Params {
+	return &Params{
+		Memory:      12 * 1024,
+		Iterations:  3,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
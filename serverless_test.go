@@ -0,0 +1,19 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestLowMemoryParams(t *testing.T) {
+	if err := LowMemory().Check(); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	hash, err := GenerateFromPassword([]byte("serverless-password"), LowMemory())
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	if err := CompareHashAndPassword(hash, []byte("serverless-password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package argon2
+
+import (
+	"sync"
+	"time"
+)
+
+// ShadowResult reports the timings of a successful verification and of the
+// shadow derivation of the same password with candidate parameters.
+type ShadowResult struct {
+	Current   time.Duration // Duration of the verification with the parameters of the hash
+	Candidate time.Duration // Duration of the derivation with the candidate parameters
+	Err       error         // Error of the shadow derivation, e.g. for invalid candidate parameters
+}
+
+// ShadowVerifier verifies hashes, and after each successful verification
+// derives the password again with candidate parameters in the background,
+// reporting the timings. It allows to collect production data on how
+// a parameter bump would behave before rolling it out, without affecting
+// the login path. Candidate and Report should be set: without Report, it
+// verifies hashes without shadow derivations. A ShadowVerifier must not be
+// copied after first use.
+type ShadowVerifier struct {
+	Candidate   *Params            // The candidate parameters
+	Report      func(ShadowResult) // Called with the result of every shadow derivation, from its goroutine
+	MaxInFlight int                // The maximum number of concurrent shadow derivations, 1 if zero. Extra ones are skipped
+
+	once     sync.Once
+	inFlight chan struct{}
+	wg       sync.WaitGroup
+}
+
+// CompareHashAndPassword is like CompareHashAndPassword, and on success
+// starts a shadow derivation of the password with the candidate parameters,
+// unless MaxInFlight of them are already running. The result of the
+// verification doesn't depend on the shadow derivation.
+func (s *ShadowVerifier) CompareHashAndPassword(hash, password []byte) error {
+	// The padding to the minimum verification duration isn't timed
+	start := time.Now()
+	defer padVerify(start)
+	if _, err := compareHashAndPassword(nil, hash, password, nil); err != nil {
+		return err
+	}
+	current := time.Since(start)
+	if s.Report == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		n := s.MaxInFlight
+		if n < 1 {
+			n = 1
+		}
+		s.inFlight = make(chan struct{}, n)
+	})
+
+	select {
+	case s.inFlight <- struct{}{}:
+	default:
+		return nil
+	}
+
+	// the caller may clear the password once we return
+	password = append([]byte(nil), password...)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.inFlight }()
+
+		// The password is an existing one, the checks of new passwords,
+		// e.g. a breach lookup by the policy, don't apply
+		start := time.Now()
+		_, _, err := generateRaw(password, nil, s.Candidate)
+		s.Report(ShadowResult{Current: current, Candidate: time.Since(start), Err: err})
+	}()
+
+	return nil
+}
+
+// Wait waits for the running shadow derivations to complete, e.g. on shutdown.
+func (s *ShadowVerifier) Wait() {
+	s.wg.Wait()
+}
@@ -0,0 +1,139 @@
+package argon2
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestShadowVerifier(t *testing.T) {
+	hash := []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU")
+
+	tests := []struct {
+		name        string
+		candidate   *Params
+		password    []byte
+		wantErr     error
+		wantReports int
+		wantShadow  error
+	}{
+		{
+			name:        "valid password",
+			candidate:   &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			password:    []byte("qwerty123"),
+			wantReports: 1,
+		},
+		{
+			name:      "mismatched password",
+			candidate: &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			password:  []byte("qwerty1234"),
+			wantErr:   ErrMismatchedHashAndPassword,
+		},
+		{
+			name:        "invalid candidate",
+			candidate:   &Params{},
+			password:    []byte("qwerty123"),
+			wantReports: 1,
+			wantShadow:  ErrMemoryTooSmall,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var results []ShadowResult
+			s := &ShadowVerifier{
+				Candidate: tt.candidate,
+				Report: func(r ShadowResult) {
+					mu.Lock()
+					defer mu.Unlock()
+					results = append(results, r)
+				},
+			}
+
+			if err := s.CompareHashAndPassword(hash, tt.password); err != tt.wantErr {
+				t.Errorf("CompareHashAndPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			s.Wait()
+
+			if len(results) != tt.wantReports {
+				t.Fatalf("got %d reports, want %d", len(results), tt.wantReports)
+			}
+			for _, r := range results {
+				if !errors.Is(r.Err, tt.wantShadow) {
+					t.Errorf("ShadowResult.Err = %v, want %v", r.Err, tt.wantShadow)
+				}
+				if r.Current <= 0 {
+					t.Errorf("ShadowResult.Current = %v, want > 0", r.Current)
+				}
+			}
+		})
+	}
+}
+
+func TestShadowVerifierMaxInFlight(t *testing.T) {
+	hash := []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU")
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	reports := 0
+	s := &ShadowVerifier{
+		Candidate: &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		Report: func(ShadowResult) {
+			<-release
+			mu.Lock()
+			defer mu.Unlock()
+			reports++
+		},
+	}
+
+	// the second shadow derivation is skipped while the first one is running
+	for i := 0; i < 2; i++ {
+		if err := s.CompareHashAndPassword(hash, []byte("qwerty123")); err != nil {
+			t.Fatalf("CompareHashAndPassword() error = %v", err)
+		}
+	}
+	close(release)
+	s.Wait()
+
+	if reports != 1 {
+		t.Errorf("got %d reports, want 1", reports)
+	}
+}
+
+func TestShadowVerifier_Policy(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hash := MustGenerateFromPassword([]byte("short"), p)
+
+	calls := 0
+	SetPolicy(PolicyFunc(func(password []byte) error {
+		calls++
+		return ErrPolicyViolation
+	}))
+	defer SetPolicy(nil)
+	SetMinPasswordLength(8)
+	defer SetMinPasswordLength(0)
+
+	var result ShadowResult
+	s := &ShadowVerifier{Candidate: p, Report: func(r ShadowResult) { result = r }}
+	if err := s.CompareHashAndPassword(hash, []byte("short")); err != nil {
+		t.Fatalf("CompareHashAndPassword() error = %v", err)
+	}
+	s.Wait()
+
+	if result.Err != nil {
+		t.Errorf("ShadowResult.Err = %v, want nil", result.Err)
+	}
+	if calls != 0 {
+		t.Errorf("policy called %d times, want 0", calls)
+	}
+}
+
+func TestShadowVerifier_NilReport(t *testing.T) {
+	hash := []byte("argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU")
+
+	s := &ShadowVerifier{Candidate: &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}}
+	if err := s.CompareHashAndPassword(hash, []byte("qwerty123")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+	s.Wait()
+}
@@ -0,0 +1,96 @@
+package argon2
+
+import (
+	"math"
+)
+
+// The opslimit and memlimit presets of libsodium's crypto_pwhash for
+// Argon2id. The opslimit is the number of iterations and the memlimit
+// is the amount of memory in bytes.
+const (
+	SodiumOpsLimitInteractive = 2
+	SodiumMemLimitInteractive = 64 << 20
+	SodiumOpsLimitModerate    = 3
+	SodiumMemLimitModerate    = 256 << 20
+	SodiumOpsLimitSensitive   = 4
+	SodiumMemLimitSensitive   = 1 << 30
+)
+
+// Constants of libsodium's crypto_pwhash_str output.
+const (
+	sodiumSaltLength = 16 // crypto_pwhash_SALTBYTES
+	sodiumKeyLength  = 32 // the derived key length of crypto_pwhash_str
+)
+
+// SodiumParams returns the parameters libsodium's crypto_pwhash_str uses for
+// the given opslimit and memlimit (in bytes), e.g. SodiumOpsLimitModerate and
+// SodiumMemLimitModerate. Hashes generated with them are encoded in the PHC
+// format with a single lane, a 16 bytes salt and a 32 bytes key, so they can
+// be verified by crypto_pwhash_str_verify.
+func SodiumParams(opslimit, memlimit uint64) (*Params, error) {
+	if opslimit > math.MaxUint32 || memlimit/1024 > math.MaxUint32 {
+		return nil, ErrInvalidParams
+	}
+
+	p := &Params{
+		Memory:      uint32(memlimit / 1024),
+		Iterations:  uint32(opslimit),
+		Parallelism: 1,
+		SaltLength:  sodiumSaltLength,
+		KeyLength:   sodiumKeyLength,
+		Format:      FormatPHC,
+	}
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Presets mirroring libsodium's interactive, moderate and sensitive
+// crypto_pwhash profiles, as returned by SodiumParams for the matching
+// opslimit and memlimit, so deployments in different languages use the same
+// cost levels. InteractiveParams suits online logins, ModerateParams
+// operations that can take about a second, and SensitiveParams the derivation
+// of keys for highly sensitive, non-interactive operations.
+//
+// The presets are shared by every importer of the package, so mutating them
+// is deprecated: use Interactive, Moderate and Sensitive to get copies
+// instead.
+var (
+	InteractiveParams = Interactive()
+	ModerateParams    = Moderate()
+	SensitiveParams   = Sensitive()
+)
+
+// Interactive returns a copy of libsodium's interactive profile, suited to
+// online logins, which the caller is free to modify.
+func Interactive() *Params {
+	return sodiumPreset(SodiumOpsLimitInteractive, SodiumMemLimitInteractive)
+}
+
+// Moderate returns a copy of libsodium's moderate profile, suited to
+// operations that can take about a second, which the caller is free to
+// modify.
+func Moderate() *Params {
+	return sodiumPreset(SodiumOpsLimitModerate, SodiumMemLimitModerate)
+}
+
+// Sensitive returns a copy of libsodium's sensitive profile, suited to the
+// derivation of keys for highly sensitive, non-interactive operations, which
+// the caller is free to modify.
+func Sensitive() *Params {
+	return sodiumPreset(SodiumOpsLimitSensitive, SodiumMemLimitSensitive)
+}
+
+// sodiumPreset returns the parameters of a libsodium profile.
+func sodiumPreset(opsLimit, memLimit uint32) *Params {
+	return &Params{
+		Memory:      memLimit / 1024,
+		Iterations:  opsLimit,
+		Parallelism: 1,
+		SaltLength:  sodiumSaltLength,
+		KeyLength:   sodiumKeyLength,
+		Format:      FormatPHC,
+	}
+}
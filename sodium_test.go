@@ -0,0 +1,114 @@
+package argon2
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSodiumParams(t *testing.T) {
+	type args struct {
+		opslimit uint64
+		memlimit uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Params
+		wantErr bool
+	}{
+		{
+			name: "interactive",
+			args: args{SodiumOpsLimitInteractive, SodiumMemLimitInteractive},
+			want: &Params{Memory: 64 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC},
+		},
+		{
+			name: "moderate",
+			args: args{SodiumOpsLimitModerate, SodiumMemLimitModerate},
+			want: &Params{Memory: 256 * 1024, Iterations: 3, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC},
+		},
+		{
+			name: "sensitive",
+			args: args{SodiumOpsLimitSensitive, SodiumMemLimitSensitive},
+			want: &Params{Memory: 1024 * 1024, Iterations: 4, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC},
+		},
+		{
+			name:    "too small memlimit",
+			args:    args{SodiumOpsLimitInteractive, 8192},
+			wantErr: true,
+		},
+		{
+			name:    "too large opslimit",
+			args:    args{1 << 32, SodiumMemLimitInteractive},
+			wantErr: true,
+		},
+		{
+			name:    "too large memlimit",
+			args:    args{SodiumOpsLimitInteractive, 1 << 42},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SodiumParams(tt.args.opslimit, tt.args.memlimit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SodiumParams() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SodiumParams() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSodiumCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+	}{
+		{
+			// crypto_pwhash_str with crypto_pwhash_ALG_ARGON2ID13
+			name:     "argon2id",
+			hash:     []byte("$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"),
+			password: []byte("password"),
+		},
+		{
+			// crypto_pwhash_str with crypto_pwhash_ALG_ARGON2I13
+			name:     "argon2i",
+			hash:     []byte("$argon2i$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$wWKIMhR9lyDFvRz9YTZweHKfbftvj+qf+YFY4NeBbtA"),
+			password: []byte("password"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CompareHashAndPassword(tt.hash, tt.password); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestSodiumPresets(t *testing.T) {
+	tests := []struct {
+		name     string
+		preset   *Params
+		opslimit uint64
+		memlimit uint64
+	}{
+		{"interactive", Interactive(), SodiumOpsLimitInteractive, SodiumMemLimitInteractive},
+		{"moderate", Moderate(), SodiumOpsLimitModerate, SodiumMemLimitModerate},
+		{"sensitive", Sensitive(), SodiumOpsLimitSensitive, SodiumMemLimitSensitive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := SodiumParams(tt.opslimit, tt.memlimit)
+			if err != nil {
+				t.Fatalf("SodiumParams() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.preset, want) {
+				t.Errorf("preset = %+v, want %+v", tt.preset, want)
+			}
+		})
+	}
+}
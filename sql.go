@@ -0,0 +1,26 @@
+package argon2
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Scan implements the sql.Scanner interface, decoding a hash read from a
+// TEXT or BYTEA (BLOB) column, like UnmarshalText. Scanning NULL is an
+// error; scan nullable columns into a sql.NullString first.
+func (h *Hash) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return h.UnmarshalText([]byte(v))
+	case []byte:
+		return h.UnmarshalText(v)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into Hash", ErrInvalidHash, src)
+	}
+}
+
+// Value implements the driver.Valuer interface, storing the hash encoded as
+// text, as Encode does.
+func (h *Hash) Value() (driver.Value, error) {
+	return string(h.Encode()), nil
+}
@@ -0,0 +1,58 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHash_Scan(t *testing.T) {
+	const hash = "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+
+	tests := []struct {
+		name    string
+		src     interface{}
+		wantErr error
+	}{
+		{name: "text column", src: hash},
+		{name: "bytea column", src: []byte(hash)},
+		{name: "null", src: nil, wantErr: ErrInvalidHash},
+		{name: "integer", src: int64(42), wantErr: ErrInvalidHash},
+		{name: "invalid hash", src: "dwiehduwehc8wh", wantErr: ErrInvalidHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h Hash
+			err := h.Scan(tt.src)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			v, err := h.Value()
+			if err != nil {
+				t.Fatalf("Value() error = %v", err)
+			}
+			if v != hash {
+				t.Errorf("Value() got = %v, want %v", v, hash)
+			}
+		})
+	}
+}
+
+func TestHash_ScanCopiesBytes(t *testing.T) {
+	src := []byte("$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc")
+
+	var h Hash
+	if err := h.Scan(src); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	// drivers may reuse the buffer after Scan returns
+	for i := range src {
+		src[i] = 0
+	}
+	if err := h.Verify([]byte("password")); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package argon2
+
+import (
+	"strconv"
+)
+
+// Strength is the grade of the parameters of a hash against the current
+// recommendations.
+type Strength int
+
+const (
+	// Weak parameters are below the recommendations, their hashes should
+	// be rehashed as soon as possible.
+	Weak Strength = iota
+
+	// Acceptable parameters meet the OWASP recommendation for argon2id.
+	Acceptable
+
+	// Strong parameters meet the defaults of the package, 64 MiB of memory
+	// and 3 iterations with Argon2id, as RFC 9106 recommends for
+	// memory-constrained environments.
+	Strong
+)
+
+// String returns the name of the strength.
+func (s Strength) String() string {
+	switch s {
+	case Weak:
+		return "weak"
+	case Acceptable:
+		return "acceptable"
+	case Strong:
+		return "strong"
+	default:
+		return "Strength(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// Assessment is the grade of the parameters of a hash, with the reasons
+// it isn't higher.
+type Assessment struct {
+	Strength Strength
+	Reasons  []string
+}
+
+// AssessHash grades the parameters of the provided hash against the current
+// recommendations, e.g. for security dashboards reporting on the health of
+// a credential store.
+func AssessHash(encodedHash []byte) (*Assessment, error) {
+	p, _, _, err := decodeHash(encodedHash)
+	if err != nil {
+		return nil, err
+	}
+
+	a := p.Assess()
+	return &a, nil
+}
+
+// Assess grades the parameters against the current recommendations.
+// They are Weak if they use less memory or iterations than every
+// configuration of the OWASP recommendation, e.g. 19 MiB with 2 iterations
+// or 12 MiB with 3, use Argon2d or the legacy argon2 version, or a salt
+// or key shorter than 16 bytes. Otherwise they are
+// Strong if they use Argon2id and are not weaker than the built-in defaults,
+// and Acceptable if they don't.
+func (p *Params) Assess() Assessment {
+	var weak, acceptable []string
+
+	if !p.meetsOWASP() {
+		weak = append(weak, "the memory and iterations are below the OWASP recommendation")
+	}
+	if p.Variant == Argon2d {
+		weak = append(weak, "Argon2d is vulnerable to side-channel attacks")
+	}
+	if p.Version == legacyVersion {
+		weak = append(weak, "the legacy argon2 version 0x10 is vulnerable to tradeoff attacks")
+	}
+	if p.SaltLength < recSaltLength {
+		weak = append(weak, "the salt is shorter than 16 bytes")
+	}
+	if p.KeyLength < minKeyLength {
+		weak = append(weak, "the key is shorter than 16 bytes")
+	}
+	if len(weak) > 0 {
+		return Assessment{Strength: Weak, Reasons: weak}
+	}
+
+	if p.Variant != Argon2id {
+		acceptable = append(acceptable, "Argon2id is recommended over "+p.Variant.String())
+	}
+	if p.Memory < builtinDefaults.Memory {
+		acceptable = append(acceptable, "the memory is below 64 MiB")
+	}
+	if p.Iterations < builtinDefaults.Iterations {
+		acceptable = append(acceptable, "the iterations are below 3")
+	}
+	if p.KeyLength < builtinDefaults.KeyLength {
+		acceptable = append(acceptable, "the key is shorter than 32 bytes")
+	}
+	if len(acceptable) > 0 {
+		return Assessment{Strength: Acceptable, Reasons: acceptable}
+	}
+
+	return Assessment{Strength: Strong}
+}
@@ -0,0 +1,108 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestAssessHash(t *testing.T) {
+	tests := []struct {
+		name        string
+		hash        string
+		want        Strength
+		wantReasons int
+		wantErr     bool
+	}{
+		{
+			name: "default params",
+			hash: "argon2id$19$65536$3$2$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want: Strong,
+		},
+		{
+			name:        "owasp params",
+			hash:        "$argon2id$v=19$m=19456,t=2,p=1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:        Acceptable,
+			wantReasons: 2,
+		},
+		{
+			name:        "argon2i",
+			hash:        "$argon2i$v=19$m=65536,t=3,p=1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:        Acceptable,
+			wantReasons: 1,
+		},
+		{
+			name:        "owasp low memory configuration",
+			hash:        "$argon2id$v=19$m=12288,t=3,p=1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:        Acceptable,
+			wantReasons: 1,
+		},
+		{
+			name:        "below every owasp configuration",
+			hash:        "$argon2id$v=19$m=32768,t=1,p=1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:        Weak,
+			wantReasons: 1,
+		},
+		{
+			name:        "low cost",
+			hash:        "argon2id$19$8192$1$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:        Weak,
+			wantReasons: 1,
+		},
+		{
+			name:        "legacy version, argon2d and short salt",
+			hash:        "$argon2d$v=16$m=65536,t=3,p=1$YWJjZGVmZ2g$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU",
+			want:        Weak,
+			wantReasons: 3,
+		},
+		{
+			name:    "invalid hash",
+			hash:    "argon2id$19$65536$3$2$YWJjZGVmZ2hpamtsbW5vcA",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AssessHash([]byte(tt.hash))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AssessHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Strength != tt.want || len(got.Reasons) != tt.wantReasons {
+				t.Errorf("AssessHash() got = %v %q, want %v with %d reasons", got.Strength, got.Reasons, tt.want, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestAssess_Presets(t *testing.T) {
+	// OWASP2021 is kept for comparison with the superseded
+	// recommendation, it isn't a preset for new hashes
+	presets := map[string]*Params{
+		"defaults":    Defaults(),
+		"owasp":       OWASP(),
+		"owasp 2023":  OWASP2023(),
+		"interactive": Interactive(),
+		"moderate":    Moderate(),
+		"sensitive":   Sensitive(),
+		"php":         PHP(),
+		"node":        Node(),
+		"ruby":        Ruby(),
+		"low memory":  LowMemory(),
+	}
+	for name, p := range presets {
+		t.Run(name, func(t *testing.T) {
+			if got := p.Assess(); got.Strength < Acceptable {
+				t.Errorf("Assess() got = %v %q, want at least %v", got.Strength, got.Reasons, Acceptable)
+			}
+		})
+	}
+}
+
+func TestStrength_String(t *testing.T) {
+	for s, want := range map[Strength]string{Weak: "weak", Acceptable: "acceptable", Strong: "strong", Strength(7): "Strength(7)"} {
+		if got := s.String(); got != want {
+			t.Errorf("String() got = %s, want %s", got, want)
+		}
+	}
+}
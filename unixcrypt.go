@@ -0,0 +1,75 @@
+package argon2
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"strconv"
+
+	"github.com/andskur/argon2-hashing/internal/md5crypt"
+	"github.com/andskur/argon2-hashing/internal/shacrypt"
+)
+
+// isUnixCrypt reports whether the hash is an MD5-crypt ("$1$"), SHA256-crypt
+// ("$5$") or SHA512-crypt ("$6$") hash, as found in /etc/shadow.
+func isUnixCrypt(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte(md5crypt.MagicCrypt)) ||
+		bytes.HasPrefix(hash, []byte(shacrypt.MagicSHA256)) ||
+		bytes.HasPrefix(hash, []byte(shacrypt.MagicSHA512))
+}
+
+// compareUnixCrypt compares an MD5-crypt, SHA256-crypt or SHA512-crypt hash
+// with the password, by hashing the password with the same settings.
+func compareUnixCrypt(hash, password []byte) error {
+	// "$id$salt$hash", at least
+	if bytes.Count(hash, []byte("$")) < 3 {
+		return ErrInvalidHash
+	}
+
+	otherHash, err := unixCrypt(password, hash)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(hash, otherHash) == 1 {
+		return nil
+	}
+
+	return ErrMismatchedHashAndPassword
+}
+
+// unixCrypt returns the MD5-crypt, SHA256-crypt or SHA512-crypt hash of the
+// password with the settings of an existing hash: its "$id$salt" or
+// "$id$rounds=N$salt" prefix, or the whole hash.
+func unixCrypt(password, settings []byte) ([]byte, error) {
+	magic := string(settings[:3])
+	settings = settings[len(magic):]
+
+	rounds := 0
+	if magic != md5crypt.MagicCrypt && bytes.HasPrefix(settings, []byte("rounds=")) {
+		i := bytes.IndexByte(settings, '$')
+		if i < 0 {
+			return nil, ErrInvalidHash
+		}
+		r, err := strconv.ParseUint(string(settings[len("rounds="):i]), 10, 31)
+		if err != nil || r < 1 {
+			return nil, ErrInvalidHash
+		}
+		if r > maxLegacyCryptRounds {
+			return nil, limitError("rounds", r, maxLegacyCryptRounds)
+		}
+		rounds, settings = int(r), settings[i+1:]
+	}
+
+	salt := settings
+	if i := bytes.IndexByte(settings, '$'); i >= 0 {
+		salt = settings[:i]
+	}
+	if len(salt) == 0 {
+		return nil, ErrInvalidHash
+	}
+
+	if magic == md5crypt.MagicCrypt {
+		return md5crypt.Crypt(password, salt, magic), nil
+	}
+	return shacrypt.Crypt(password, salt, rounds, magic), nil
+}
@@ -0,0 +1,58 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestCompareUnixCrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     []byte
+		password []byte
+		wantErr  error
+	}{
+		{
+			name:     "md5-crypt",
+			hash:     []byte("$1$md5salt$Pj6kFcjobKpolQko11Vo20"),
+			password: []byte("legacy-password"),
+		},
+		{
+			name:     "sha512-crypt",
+			hash:     []byte("$6$shadowsalt$TnisS85/xIP2CrQ4q2tTzuYWga6aBhBGu2nFGm3bl6n56ZpqfZR.EAFWy8BXRDUhSHMvsFxqaK7QctsknbtJO0"),
+			password: []byte("legacy-password"),
+		},
+		{
+			name:     "sha256-crypt with rounds",
+			hash:     []byte("$5$rounds=1000$shadowsalt$UQsC1KCPL2raANS48uERUX8epmecoK0ZJsdALjDnkX7"),
+			password: []byte("legacy-password"),
+		},
+		{
+			name:     "mismatched password",
+			hash:     []byte("$6$shadowsalt$TnisS85/xIP2CrQ4q2tTzuYWga6aBhBGu2nFGm3bl6n56ZpqfZR.EAFWy8BXRDUhSHMvsFxqaK7QctsknbtJO0"),
+			password: []byte("legacy-password1"),
+			wantErr:  ErrMismatchedHashAndPassword,
+		},
+		{
+			name:     "invalid rounds",
+			hash:     []byte("$6$rounds=many$shadowsalt$TnisS85/xIP2CrQ4q2tTzuYWga6aBhBGu2nFGm3bl6n56ZpqfZR.EAFWy8BXRDUhSHMvsFxqaK7QctsknbtJO0"),
+			password: []byte("legacy-password"),
+			wantErr:  ErrInvalidHash,
+		},
+		{
+			name:     "missing hash",
+			hash:     []byte("$6$shadowsalt"),
+			password: []byte("legacy-password"),
+			wantErr:  ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !isUnixCrypt(tt.hash) {
+				t.Fatalf("isUnixCrypt() = false, want true")
+			}
+			if err := compareUnixCrypt(tt.hash, tt.password); err != tt.wantErr {
+				t.Errorf("compareUnixCrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package argon2
+
+import (
+	"sync"
+)
+
+// Validator enforces stricter floors than the minimums of Check, e.g. an
+// organization-wide policy of at least 64 MiB of memory and 2 iterations.
+// Zero fields aren't enforced.
+type Validator struct {
+	MinMemory      uint32    // The minimum amount of memory (kibibytes)
+	MinIterations  uint32    // The minimum number of iterations
+	MinParallelism uint8     // The minimum number of lanes
+	MinSaltLength  uint32    // The minimum salt length in bytes
+	MinKeyLength   uint32    // The minimum key length in bytes
+	Variants       []Variant // The allowed variants. Empty means any
+}
+
+// Validate checks that the parameters are valid, as Check does, and meet
+// the floors of the validator. Every violation is reported, e.g.
+// ErrMemoryTooSmall for a memory below the floor, and ErrUnknownAlgorithm
+// if the variant isn't allowed, joined as Check does.
+func (v *Validator) Validate(p *Params) error {
+	return joinErrors(append(p.check(), v.validate(p)...))
+}
+
+// validate returns the violations of the floors of the validator.
+func (v *Validator) validate(p *Params) []error {
+	var errs []error
+	if p.Memory < v.MinMemory {
+		errs = append(errs, ErrMemoryTooSmall)
+	}
+	if p.Iterations < v.MinIterations {
+		errs = append(errs, ErrIterationsTooSmall)
+	}
+	if p.Parallelism < v.MinParallelism {
+		errs = append(errs, ErrParallelismTooSmall)
+	}
+	if p.SaltLength < v.MinSaltLength {
+		errs = append(errs, ErrSaltTooShort)
+	}
+	if p.KeyLength < v.MinKeyLength {
+		errs = append(errs, ErrKeyTooShort)
+	}
+
+	if len(v.Variants) == 0 {
+		return errs
+	}
+	for _, variant := range v.Variants {
+		if p.Variant == variant {
+			return errs
+		}
+	}
+
+	return append(errs, ErrUnknownAlgorithm)
+}
+
+// ValidateEncodedHash checks the structure, version and parameters of the
+// provided hash without deriving a key, so import pipelines can vet stored
+// hashes cheaply. Unlike Check, it accepts the weaker parameters existing
+// hashes may have been generated with, as long as they are within the limits
+// of the spec, but it rejects excessive costs. Invalid parameters are
+// reported as a HashError wrapping ErrInvalidHash and the violations, e.g.
+// ErrMemoryTooLarge.
+func ValidateEncodedHash(encodedHash []byte) error {
+	_, _, _, err := decodeHash(encodedHash)
+	return err
+}
+
+// checkSpec returns the violations of the limits of the spec, which every
+// decoded hash must be within.
+func (p *Params) checkSpec() []error {
+	var errs []error
+	if p.Iterations < 1 {
+		errs = append(errs, ErrIterationsTooSmall)
+	}
+	if p.Parallelism < 1 {
+		errs = append(errs, ErrParallelismTooSmall)
+	} else if p.Memory < 8*uint32(p.Parallelism) {
+		errs = append(errs, ErrMemoryTooSmall)
+	}
+	if p.Memory > maxMemoryValue {
+		errs = append(errs, ErrMemoryTooLarge)
+	}
+	if p.SaltLength < minSaltLength {
+		errs = append(errs, ErrSaltTooShort)
+	}
+	if p.SaltLength > maxSaltLength {
+		errs = append(errs, ErrSaltTooLong)
+	}
+	if p.KeyLength < minTagLength {
+		errs = append(errs, ErrKeyTooShort)
+	}
+	if p.KeyLength > maxKeyLength {
+		errs = append(errs, ErrKeyTooLong)
+	}
+	return errs
+}
+
+var (
+	validatorMu sync.RWMutex
+	validator   *Validator
+)
+
+// SetValidator installs the validator Check, and so every function
+// generating hashes, enforces in addition to its own minimums, so weak
+// parameters are rejected across a codebase. Passing nil removes it.
+// Verifying existing hashes isn't affected.
+func SetValidator(v *Validator) {
+	validatorMu.Lock()
+	defer validatorMu.Unlock()
+	validator = v
+}
+
+// currentValidator returns the installed validator, if any.
+func currentValidator() *Validator {
+	validatorMu.RLock()
+	defer validatorMu.RUnlock()
+	return validator
+}
@@ -0,0 +1,133 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	v := &Validator{
+		MinMemory:     64 * 1024,
+		MinIterations: 2,
+		Variants:      []Variant{Argon2id},
+	}
+
+	tests := []struct {
+		name    string
+		params  *Params
+		wantErr error
+	}{
+		{
+			name:   "default params",
+			params: DefaultParams,
+		},
+		{
+			name:    "weak memory",
+			params:  OWASP(),
+			wantErr: ErrMemoryTooSmall,
+		},
+		{
+			name:    "weak iterations",
+			params:  &Params{Memory: 64 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+			wantErr: ErrIterationsTooSmall,
+		},
+		{
+			name:    "disallowed variant",
+			params:  &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 1, SaltLength: 16, KeyLength: 32, Variant: Argon2i},
+			wantErr: ErrUnknownAlgorithm,
+		},
+		{
+			name:    "invalid params",
+			params:  &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 1, SaltLength: 4, KeyLength: 32},
+			wantErr: ErrSaltTooShort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.Validate(tt.params); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetValidator(t *testing.T) {
+	weak := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hash, err := GenerateFromPassword([]byte("password"), weak)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	SetValidator(&Validator{MinMemory: 64 * 1024})
+	defer SetValidator(nil)
+
+	if _, err := GenerateFromPassword([]byte("password"), weak); err != ErrMemoryTooSmall {
+		t.Errorf("GenerateFromPassword() error = %v, wantErr %v", err, ErrMemoryTooSmall)
+	}
+	if _, err := GenerateFromPassword([]byte("password"), DefaultParams); err != nil {
+		t.Errorf("GenerateFromPassword() error = %v", err)
+	}
+	if err := CompareHashAndPassword(hash, []byte("password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+
+	SetValidator(nil)
+	if err := weak.Check(); err != nil {
+		t.Errorf("Check() error = %v after removing the validator", err)
+	}
+}
+
+func TestValidateEncodedHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    string
+		wantErr []error
+	}{
+		{
+			name: "valid hash",
+			hash: "argon2id$19$65536$3$2$6pAg+fVI2vB9uenAuOTK0A$VPg50e+vxRnvQ8dIFSg1HFNYHYcxEW+Dx47O6vipImU",
+		},
+		{
+			name: "weak, but valid hash",
+			hash: "$argon2i$v=16$m=4096,t=1,p=1$c29tZXNhbHQ$a2V5a2V5a2V5a2V5",
+		},
+		{
+			name:    "malformed hash",
+			hash:    "argon2id$19$65536$3",
+			wantErr: []error{ErrInvalidHash},
+		},
+		{
+			name:    "incompatible version",
+			hash:    "$argon2id$v=18$m=65536,t=3,p=2$c29tZXNhbHQ$a2V5a2V5a2V5a2V5",
+			wantErr: []error{ErrIncompatibleVersion},
+		},
+		{
+			name:    "excessive memory",
+			hash:    "$argon2id$v=19$m=4294967295,t=3,p=2$c29tZXNhbHQ$a2V5a2V5a2V5a2V5",
+			wantErr: []error{ErrInvalidHash, ErrMemoryTooLarge},
+		},
+		{
+			name:    "no iterations and short salt",
+			hash:    "$argon2id$v=19$m=65536,t=0,p=2$c2FsdA$a2V5a2V5a2V5a2V5",
+			wantErr: []error{ErrInvalidHash, ErrIterationsTooSmall, ErrSaltTooShort},
+		},
+		{
+			name:    "too little memory per lane",
+			hash:    "$argon2id$v=19$m=16,t=1,p=4$c29tZXNhbHQ$a2V5a2V5a2V5a2V5",
+			wantErr: []error{ErrInvalidHash, ErrMemoryTooSmall},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEncodedHash([]byte(tt.hash))
+			if (err != nil) != (len(tt.wantErr) > 0) {
+				t.Fatalf("ValidateEncodedHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("ValidateEncodedHash() error = %v, want %v", err, want)
+				}
+			}
+		})
+	}
+}
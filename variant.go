@@ -0,0 +1,100 @@
+package argon2
+
+import (
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/andskur/argon2-hashing/internal/core"
+)
+
+// Variant identifies the argon2 algorithm variant used to derive a key.
+// It is encoded as the algorithm identifier of a hash, e.g. "argon2id".
+type Variant int
+
+const (
+	// Argon2id is the hybrid variant of argon2, recommended for password hashing.
+	Argon2id Variant = iota
+
+	// Argon2d uses data-dependent memory access, which maximizes resistance
+	// to GPU cracking attacks but makes it vulnerable to side-channel attacks.
+	// It is suitable for non-interactive key derivation, not for passwords
+	// hashed on shared hardware.
+	Argon2d
+
+	// Argon2i uses data-independent memory access, which resists
+	// side-channel attacks. It is supported mainly for interoperability,
+	// e.g. with hashes produced by libsodium's argon2i algorithm.
+	Argon2i
+)
+
+// variantNames maps the supported variants to their hash identifiers.
+var variantNames = map[Variant]string{
+	Argon2id: "argon2id",
+	Argon2d:  "argon2d",
+	Argon2i:  "argon2i",
+}
+
+// String returns the hash identifier of the variant.
+func (v Variant) String() string {
+	if name, ok := variantNames[v]; ok {
+		return name
+	}
+	return "Variant(" + strconv.Itoa(int(v)) + ")"
+}
+
+// parseVariant returns the variant identified by the given hash identifier.
+// It returns ErrUnknownAlgorithm if the identifier is not supported.
+func parseVariant(id []byte) (Variant, error) {
+	for v, name := range variantNames {
+		if name == string(id) {
+			return v, nil
+		}
+	}
+	return 0, ErrUnknownAlgorithm
+}
+
+// deriveKey derives a key from the password, pre-hashed if the parameters
+// say so, salt, optional secret and associated data of the parameters with
+// the given parameters, dispatching to the implementation of the variant
+// and version.
+func (v Variant) deriveKey(password, salt, secret []byte, p *Params) []byte {
+	if p.Normalization != NoNormalization {
+		password = p.Normalization.apply(password)
+	}
+	if p.PreHash != NoPreHash {
+		password = p.PreHash.apply(password)
+		defer wipe(password)
+	}
+
+	version := p.Version
+	if version == 0 {
+		version = argon2.Version
+	}
+
+	// golang.org/x/crypto/argon2 implements only the current version
+	// of Argon2id and Argon2i, without a secret or associated data
+	native := version == argon2.Version && len(secret) == 0 && p.Data == ""
+	if v == Argon2id && native {
+		return argon2.IDKey(password, salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	}
+	if v == Argon2i && native {
+		return argon2.Key(password, salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	}
+
+	return core.DeriveKey(v.mode(), version, password, salt, secret, []byte(p.Data), p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+}
+
+// mode returns the internal/core mode of the variant.
+func (v Variant) mode() core.Mode {
+	switch v {
+	case Argon2id:
+		return core.Argon2id
+	case Argon2d:
+		return core.Argon2d
+	case Argon2i:
+		return core.Argon2i
+	default:
+		panic("argon2: unknown variant " + v.String())
+	}
+}
@@ -0,0 +1,139 @@
+package argon2
+
+import (
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrParamsVersionExists is returned when registering a params version
+// which is already registered.
+var ErrParamsVersionExists = errors.New("argon2: the params version is already registered")
+
+// ParamsRegistry maps small integer params versions, e.g. 1, 2, 3, to the
+// parameters of each generation, so applications can roll parameters
+// forward. The version of the parameters a hash is generated with is
+// recorded alongside the hash, e.g. in a column next to it, so the users
+// still needing a rehash are known exactly: the ones with a version below
+// Current. The zero value is an empty registry ready to use, and it is safe
+// for concurrent use.
+type ParamsRegistry struct {
+	mu       sync.RWMutex
+	versions map[uint32]Params
+	current  uint32
+}
+
+// Register registers the parameters of the version after validating them
+// with Check. The highest registered version is the current one. Versions
+// can't be re-registered, as hashes refer to them, so ErrParamsVersionExists
+// is returned for a registered version, and ErrInvalidParams for nil
+// parameters.
+func (r *ParamsRegistry) Register(version uint32, p *Params) error {
+	if p == nil {
+		return ErrInvalidParams
+	}
+	if err := p.Check(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.versions[version]; ok {
+		return ErrParamsVersionExists
+	}
+	if r.versions == nil {
+		r.versions = make(map[uint32]Params)
+	}
+	r.versions[version] = *p
+	if len(r.versions) == 1 || version > r.current {
+		r.current = version
+	}
+
+	return nil
+}
+
+// Params returns a copy of the parameters of the version, and whether
+// it is registered.
+func (r *ParamsRegistry) Params(version uint32) (*Params, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.versions[version]
+	if !ok {
+		return nil, false
+	}
+	return &p, true
+}
+
+// Current returns the current, highest registered, version and a copy of
+// its parameters. It returns ErrInvalidParams if no version is registered.
+func (r *ParamsRegistry) Current() (uint32, *Params, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.versions[r.current]
+	if !ok {
+		return 0, nil, ErrInvalidParams
+	}
+	return r.current, &p, nil
+}
+
+// GenerateFromPassword returns the hash of the password generated with the
+// current parameters, and their version to record alongside the hash.
+func (r *ParamsRegistry) GenerateFromPassword(password []byte) (hash []byte, version uint32, err error) {
+	version, p, err := r.Current()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hash, err = GenerateFromPassword(password, p)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hash, version, nil
+}
+
+// NeedsRehash reports whether a hash of the version should be regenerated
+// with the current parameters, i.e. whether the version is below the current one.
+func (r *ParamsRegistry) NeedsRehash(version uint32) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return version < r.current
+}
+
+// CompareAndUpdate compares the hash of the recorded version with the
+// password, like CompareHashAndPassword, and on success returns a fresh hash
+// generated with the current parameters and its version if the hash needs
+// a rehash, as reported by NeedsRehash, or nil and the version provided
+// otherwise.
+func (r *ParamsRegistry) CompareAndUpdate(hash, password []byte, version uint32) (newHash []byte, newVersion uint32, err error) {
+	defer padVerify(time.Now())
+
+	if err := checkPassword(password); err != nil {
+		return nil, version, err
+	}
+
+	p, salt, key, err := decodeHash(hash)
+	if err != nil {
+		return nil, version, err
+	}
+	if err := p.checkInputs(nil, nil); err != nil {
+		return nil, version, err
+	}
+
+	otherKey := p.Variant.deriveKey(password, salt, nil, p)
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return nil, version, ErrMismatchedHashAndPassword
+	}
+	notifyWeakHash(p)
+
+	// A password below the minimum length keeps its hash rather than
+	// failing the login
+	if !r.NeedsRehash(version) || checkNewPassword(password) != nil {
+		return nil, version, nil
+	}
+
+	return r.GenerateFromPassword(password)
+}
@@ -0,0 +1,63 @@
+package argon2
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParamsRegistry(t *testing.T) {
+	var r ParamsRegistry
+	if _, _, err := r.GenerateFromPassword([]byte("password")); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("GenerateFromPassword() error = %v, wantErr %v", err, ErrInvalidParams)
+	}
+
+	v1 := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	v2 := &Params{Memory: 16 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32, Format: FormatPHC}
+	if err := r.Register(1, v1); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	hash, version, err := r.GenerateFromPassword([]byte("password"))
+	if err != nil || version != 1 {
+		t.Fatalf("GenerateFromPassword() version = %d, error = %v, want version 1", version, err)
+	}
+
+	if err := r.Register(2, v2); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := r.Register(2, v1); err != ErrParamsVersionExists {
+		t.Errorf("Register() error = %v, wantErr %v", err, ErrParamsVersionExists)
+	}
+	if err := r.Register(3, &Params{Memory: 1024}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Register() error = %v, wantErr %v", err, ErrInvalidParams)
+	}
+	if err := r.Register(3, nil); err != ErrInvalidParams {
+		t.Errorf("Register() error = %v, wantErr %v", err, ErrInvalidParams)
+	}
+	if current, p, err := r.Current(); err != nil || current != 2 || *p != *v2 {
+		t.Errorf("Current() got = %d, %v, error = %v, want version 2", current, p, err)
+	}
+	if p, ok := r.Params(1); !ok || *p != *v1 {
+		t.Errorf("Params() got = %v, %v, want version 1", p, ok)
+	}
+	if _, ok := r.Params(3); ok {
+		t.Errorf("Params() got an unregistered version")
+	}
+	if !r.NeedsRehash(1) || r.NeedsRehash(2) {
+		t.Errorf("NeedsRehash() got = %v, %v, want true, false", r.NeedsRehash(1), r.NeedsRehash(2))
+	}
+
+	if _, _, err := r.CompareAndUpdate(hash, []byte("passw0rd"), 1); err != ErrMismatchedHashAndPassword {
+		t.Errorf("CompareAndUpdate() error = %v, wantErr %v", err, ErrMismatchedHashAndPassword)
+	}
+	newHash, newVersion, err := r.CompareAndUpdate(hash, []byte("password"), 1)
+	if err != nil || newHash == nil || newVersion != 2 {
+		t.Fatalf("CompareAndUpdate() got = %s, %d, error = %v, want a version 2 hash", newHash, newVersion, err)
+	}
+	if err := CompareHashAndPassword(newHash, []byte("password")); err != nil {
+		t.Errorf("CompareHashAndPassword() error = %v", err)
+	}
+	if newHash, newVersion, err := r.CompareAndUpdate(newHash, []byte("password"), newVersion); err != nil || newHash != nil || newVersion != 2 {
+		t.Errorf("CompareAndUpdate() got = %s, %d, error = %v, want no update", newHash, newVersion, err)
+	}
+}
@@ -0,0 +1,37 @@
+package argon2
+
+import (
+	"sync/atomic"
+)
+
+// weakHashHook is the hook installed with SetWeakHashHook.
+type weakHashHook struct {
+	baseline Params
+	fn       func(hp *Params)
+}
+
+var weakHook atomic.Pointer[weakHashHook]
+
+// SetWeakHashHook installs fn to be called whenever a password is verified
+// successfully against a hash whose parameters are weaker than the baseline,
+// as reported by WeakerThan, with a copy of the decoded parameters of the
+// hash. It lets teams meter how many legacy hashes remain in the wild, e.g.
+// by incrementing a metric labeled with the parameters. fn is called
+// synchronously, so it should return quickly. A nil fn removes the hook.
+func SetWeakHashHook(baseline *Params, fn func(hp *Params)) {
+	if fn == nil {
+		weakHook.Store(nil)
+		return
+	}
+	weakHook.Store(&weakHashHook{baseline: *orDefault(baseline), fn: fn})
+}
+
+// notifyWeakHash calls the installed hook if the parameters of a
+// successfully verified hash are weaker than its baseline.
+func notifyWeakHash(hp *Params) {
+	h := weakHook.Load()
+	if h == nil || !hp.WeakerThan(&h.baseline) {
+		return
+	}
+	h.fn(hp.Clone())
+}
@@ -0,0 +1,56 @@
+package argon2
+
+import (
+	"testing"
+)
+
+func TestSetWeakHashHook(t *testing.T) {
+	weak := []byte("argon2id$19$8192$1$1$YWJjZGVmZ2hpamtsbW5vcA$CKzX2QSwkZpR4ShoxNMfbaYVZMkpw2pNv0IBjKsRqLU")
+	weakHash, err := GenerateFromPassword([]byte("password"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	strongHash, err := GenerateFromPassword([]byte("password"), OWASP())
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	var got []*Params
+	SetWeakHashHook(OWASP(), func(hp *Params) { got = append(got, hp) })
+	defer SetWeakHashHook(nil, nil)
+
+	if err := CompareHashAndPassword(weakHash, []byte("password")); err != nil {
+		t.Fatalf("CompareHashAndPassword() error = %v", err)
+	}
+	if err := CompareHashAndPassword(strongHash, []byte("password")); err != nil {
+		t.Fatalf("CompareHashAndPassword() error = %v", err)
+	}
+	if err := CompareHashAndPassword(weak, []byte("wrong")); err == nil {
+		t.Fatalf("CompareHashAndPassword() error = nil for a wrong password")
+	}
+	if _, err := CompareAndUpdate(weakHash, []byte("password"), OWASP()); err != nil {
+		t.Fatalf("CompareAndUpdate() error = %v", err)
+	}
+	weakCrypt, err := GenerateCrypt([]byte("password"), &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err != nil {
+		t.Fatalf("GenerateCrypt() error = %v", err)
+	}
+	if err := CompareCrypt(weakCrypt, []byte("password")); err != nil {
+		t.Fatalf("CompareCrypt() error = %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("hook called %d times, want 3", len(got))
+	}
+	if got[0].Memory != 8*1024 || got[0].Iterations != 1 {
+		t.Errorf("hook got = %v, want the parameters of the weak hash", got[0])
+	}
+
+	SetWeakHashHook(nil, nil)
+	if err := CompareHashAndPassword(weakHash, []byte("password")); err != nil {
+		t.Fatalf("CompareHashAndPassword() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("hook called after being removed")
+	}
+}
@@ -0,0 +1,91 @@
+package argon2
+
+import (
+	"bytes"
+
+	"github.com/andskur/argon2-hashing/internal/bcryptcore"
+)
+
+// wrapPrefix starts the hashes generated by WrapHash.
+const wrapPrefix = "{WRAP:"
+
+// bcryptHashSize is the length of the bcrypt hash that follows the settings.
+const bcryptHashSize = 31
+
+// WrapHash hardens a legacy hash offline, without knowing the password, by
+// deriving an argon2 key from the legacy hash with the parameters provided.
+// The wrapped hash records the settings (scheme, cost and salt) of the
+// legacy hash, so it can be recomputed from the password when verifying:
+//
+//	{WRAP:$2b$10$N9qo8uLOickgx2ZMRZoMye}$argon2id$v=19$m=65536,t=3,p=2$salt$key
+//
+// The argon2 hash is always encoded in the PHC format. The supported legacy
+// hashes are bcrypt and Unix crypt ("$1$", "$5$" and "$6$") ones. Wrapped
+// hashes are verified by VerifyAnyScheme, and migrated to plain argon2 hashes
+// by VerifyAndMigrate. A nil p stands for the Defaults.
+func WrapHash(legacyHash []byte, p *Params) ([]byte, error) {
+	var settings []byte
+	switch {
+	case isBcrypt(legacyHash):
+		if len(legacyHash) != len("$2b$10$N9qo8uLOickgx2ZMRZoMye")+bcryptHashSize {
+			return nil, ErrInvalidHash
+		}
+		settings = legacyHash[:len(legacyHash)-bcryptHashSize]
+	case isUnixCrypt(legacyHash):
+		i := bytes.LastIndexByte(legacyHash, '$')
+		if bytes.Count(legacyHash, []byte("$")) < 3 || i == len(legacyHash)-1 {
+			return nil, ErrInvalidHash
+		}
+		settings = legacyHash[:i]
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+
+	phc := *orDefault(p)
+	phc.Format = FormatPHC
+
+	dst := make([]byte, 0, len(wrapPrefix)+len(settings)+1+128)
+	dst = append(dst, wrapPrefix...)
+	dst = append(dst, settings...)
+	dst = append(dst, '}')
+	return appendDerived(dst, legacyHash, nil, &phc)
+}
+
+// isWrapped reports whether the hash was generated by WrapHash.
+func isWrapped(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte(wrapPrefix))
+}
+
+// compareWrapped compares a hash generated by WrapHash with the password, by
+// recomputing the legacy hash from the password and the recorded settings,
+// and comparing it with the argon2 hash.
+func compareWrapped(hash, password []byte) error {
+	hash = hash[len(wrapPrefix):]
+	i := bytes.IndexByte(hash, '}')
+	if i < 0 {
+		return ErrInvalidHash
+	}
+	settings, outer := hash[:i], hash[i+1:]
+
+	var legacyHash []byte
+	var err error
+	switch {
+	case isBcrypt(settings):
+		if err := checkBcryptCost(settings); err != nil {
+			return err
+		}
+		legacyHash, err = bcryptcore.Crypt(password, settings)
+		if err != nil {
+			return ErrInvalidHash
+		}
+	case isUnixCrypt(settings):
+		legacyHash, err = unixCrypt(password, settings)
+		if err != nil {
+			return err
+		}
+	default:
+		return ErrUnknownAlgorithm
+	}
+
+	return CompareHashAndPassword(outer, legacyHash)
+}
@@ -0,0 +1,127 @@
+package argon2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrapHash(t *testing.T) {
+	p := &Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	tests := []struct {
+		name         string
+		legacyHash   []byte
+		password     []byte
+		wantSettings string
+		wantErr      error
+	}{
+		{
+			name:         "bcrypt",
+			legacyHash:   []byte("$2b$05$abcdefghijklmnopqrstuuHIrMEWpUCQe2YqFR3sXwQ75u4od..9q"),
+			password:     []byte("pw"),
+			wantSettings: "$2b$05$abcdefghijklmnopqrstuu",
+		},
+		{
+			name:         "md5-crypt",
+			legacyHash:   []byte("$1$md5salt$Pj6kFcjobKpolQko11Vo20"),
+			password:     []byte("legacy-password"),
+			wantSettings: "$1$md5salt",
+		},
+		{
+			name:         "sha256-crypt with rounds",
+			legacyHash:   []byte("$5$rounds=1000$shadowsalt$UQsC1KCPL2raANS48uERUX8epmecoK0ZJsdALjDnkX7"),
+			password:     []byte("legacy-password"),
+			wantSettings: "$5$rounds=1000$shadowsalt",
+		},
+		{
+			name:       "truncated bcrypt",
+			legacyHash: []byte("$2b$05$abcdefghijklmnopqrstuu"),
+			wantErr:    ErrInvalidHash,
+		},
+		{
+			name:       "sha512-crypt without hash",
+			legacyHash: []byte("$6$shadowsalt$"),
+			wantErr:    ErrInvalidHash,
+		},
+		{
+			name:       "argon2",
+			legacyHash: []byte("$argon2id$v=19$m=65536,t=3,p=4$ZG90bmV0LXNhbHQtMDAxNg$KcOjiJfeGMdAncWZegKy5mj1b3UjtmERelO2FG3320w"),
+			wantErr:    ErrUnknownAlgorithm,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WrapHash(tt.legacyHash, p)
+			if err != tt.wantErr {
+				t.Fatalf("WrapHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			prefix := "{WRAP:" + tt.wantSettings + "}$argon2id$v=19$m=8192,t=1,p=1$"
+			if !bytes.HasPrefix(got, []byte(prefix)) {
+				t.Errorf("WrapHash() got = %s, want prefix %s", got, prefix)
+			}
+			if err := VerifyAnyScheme(got, tt.password); err != nil {
+				t.Errorf("VerifyAnyScheme() error = %v", err)
+			}
+			if err := VerifyAnyScheme(got, []byte("wrong-password")); err != ErrMismatchedHashAndPassword {
+				t.Errorf("VerifyAnyScheme() error = %v, want %v", err, ErrMismatchedHashAndPassword)
+			}
+
+			// wrapped hashes are migrated to plain argon2 hashes
+			newHash, err := VerifyAndMigrate(got, tt.password, p)
+			if err != nil {
+				t.Fatalf("VerifyAndMigrate() error = %v", err)
+			}
+			if err := CompareHashAndPassword(newHash, tt.password); err != nil {
+				t.Errorf("CompareHashAndPassword() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestWrapHash_NilParams(t *testing.T) {
+	hash, err := WrapHash([]byte("$1$md5salt$Pj6kFcjobKpolQko11Vo20"), nil)
+	if err != nil {
+		t.Fatalf("WrapHash() error = %v", err)
+	}
+	if !bytes.Contains(hash, []byte("$m=65536,t=3,p=2$")) {
+		t.Errorf("WrapHash() got = %s, want the Defaults", hash)
+	}
+	if err := VerifyAnyScheme(hash, []byte("legacy-password")); err != nil {
+		t.Errorf("VerifyAnyScheme() error = %v", err)
+	}
+}
+
+func TestCompareWrapped(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    []byte
+		wantErr error
+	}{
+		{
+			name:    "missing closing brace",
+			hash:    []byte("{WRAP:$2b$05$abcdefghijklmnopqrstuu$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5"),
+			wantErr: ErrInvalidHash,
+		},
+		{
+			name:    "unknown inner scheme",
+			hash:    []byte("{WRAP:$3$salt}$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5"),
+			wantErr: ErrUnknownAlgorithm,
+		},
+		{
+			name:    "invalid bcrypt settings",
+			hash:    []byte("{WRAP:$2b$99$abcdefghijklmnopqrstuu}$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHQ$a2V5"),
+			wantErr: ErrInvalidHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := compareWrapped(tt.hash, []byte("pw")); err != tt.wantErr {
+				t.Errorf("compareWrapped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}